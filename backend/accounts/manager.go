@@ -1,7 +1,6 @@
 package accounts
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -47,18 +46,23 @@ type Manager struct {
 	accounts    map[int]*Account
 	keyIndex    map[string]*Account
 	nextID      int
-	storePath   string
+	store       Store
 	profileBase string
 }
 
-// NewManager creates a manager with persistence.
+// NewManager creates a manager persisted to a JSON file.
 // storePath: JSON file path. profileBase: base dir for user data dir (per account).
 func NewManager(storePath, profileBase string) (*Manager, error) {
+	return NewManagerWithStore(NewJSONStore(storePath), profileBase)
+}
+
+// NewManagerWithStore creates a manager on top of an arbitrary Store.
+func NewManagerWithStore(store Store, profileBase string) (*Manager, error) {
 	m := &Manager{
 		accounts:    map[int]*Account{},
 		keyIndex:    map[string]*Account{},
 		nextID:      1,
-		storePath:   storePath,
+		store:       store,
 		profileBase: profileBase,
 	}
 	if err := m.load(); err != nil {
@@ -98,7 +102,7 @@ func (m *Manager) Create(proxy, name string) (*Account, error) {
 
 	m.accounts[id] = acc
 	m.keyIndex[key] = acc
-	return acc, m.saveLocked()
+	return acc, m.saveLocked(acc)
 }
 
 // Get returns account by id.
@@ -148,7 +152,7 @@ func (m *Manager) UpdateProxy(id int, proxy string, name string) (*Account, erro
 		acc.Name = name
 	}
 	acc.LoggedIn = false
-	return acc, m.saveLocked()
+	return acc, m.saveLocked(acc)
 }
 
 // SetName sets name for account.
@@ -160,7 +164,7 @@ func (m *Manager) SetName(id int, name string) (*Account, error) {
 		return nil, errors.Errorf("account %d not found", id)
 	}
 	acc.Name = name
-	return acc, m.saveLocked()
+	return acc, m.saveLocked(acc)
 }
 
 // ApplyProxyConfig sets structured proxy config (and raw if provided).
@@ -187,7 +191,7 @@ func (m *Manager) ApplyProxyConfig(id int, cfg ProxyConfig) (*Account, error) {
 	acc.ProxyUser = cfg.User
 	acc.ProxyPass = cfg.Pass
 	acc.LoggedIn = false
-	return acc, m.saveLocked()
+	return acc, m.saveLocked(acc)
 }
 
 // MarkLoggedIn updates logged-in status and timestamp.
@@ -197,7 +201,18 @@ func (m *Manager) MarkLoggedIn(key string) {
 	if acc, ok := m.keyIndex[key]; ok {
 		acc.LoggedIn = true
 		acc.LastLogin = time.Now()
-		_ = m.saveLocked()
+		_ = m.saveLocked(acc)
+		_ = m.store.RecordLogin(key, acc.LastLogin)
+	}
+}
+
+// MarkLoggedOut clears the logged-in flag, e.g. after the cookies expired or were deleted.
+func (m *Manager) MarkLoggedOut(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if acc, ok := m.keyIndex[key]; ok && acc.LoggedIn {
+		acc.LoggedIn = false
+		_ = m.saveLocked(acc)
 	}
 }
 
@@ -212,54 +227,25 @@ func (m *Manager) Delete(id int) error {
 	delete(m.accounts, id)
 	delete(m.keyIndex, acc.Key)
 
-	// Remove cookie/profile paths.
-	_ = os.Remove(acc.CookiePath)
+	// Remove cookies and profile path.
+	_ = cookies.NewLoadCookie(acc.CookiePath).DeleteCookies()
 	_ = os.RemoveAll(acc.ProfilePath)
-	return m.saveLocked()
+	return m.store.DeleteAccount(id, m.nextID)
 }
 
-func (m *Manager) saveLocked() error {
-	if m.storePath == "" {
-		return nil
-	}
-	if err := os.MkdirAll(filepath.Dir(m.storePath), 0o755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(struct {
-		NextID   int        `json:"next_id"`
-		Accounts []*Account `json:"accounts"`
-	}{
-		NextID:   m.nextID,
-		Accounts: collectAccounts(m.accounts),
-	}, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(m.storePath, data, 0o644)
+func (m *Manager) saveLocked(acc *Account) error {
+	return m.store.SaveAccount(acc, m.nextID)
 }
 
 func (m *Manager) load() error {
-	if m.storePath == "" {
-		return nil
-	}
-	data, err := os.ReadFile(m.storePath)
+	nextID, list, err := m.store.Load()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return err
-	}
-	var payload struct {
-		NextID   int        `json:"next_id"`
-		Accounts []*Account `json:"accounts"`
-	}
-	if err := json.Unmarshal(data, &payload); err != nil {
 		return err
 	}
-	if payload.NextID > 0 {
-		m.nextID = payload.NextID
+	if nextID > 0 {
+		m.nextID = nextID
 	}
-	for _, acc := range payload.Accounts {
+	for _, acc := range list {
 		m.accounts[acc.ID] = acc
 		m.keyIndex[acc.Key] = acc
 		if acc.ID >= m.nextID {
@@ -269,6 +255,35 @@ func (m *Manager) load() error {
 	return nil
 }
 
+// Save rewrites every account with the current encryption settings, e.g. after a key rotation.
+func (m *Manager) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, acc := range m.accounts {
+		if err := m.saveLocked(acc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoginHistory returns up to limit most recent successful logins of the account, newest first.
+func (m *Manager) LoginHistory(key string, limit int) ([]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.keyIndex[key]; !ok {
+		return nil, errors.Errorf("account %s not found", key)
+	}
+	return m.store.LoginHistory(key, limit)
+}
+
+// Close releases the underlying store.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.store.Close()
+}
+
 func collectAccounts(m map[int]*Account) []*Account {
 	out := make([]*Account, 0, len(m))
 	for _, v := range m {
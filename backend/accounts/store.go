@@ -0,0 +1,199 @@
+package accounts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
+)
+
+// Store persists accounts and their login history. Implementations do not need
+// to be safe for concurrent use; Manager serializes all calls.
+type Store interface {
+	// Load returns the next account ID and all stored accounts.
+	Load() (nextID int, accounts []*Account, err error)
+	// SaveAccount inserts or updates one account together with the ID counter.
+	SaveAccount(acc *Account, nextID int) error
+	// DeleteAccount removes an account and its login history.
+	DeleteAccount(id int, nextID int) error
+	// RecordLogin appends a successful login of the account.
+	RecordLogin(key string, at time.Time) error
+	// LoginHistory returns up to limit most recent logins, newest first.
+	LoginHistory(key string, limit int) ([]time.Time, error)
+	Close() error
+}
+
+// maxJSONLoginHistory caps the per-account login history kept in the JSON file.
+const maxJSONLoginHistory = 20
+
+// jsonStore keeps everything in a single JSON file that is rewritten on every change.
+type jsonStore struct {
+	path     string
+	nextID   int
+	accounts map[int]*Account
+	history  map[string][]time.Time
+}
+
+type jsonPayload struct {
+	NextID       int                    `json:"next_id"`
+	Accounts     []*Account             `json:"accounts"`
+	LoginHistory map[string][]time.Time `json:"login_history,omitempty"`
+}
+
+// NewJSONStore returns the file based store used by default (accounts.json).
+// An empty path keeps everything in memory.
+func NewJSONStore(path string) Store {
+	return &jsonStore{
+		path:     path,
+		nextID:   1,
+		accounts: map[int]*Account{},
+		history:  map[string][]time.Time{},
+	}
+}
+
+func (s *jsonStore) Load() (int, []*Account, error) {
+	if s.path == "" {
+		return s.nextID, nil, nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s.nextID, nil, nil
+		}
+		return 0, nil, err
+	}
+	var payload jsonPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return 0, nil, err
+	}
+
+	box := secret.Default()
+	plaintext := false
+	for _, acc := range payload.Accounts {
+		if (acc.Proxy != "" && !secret.IsSealed([]byte(acc.Proxy))) ||
+			(acc.ProxyPass != "" && !secret.IsSealed([]byte(acc.ProxyPass))) {
+			plaintext = true
+		}
+		if err := openAccount(acc, box); err != nil {
+			return 0, nil, err
+		}
+		s.accounts[acc.ID] = acc
+	}
+	if payload.NextID > 0 {
+		s.nextID = payload.NextID
+	}
+	if payload.LoginHistory != nil {
+		s.history = payload.LoginHistory
+	}
+	if box != nil && plaintext {
+		// Migrate a plaintext store written before encryption was enabled.
+		if err := s.save(); err != nil {
+			return 0, nil, err
+		}
+	}
+	return s.nextID, payload.Accounts, nil
+}
+
+func (s *jsonStore) SaveAccount(acc *Account, nextID int) error {
+	s.accounts[acc.ID] = acc
+	s.nextID = nextID
+	return s.save()
+}
+
+func (s *jsonStore) DeleteAccount(id int, nextID int) error {
+	if acc, ok := s.accounts[id]; ok {
+		delete(s.history, acc.Key)
+	}
+	delete(s.accounts, id)
+	s.nextID = nextID
+	return s.save()
+}
+
+func (s *jsonStore) RecordLogin(key string, at time.Time) error {
+	h := append([]time.Time{at}, s.history[key]...)
+	if len(h) > maxJSONLoginHistory {
+		h = h[:maxJSONLoginHistory]
+	}
+	s.history[key] = h
+	return s.save()
+}
+
+func (s *jsonStore) LoginHistory(key string, limit int) ([]time.Time, error) {
+	h := s.history[key]
+	if limit > 0 && len(h) > limit {
+		h = h[:limit]
+	}
+	return append([]time.Time(nil), h...), nil
+}
+
+func (s *jsonStore) Close() error {
+	return nil
+}
+
+func (s *jsonStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	accounts, err := sealAccounts(collectAccounts(s.accounts), secret.Default())
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(jsonPayload{
+		NextID:       s.nextID,
+		Accounts:     accounts,
+		LoginHistory: s.history,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// SealAccount returns a copy with proxy credentials encrypted by box. The raw
+// proxy URL may embed user:pass, so it is encrypted as well. Stores call this
+// before writing an account.
+func SealAccount(acc *Account, box *secret.Box) (*Account, error) {
+	copyAcc := *acc
+	var err error
+	if copyAcc.Proxy, err = box.SealString(acc.Proxy); err != nil {
+		return nil, err
+	}
+	if copyAcc.ProxyPass, err = box.SealString(acc.ProxyPass); err != nil {
+		return nil, err
+	}
+	return &copyAcc, nil
+}
+
+// OpenAccount decrypts proxy credentials written by SealAccount in place.
+func OpenAccount(acc *Account, box *secret.Box) error {
+	return openAccount(acc, box)
+}
+
+func openAccount(acc *Account, box *secret.Box) error {
+	var err error
+	if acc.Proxy, err = box.OpenString(acc.Proxy); err != nil {
+		return errors.Wrapf(err, "decrypt proxy of %s", acc.Key)
+	}
+	if acc.ProxyPass, err = box.OpenString(acc.ProxyPass); err != nil {
+		return errors.Wrapf(err, "decrypt proxy password of %s", acc.Key)
+	}
+	return nil
+}
+
+func sealAccounts(list []*Account, box *secret.Box) ([]*Account, error) {
+	out := make([]*Account, 0, len(list))
+	for _, acc := range list {
+		sealed, err := SealAccount(acc, box)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sealed)
+	}
+	return out, nil
+}
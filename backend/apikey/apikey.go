@@ -0,0 +1,212 @@
+// Package apikey 实现 HTTP API 与 MCP 端点的 API Key 鉴权：
+// 每个 Key 有名字、可访问的权限范围（scope）以及可操作的账号列表。
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Scope 接口权限范围
+type Scope string
+
+const (
+	// ScopeRead 只读：查看登录状态、Feeds、用户主页、任务与日历
+	ScopeRead Scope = "read"
+	// ScopePublish 发布与互动：发布笔记、评论、点赞、收藏、管理日历
+	ScopePublish Scope = "publish"
+	// ScopeAdmin 账号管理：登录、删除 cookies、修改代理、增删账号
+	ScopeAdmin Scope = "admin"
+	// ScopeAll 拥有全部权限
+	ScopeAll Scope = "*"
+)
+
+var (
+	// ErrUnauthorized 缺少或无效的 API Key
+	ErrUnauthorized = errors.New("missing or invalid api key")
+	// ErrForbidden API Key 无权执行该操作
+	ErrForbidden = errors.New("api key is not allowed to perform this operation")
+)
+
+// Key 一个具名的 API Key
+type Key struct {
+	Name   string
+	Scopes []Scope
+	// Accounts 允许操作的账号 ID，为空表示全部账号
+	Accounts []int
+
+	hash [sha256.Size]byte
+}
+
+// Allows 判断是否拥有 scope 权限
+func (k *Key) Allows(scope Scope) bool {
+	return slices.Contains(k.Scopes, ScopeAll) || slices.Contains(k.Scopes, scope)
+}
+
+// AllowsAccount 判断是否可以操作账号 id
+func (k *Key) AllowsAccount(id int) bool {
+	return len(k.Accounts) == 0 || slices.Contains(k.Accounts, id)
+}
+
+// Keyring 已配置的全部 Key
+type Keyring struct {
+	keys []*Key
+}
+
+// fileKey 配置文件中的一项，key 与 key_sha256（key 的 SHA-256 十六进制）二选一
+type fileKey struct {
+	Name      string  `json:"name"`
+	Key       string  `json:"key,omitempty"`
+	KeySHA256 string  `json:"key_sha256,omitempty"`
+	Scopes    []Scope `json:"scopes"`
+	Accounts  []int   `json:"accounts,omitempty"`
+}
+
+// LoadFile 读取 Key 配置文件，格式：
+//
+//	{"keys": [{"name": "bot", "key": "...", "scopes": ["read", "publish"], "accounts": [1, 2]}]}
+func LoadFile(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read api keys %s", path)
+	}
+	var payload struct {
+		Keys []fileKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, errors.Wrapf(err, "parse api keys %s", path)
+	}
+	kr, err := newKeyring(payload.Keys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "api keys %s", path)
+	}
+	return kr, nil
+}
+
+func newKeyring(items []fileKey) (*Keyring, error) {
+	if len(items) == 0 {
+		return nil, errors.New("no keys configured")
+	}
+	kr := &Keyring{}
+	names := map[string]bool{}
+	hashes := map[[sha256.Size]byte]bool{}
+	for i, item := range items {
+		if item.Name == "" {
+			return nil, fmt.Errorf("key #%d: name is required", i+1)
+		}
+		if names[item.Name] {
+			return nil, fmt.Errorf("key %s: duplicate name", item.Name)
+		}
+		names[item.Name] = true
+
+		k := &Key{Name: item.Name, Accounts: item.Accounts}
+		switch {
+		case item.Key != "" && item.KeySHA256 != "":
+			return nil, fmt.Errorf("key %s: set only one of key and key_sha256", item.Name)
+		case item.Key != "":
+			k.hash = sha256.Sum256([]byte(item.Key))
+		case item.KeySHA256 != "":
+			raw, err := hex.DecodeString(strings.TrimSpace(item.KeySHA256))
+			if err != nil || len(raw) != sha256.Size {
+				return nil, fmt.Errorf("key %s: key_sha256 must be a hex encoded SHA-256", item.Name)
+			}
+			copy(k.hash[:], raw)
+		default:
+			return nil, fmt.Errorf("key %s: key or key_sha256 is required", item.Name)
+		}
+		if hashes[k.hash] {
+			return nil, fmt.Errorf("key %s: duplicate key", item.Name)
+		}
+		hashes[k.hash] = true
+
+		if len(item.Scopes) == 0 {
+			return nil, fmt.Errorf("key %s: scopes is required", item.Name)
+		}
+		for _, s := range item.Scopes {
+			switch s {
+			case ScopeRead, ScopePublish, ScopeAdmin, ScopeAll:
+			default:
+				return nil, fmt.Errorf("key %s: unknown scope %q", item.Name, s)
+			}
+		}
+		k.Scopes = item.Scopes
+		kr.keys = append(kr.keys, k)
+	}
+	return kr, nil
+}
+
+// Lookup 按请求携带的 token 查找 Key，比较耗时与 token 内容无关
+func (kr *Keyring) Lookup(token string) (*Key, bool) {
+	if token == "" {
+		return nil, false
+	}
+	sum := sha256.Sum256([]byte(token))
+	var found *Key
+	for _, k := range kr.keys {
+		if subtle.ConstantTimeCompare(sum[:], k.hash[:]) == 1 {
+			found = k
+		}
+	}
+	return found, found != nil
+}
+
+// Names 返回全部 Key 的名字
+func (kr *Keyring) Names() []string {
+	out := make([]string, 0, len(kr.keys))
+	for _, k := range kr.keys {
+		out = append(out, k.Name)
+	}
+	return out
+}
+
+// TokenFromHeader 从 Authorization: Bearer <token> 或 X-API-Key 请求头取出 token
+func TokenFromHeader(authorization, apiKey string) string {
+	if apiKey != "" {
+		return apiKey
+	}
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+type keyCtx struct{}
+
+// WithKey 将通过鉴权的 Key 放入 ctx
+func WithKey(ctx context.Context, k *Key) context.Context {
+	return context.WithValue(ctx, keyCtx{}, k)
+}
+
+// FromContext 返回 ctx 中的 Key；未启用鉴权或内部调用时为 nil
+func FromContext(ctx context.Context) *Key {
+	k, _ := ctx.Value(keyCtx{}).(*Key)
+	return k
+}
+
+// CheckScope 检查 ctx 中的 Key 是否拥有 scope 权限，ctx 中没有 Key 时放行
+func CheckScope(ctx context.Context, scope Scope) error {
+	k := FromContext(ctx)
+	if k == nil || k.Allows(scope) {
+		return nil
+	}
+	return errors.Wrapf(ErrForbidden, "key %s lacks scope %s", k.Name, scope)
+}
+
+// CheckAccount 检查 ctx 中的 Key 是否可以操作账号 id，ctx 中没有 Key 时放行
+func CheckAccount(ctx context.Context, id int) error {
+	k := FromContext(ctx)
+	if k == nil || k.AllowsAccount(id) {
+		return nil
+	}
+	return errors.Wrapf(ErrForbidden, "key %s cannot access account %d", k.Name, id)
+}
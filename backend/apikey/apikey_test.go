@@ -0,0 +1,87 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeys(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFileAndLookup(t *testing.T) {
+	sum := sha256.Sum256([]byte("hashed-token"))
+	kr, err := LoadFile(writeKeys(t, `{"keys": [
+		{"name": "ops", "key": "ops-token", "scopes": ["*"]},
+		{"name": "bot", "key_sha256": "`+hex.EncodeToString(sum[:])+`", "scopes": ["read"], "accounts": [2]}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ops", "bot"}, kr.Names())
+
+	ops, ok := kr.Lookup("ops-token")
+	require.True(t, ok)
+	assert.True(t, ops.Allows(ScopeAdmin))
+	assert.True(t, ops.AllowsAccount(0))
+
+	bot, ok := kr.Lookup("hashed-token")
+	require.True(t, ok)
+	assert.Equal(t, "bot", bot.Name)
+	assert.True(t, bot.Allows(ScopeRead))
+	assert.False(t, bot.Allows(ScopePublish))
+	assert.True(t, bot.AllowsAccount(2))
+	assert.False(t, bot.AllowsAccount(1))
+	assert.False(t, bot.AllowsAccount(0))
+
+	_, ok = kr.Lookup("wrong")
+	assert.False(t, ok)
+	_, ok = kr.Lookup("")
+	assert.False(t, ok)
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":          `{"keys": []}`,
+		"missing name":   `{"keys": [{"key": "a", "scopes": ["read"]}]}`,
+		"missing key":    `{"keys": [{"name": "a", "scopes": ["read"]}]}`,
+		"duplicate name": `{"keys": [{"name": "a", "key": "x", "scopes": ["read"]}, {"name": "a", "key": "y", "scopes": ["read"]}]}`,
+		"duplicate key":  `{"keys": [{"name": "a", "key": "x", "scopes": ["read"]}, {"name": "b", "key": "x", "scopes": ["read"]}]}`,
+		"unknown scope":  `{"keys": [{"name": "a", "key": "x", "scopes": ["write"]}]}`,
+		"no scopes":      `{"keys": [{"name": "a", "key": "x"}]}`,
+		"bad hash":       `{"keys": [{"name": "a", "key_sha256": "abc", "scopes": ["read"]}]}`,
+	} {
+		_, err := LoadFile(writeKeys(t, content))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestTokenFromHeader(t *testing.T) {
+	assert.Equal(t, "abc", TokenFromHeader("Bearer abc", ""))
+	assert.Equal(t, "abc", TokenFromHeader("bearer  abc ", ""))
+	assert.Equal(t, "xyz", TokenFromHeader("Bearer abc", "xyz"))
+	assert.Equal(t, "", TokenFromHeader("Basic abc", ""))
+	assert.Equal(t, "", TokenFromHeader("", ""))
+}
+
+func TestCheckContext(t *testing.T) {
+	// 未鉴权的内部调用不受限制
+	ctx := context.Background()
+	assert.NoError(t, CheckScope(ctx, ScopeAdmin))
+	assert.NoError(t, CheckAccount(ctx, 1))
+
+	ctx = WithKey(ctx, &Key{Name: "bot", Scopes: []Scope{ScopeRead}, Accounts: []int{2}})
+	assert.NoError(t, CheckScope(ctx, ScopeRead))
+	assert.True(t, errors.Is(CheckScope(ctx, ScopePublish), ErrForbidden))
+	assert.NoError(t, CheckAccount(ctx, 2))
+	assert.True(t, errors.Is(CheckAccount(ctx, 1), ErrForbidden))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
 )
 
 // AppServer 应用服务器结构体，封装所有服务和处理器
@@ -21,13 +22,15 @@ type AppServer struct {
 	router             *gin.Engine
 	httpServer         *http.Server
 	accounts           *accounts.Manager
+	apiKeys            *apikey.Keyring
 }
 
-// NewAppServer 创建新的应用服务器实例
-func NewAppServer(xiaohongshuService *XiaohongshuService) *AppServer {
+// NewAppServer 创建新的应用服务器实例，apiKeys 为 nil 时 HTTP API 与 MCP 端点不鉴权
+func NewAppServer(xiaohongshuService *XiaohongshuService, apiKeys *apikey.Keyring) *AppServer {
 	appServer := &AppServer{
 		xiaohongshuService: xiaohongshuService,
 		accounts:           xiaohongshuService.accounts,
+		apiKeys:            apiKeys,
 	}
 
 	// 初始化 MCP Server（需要在创建 appServer 之后，因为工具注册需要访问 appServer）
@@ -70,5 +73,10 @@ func (s *AppServer) Start(port string) error {
 		logrus.Infof("服务器已优雅关闭")
 	}
 
+	// 停止后台任务，未完成的任务会在下次启动时继续执行
+	if err := s.xiaohongshuService.Close(ctx); err != nil {
+		logrus.Warnf("等待后台任务结束超时: %v", err)
+	}
+
 	return nil
 }
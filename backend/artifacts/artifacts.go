@@ -0,0 +1,254 @@
+// Package artifacts 在浏览器操作失败时保存现场：整页截图、页面 HTML、当前地址以及控制台与网络错误，
+// 每次失败保存为一个目录，按 ID 查看与下载。
+package artifacts
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// 现场目录中的文件
+const (
+	FileMeta       = "meta.json"
+	FileScreenshot = "screenshot.png"
+	FileHTML       = "page.html"
+	FileConsole    = "console.log"
+)
+
+// ErrNotFound 现场不存在或已被清理
+var ErrNotFound = errors.New("artifact not found")
+
+// Meta 失败现场的概要，保存为 meta.json
+type Meta struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Account   string    `json:"account,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Error     string    `json:"error"`
+	// Files 目录中保存的文件（不含 meta.json）
+	Files []string `json:"files"`
+	// CaptureErrors 截图、HTML 等读取失败的原因，页面已崩溃或已关闭时常见
+	CaptureErrors []string `json:"capture_errors,omitempty"`
+}
+
+// Snapshot 一次失败现场的内容
+type Snapshot struct {
+	Meta
+	Screenshot []byte
+	HTML       string
+	// Console 控制台错误、未捕获异常与失败的网络请求，每条一行
+	Console []string
+}
+
+// Error 已保存现场的操作错误，Error() 中带有现场 ID
+type Error struct {
+	ID  string
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v (artifact %s)", e.Err, e.ID)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IDOf 返回 err 链中的现场 ID，没有时返回空字符串
+func IDOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.ID
+	}
+	return ""
+}
+
+// idPattern 现场 ID：创建时间加随机后缀，按字符串排序即按时间排序
+var idPattern = regexp.MustCompile(`^\d{8}-\d{6}-[0-9a-f]{6}$`)
+
+func newID(t time.Time) string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return t.Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// Store 现场目录的本地存储，只保留最近 keep 个（keep <= 0 时不清理）。
+type Store struct {
+	mu   sync.Mutex
+	dir  string
+	keep int
+}
+
+// NewStore 创建存储，dir 不存在时自动创建
+func NewStore(dir string, keep int) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, errors.Wrapf(err, "create artifacts dir %s", dir)
+	}
+	return &Store{dir: dir, keep: keep}, nil
+}
+
+// Save 把现场写入新目录并返回 ID，随后清理超出保留数量的最旧现场
+func (s *Store) Save(snap *Snapshot) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if snap.CreatedAt.IsZero() {
+		snap.CreatedAt = time.Now()
+	}
+	snap.ID = newID(snap.CreatedAt)
+	dir := filepath.Join(s.dir, snap.ID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", errors.Wrapf(err, "create artifact dir %s", dir)
+	}
+
+	console := strings.Join(snap.Console, "\n")
+	if console != "" {
+		console += "\n"
+	}
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{FileScreenshot, snap.Screenshot},
+		{FileHTML, []byte(snap.HTML)},
+		{FileConsole, []byte(console)},
+	}
+	snap.Files = []string{}
+	for _, f := range files {
+		if len(f.data) == 0 && f.name != FileConsole {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, f.name), f.data, 0o600); err != nil {
+			return "", errors.Wrapf(err, "write artifact %s/%s", snap.ID, f.name)
+		}
+		snap.Files = append(snap.Files, f.name)
+	}
+
+	meta, err := json.MarshalIndent(snap.Meta, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, FileMeta), meta, 0o600); err != nil {
+		return "", errors.Wrapf(err, "write artifact %s/%s", snap.ID, FileMeta)
+	}
+
+	s.prune()
+	return snap.ID, nil
+}
+
+// prune 删除超出保留数量的最旧现场，调用方持有锁
+func (s *Store) prune() {
+	if s.keep <= 0 {
+		return
+	}
+	ids, err := s.list()
+	if err != nil || len(ids) <= s.keep {
+		return
+	}
+	for _, id := range ids[:len(ids)-s.keep] {
+		_ = os.RemoveAll(filepath.Join(s.dir, id))
+	}
+}
+
+// list 按时间从旧到新返回全部现场 ID
+func (s *Store) list() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() && idPattern.MatchString(e.Name()) {
+			ids = append(ids, e.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Get 读取现场概要
+func (s *Store) Get(id string) (*Meta, error) {
+	if !idPattern.MatchString(id) {
+		return nil, ErrNotFound
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, id, FileMeta))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrapf(err, "parse artifact %s", id)
+	}
+	return &m, nil
+}
+
+// Open 打开现场中的单个文件，name 只能是 meta.json 或 Files 中的文件
+func (s *Store) Open(id, name string) (*os.File, error) {
+	m, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if name != FileMeta && !contains(m.Files, name) {
+		return nil, ErrNotFound
+	}
+	f, err := os.Open(filepath.Join(s.dir, id, name))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// WriteZip 把整个现场目录打包为 zip 写入 w
+func (s *Store) WriteZip(w io.Writer, id string) error {
+	m, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	for _, name := range append([]string{FileMeta}, m.Files...) {
+		if err := s.addToZip(zw, id, name); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (s *Store) addToZip(zw *zip.Writer, id, name string) error {
+	f, err := os.Open(filepath.Join(s.dir, id, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dst, err := zw.Create(id + "/" + name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, f)
+	return err
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
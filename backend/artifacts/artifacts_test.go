@@ -0,0 +1,87 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSave(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir, 0)
+	require.NoError(t, err)
+
+	snap := &Snapshot{
+		Meta:       Meta{Account: "acc_1", URL: "https://www.xiaohongshu.com/explore", Error: "timeout"},
+		Screenshot: []byte("png"),
+		Console:    []string{"[console.error] boom", "[network] GET https://example.com/a 500"},
+	}
+	id, err := s.Save(snap)
+	require.NoError(t, err)
+	assert.Regexp(t, idPattern, id)
+
+	m, err := s.Get(id)
+	require.NoError(t, err)
+	assert.Equal(t, "acc_1", m.Account)
+	assert.Equal(t, "timeout", m.Error)
+	assert.Equal(t, []string{FileScreenshot, FileConsole}, m.Files, "HTML 为空时不保存")
+
+	f, err := s.Open(id, FileConsole)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(f)
+	f.Close()
+	assert.Equal(t, "[console.error] boom\n[network] GET https://example.com/a 500\n", string(data))
+
+	_, err = s.Open(id, FileHTML)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Open(id, "../../etc/passwd")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Get("../" + id)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.WriteZip(&buf, id))
+	assert.NotZero(t, buf.Len())
+	assert.ErrorIs(t, s.WriteZip(io.Discard, "20250101-000000-abcdef"), ErrNotFound)
+}
+
+func TestStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir, 2)
+	require.NoError(t, err)
+
+	// 不是现场目录的文件不受影响
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "keep-me"), 0o700))
+
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.Local)
+	var ids []string
+	for i := 0; i < 4; i++ {
+		id, err := s.Save(&Snapshot{Meta: Meta{CreatedAt: start.Add(time.Duration(i) * time.Minute), Error: fmt.Sprint(i)}})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	left, err := s.list()
+	require.NoError(t, err)
+	assert.Equal(t, ids[2:], left)
+	_, err = s.Get(ids[0])
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.DirExists(t, filepath.Join(dir, "keep-me"))
+}
+
+func TestError(t *testing.T) {
+	cause := context.DeadlineExceeded
+	err := fmt.Errorf("发表评论失败: %w", &Error{ID: "20250601-100000-abcdef", Err: cause})
+
+	assert.Equal(t, "发表评论失败: context deadline exceeded (artifact 20250601-100000-abcdef)", err.Error())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, "20250601-100000-abcdef", IDOf(err))
+	assert.Empty(t, IDOf(cause))
+}
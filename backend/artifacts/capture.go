@@ -0,0 +1,156 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+)
+
+// maxConsoleLines Recorder 最多保留的行数，超出时丢弃最旧的
+const maxConsoleLines = 500
+
+// captureTimeout 读取截图、HTML 等的总超时，页面卡死时不拖住错误返回
+const captureTimeout = 20 * time.Second
+
+// Recorder 记录页面的控制台错误与警告、未捕获异常，以及失败或返回 4xx/5xx 的网络请求
+type Recorder struct {
+	mu    sync.Mutex
+	lines []string
+	stop  context.CancelFunc
+}
+
+// Record 开始记录 page 上的事件，不再需要时调用 Stop
+func Record(page *rod.Page) *Recorder {
+	ctx, cancel := context.WithCancel(page.GetContext())
+	p := page.Context(ctx)
+	r := &Recorder{stop: cancel}
+
+	if err := (proto.RuntimeEnable{}).Call(p); err != nil {
+		logrus.Warnf("enable runtime domain failed: %v", err)
+	}
+	if err := (proto.LogEnable{}).Call(p); err != nil {
+		logrus.Warnf("enable log domain failed: %v", err)
+	}
+	if err := (proto.NetworkEnable{}).Call(p); err != nil {
+		logrus.Warnf("enable network domain failed: %v", err)
+	}
+
+	// 事件在同一个协程中依次处理，requests 不需要加锁
+	requests := map[proto.NetworkRequestID]string{}
+	wait := p.EachEvent(func(e *proto.RuntimeConsoleAPICalled) {
+		switch e.Type {
+		case proto.RuntimeConsoleAPICalledTypeError, proto.RuntimeConsoleAPICalledTypeWarning, proto.RuntimeConsoleAPICalledTypeAssert:
+		default:
+			return
+		}
+		args := make([]string, 0, len(e.Args))
+		for _, a := range e.Args {
+			args = append(args, remoteObjectText(a))
+		}
+		r.add("console."+string(e.Type), strings.Join(args, " "))
+	}, func(e *proto.RuntimeExceptionThrown) {
+		text := e.ExceptionDetails.Text
+		if ex := e.ExceptionDetails.Exception; ex != nil && ex.Description != "" {
+			text += " " + ex.Description
+		}
+		r.add("exception", text)
+	}, func(e *proto.LogEntryAdded) {
+		if e.Entry.Level == proto.LogLogEntryLevelError || e.Entry.Level == proto.LogLogEntryLevelWarning {
+			r.add("log."+string(e.Entry.Level), strings.TrimSpace(e.Entry.Text+" "+e.Entry.URL))
+		}
+	}, func(e *proto.NetworkRequestWillBeSent) {
+		requests[e.RequestID] = e.Request.Method + " " + e.Request.URL
+	}, func(e *proto.NetworkResponseReceived) {
+		if e.Response.Status >= 400 {
+			req := requests[e.RequestID]
+			if req == "" {
+				req = e.Response.URL
+			}
+			r.add("network", fmt.Sprintf("%s %d", req, e.Response.Status))
+		}
+	}, func(e *proto.NetworkLoadingFinished) {
+		delete(requests, e.RequestID)
+	}, func(e *proto.NetworkLoadingFailed) {
+		if !e.Canceled {
+			r.add("network", fmt.Sprintf("%s %s", requests[e.RequestID], e.ErrorText))
+		}
+		delete(requests, e.RequestID)
+	})
+	go wait()
+	return r
+}
+
+func (r *Recorder) add(kind, text string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprintf("%s [%s] %s", time.Now().Format("15:04:05.000"), kind, text))
+	if n := len(r.lines) - maxConsoleLines; n > 0 {
+		r.lines = append(r.lines[:0], r.lines[n:]...)
+	}
+}
+
+// Lines 目前记录的全部行，按发生顺序
+func (r *Recorder) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+// Stop 停止记录
+func (r *Recorder) Stop() {
+	r.stop()
+}
+
+// remoteObjectText console 参数的文本形式
+func remoteObjectText(o *proto.RuntimeRemoteObject) string {
+	switch {
+	case o.Type == proto.RuntimeRemoteObjectTypeString:
+		return o.Value.Str()
+	case o.Description != "":
+		return o.Description
+	case o.UnserializableValue != "":
+		return string(o.UnserializableValue)
+	case !o.Value.Nil():
+		return o.Value.JSON("", "")
+	}
+	return string(o.Type)
+}
+
+// Capture 读取页面当前的地址、标题、整页截图和 HTML，以及 rec 记录的控制台与网络错误（rec 可为 nil）。
+// 页面可能已经卡死或关闭，单项读取失败记录在 CaptureErrors 中，不影响其余内容。
+func Capture(page *rod.Page, rec *Recorder, cause error) *Snapshot {
+	ctx, cancel := context.WithTimeout(context.Background(), captureTimeout)
+	defer cancel()
+	p := page.Context(ctx)
+
+	snap := &Snapshot{Meta: Meta{CreatedAt: time.Now(), Error: cause.Error()}}
+	fail := func(what string, err error) {
+		snap.CaptureErrors = append(snap.CaptureErrors, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	if info, err := p.Info(); err != nil {
+		fail("page info", err)
+	} else {
+		snap.URL, snap.Title = info.URL, info.Title
+	}
+	if img, err := p.Screenshot(true, &proto.PageCaptureScreenshot{Format: proto.PageCaptureScreenshotFormatPng}); err != nil {
+		fail("screenshot", err)
+	} else {
+		snap.Screenshot = img
+	}
+	if html, err := p.HTML(); err != nil {
+		fail("html", err)
+	} else {
+		snap.HTML = html
+	}
+	if rec != nil {
+		snap.Console = rec.Lines()
+	}
+	return snap
+}
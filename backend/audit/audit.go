@@ -0,0 +1,187 @@
+// Package audit 记录账号在小红书上执行的每一次操作（发布、评论、点赞等），
+// 只追加不修改，用于事后追溯以及让调用方避免重复操作。
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Action 操作类型
+type Action string
+
+const (
+	ActionPublishContent  Action = "publish_content"
+	ActionSaveDraft       Action = "save_draft_content"
+	ActionScheduleContent Action = "schedule_content"
+	ActionPublishVideo    Action = "publish_video"
+	ActionSaveDraftVideo  Action = "save_draft_video"
+	ActionScheduleVideo   Action = "schedule_video"
+	ActionPublishDraft    Action = "publish_draft"
+	ActionEditNote        Action = "edit_note"
+	ActionDeleteNote      Action = "delete_note"
+	ActionNoteVisibility  Action = "set_note_visibility"
+	ActionComment         Action = "comment"
+	ActionReply           Action = "reply"
+	ActionLikeComment     Action = "like_comment"
+	ActionUnlikeComment   Action = "unlike_comment"
+	ActionDeleteComment   Action = "delete_comment"
+	ActionPinComment      Action = "pin_comment"
+	ActionUnpinComment    Action = "unpin_comment"
+	ActionLike            Action = "like"
+	ActionUnlike          Action = "unlike"
+	ActionFavorite        Action = "favorite"
+	ActionUnfavorite      Action = "unfavorite"
+	ActionFollow          Action = "follow"
+	ActionUnfollow        Action = "unfollow"
+	ActionSendMessage     Action = "send_message"
+	ActionDeleteCookies   Action = "delete_cookies"
+)
+
+// Outcome 操作结果
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// Entry 一条审计记录
+type Entry struct {
+	ID         string    `json:"id"`
+	Time       time.Time `json:"time"`
+	AccountKey string    `json:"account_key"`
+	Action     Action    `json:"action"`
+	FeedID     string    `json:"feed_id,omitempty"`
+	CommentID  string    `json:"comment_id,omitempty"`
+	UserID     string    `json:"user_id,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	// ContentHash 正文的 SHA-256（十六进制），不保存正文本身
+	ContentHash string  `json:"content_hash,omitempty"`
+	Caller      string  `json:"caller,omitempty"`
+	Outcome     Outcome `json:"outcome"`
+	Error       string  `json:"error,omitempty"`
+	DurationMS  int64   `json:"duration_ms"`
+}
+
+// Filter 查询条件，零值表示不过滤；结果按时间倒序
+type Filter struct {
+	AccountKey  string
+	Action      Action
+	FeedID      string
+	ContentHash string
+	Caller      string
+	Outcome     Outcome
+	Since       time.Time
+	Until       time.Time
+	Limit       int
+}
+
+// Match 判断 e 是否满足筛选条件（不考虑 Limit）
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case f.AccountKey != "" && e.AccountKey != f.AccountKey,
+		f.Action != "" && e.Action != f.Action,
+		f.FeedID != "" && e.FeedID != f.FeedID,
+		f.ContentHash != "" && e.ContentHash != f.ContentHash,
+		f.Caller != "" && e.Caller != f.Caller,
+		f.Outcome != "" && e.Outcome != f.Outcome,
+		!f.Since.IsZero() && e.Time.Before(f.Since),
+		!f.Until.IsZero() && !e.Time.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Store 审计记录的存储后端
+type Store interface {
+	Append(e Entry) error
+	Query(f Filter) ([]Entry, error)
+	Close() error
+}
+
+// DefaultLimit Query 未指定 Limit 时最多返回的条数
+const DefaultLimit = 100
+
+// Log 审计日志；nil *Log 不记录任何内容
+type Log struct {
+	store Store
+}
+
+// New 创建审计日志
+func New(store Store) *Log {
+	return &Log{store: store}
+}
+
+// Record 补全 ID、时间后写入一条记录。写入失败只打印日志，不影响业务操作。
+func (l *Log) Record(e Entry) {
+	if l == nil {
+		return
+	}
+	if e.ID == "" {
+		e.ID = newEntryID()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if err := l.store.Append(e); err != nil {
+		logrus.Errorf("audit: failed to record %s for %s: %v", e.Action, e.AccountKey, err)
+	}
+}
+
+// Query 按条件查询，最新的记录在前
+func (l *Log) Query(f Filter) ([]Entry, error) {
+	if l == nil {
+		return []Entry{}, nil
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	return l.store.Query(f)
+}
+
+// Close 关闭存储后端
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.store.Close()
+}
+
+// HashContent 返回正文的 SHA-256，首尾空白不参与计算；空正文返回空串
+func HashContent(content string) string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+type callerCtx struct{}
+
+// WithCaller 标记操作的发起方，如 API Key 名称或 "job:<id>"
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerCtx{}, caller)
+}
+
+// Caller 返回 ctx 中的发起方
+func Caller(ctx context.Context) string {
+	c, _ := ctx.Value(callerCtx{}).(string)
+	return c
+}
+
+func newEntryID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("aud_%d", time.Now().UnixNano())
+	}
+	return "aud_" + hex.EncodeToString(b)
+}
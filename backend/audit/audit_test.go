@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStoreQuery(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "audit.jsonl"), FileOptions{})
+	require.NoError(t, err)
+	log := New(store)
+	defer log.Close()
+
+	start := time.Now().Add(-time.Hour)
+	log.Record(Entry{Time: start, AccountKey: "acc_1", Action: ActionComment, FeedID: "f1", ContentHash: HashContent("hello"), Outcome: OutcomeSuccess})
+	log.Record(Entry{Time: start.Add(time.Minute), AccountKey: "acc_2", Action: ActionLike, FeedID: "f1", Outcome: OutcomeSuccess})
+	log.Record(Entry{Time: start.Add(2 * time.Minute), AccountKey: "acc_1", Action: ActionComment, FeedID: "f2", Outcome: OutcomeFailure, Error: "boom"})
+
+	all, err := log.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "f2", all[0].FeedID)
+	assert.NotEmpty(t, all[0].ID)
+
+	got, err := log.Query(Filter{AccountKey: "acc_1", Action: ActionComment})
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	got, err = log.Query(Filter{ContentHash: HashContent("  hello\n")})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "f1", got[0].FeedID)
+
+	got, err = log.Query(Filter{Since: start.Add(30 * time.Second), Outcome: OutcomeSuccess})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "acc_2", got[0].AccountKey)
+
+	got, err = log.Query(Filter{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestFileStoreRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	store, err := NewFileStore(path, FileOptions{MaxBytes: 300, MaxFiles: 2})
+	require.NoError(t, err)
+	log := New(store)
+	defer log.Close()
+
+	for i := 0; i < 20; i++ {
+		log.Record(Entry{AccountKey: "acc_1", Action: ActionLike, FeedID: string(rune('a' + i)), Outcome: OutcomeSuccess})
+	}
+	_, err = os.Stat(path + ".1")
+	assert.NoError(t, err)
+	_, err = os.Stat(path + ".2")
+	assert.NoError(t, err)
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	// 最新的记录仍然排在最前，最旧的记录已被轮转删除
+	got, err := log.Query(Filter{Limit: 1000})
+	require.NoError(t, err)
+	require.NotEmpty(t, got)
+	assert.Less(t, len(got), 20)
+	assert.Equal(t, "t", got[0].FeedID)
+	for i := 1; i < len(got); i++ {
+		assert.Greater(t, got[i-1].FeedID, got[i].FeedID)
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var log *Log
+	log.Record(Entry{Action: ActionLike})
+	got, err := log.Query(Filter{})
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+	assert.NoError(t, log.Close())
+}
+
+func TestCaller(t *testing.T) {
+	assert.Equal(t, "", Caller(context.Background()))
+	assert.Equal(t, "job:1", Caller(WithCaller(context.Background(), "job:1")))
+}
@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// FileOptions JSONL 文件的轮转设置
+type FileOptions struct {
+	// MaxBytes 当前文件超过该大小后轮转，0 表示不轮转
+	MaxBytes int64
+	// MaxFiles 保留的历史文件个数（path.1 … path.N），超出的最旧文件被删除
+	MaxFiles int
+}
+
+// FileStore 以 JSONL 格式追加写入审计记录，每行一条
+type FileStore struct {
+	path string
+	opts FileOptions
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore 打开（不存在时创建）审计文件
+func NewFileStore(path string, opts FileOptions) (*FileStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	s := &FileStore{path: path, opts: opts}
+	if err := s.openLocked(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileStore) openLocked() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "open audit log %s", s.path)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f, s.size = f, info.Size()
+	return nil
+}
+
+// Append 追加一条记录，必要时先轮转
+func (s *FileStore) Append(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return errors.New("audit log closed")
+	}
+	if s.opts.MaxBytes > 0 && s.size > 0 && s.size+int64(len(line)) > s.opts.MaxBytes {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+	n, err := s.f.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// rotateLocked 将 path.N-1 依次改名为 path.N，当前文件改名为 path.1 后重新创建
+func (s *FileStore) rotateLocked() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	s.f = nil
+	if s.opts.MaxFiles <= 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.openLocked()
+	}
+	if err := os.Remove(s.rotatedPath(s.opts.MaxFiles)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := s.opts.MaxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(s.rotatedPath(i), s.rotatedPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, s.rotatedPath(1)); err != nil {
+		return err
+	}
+	logrus.Infof("audit: rotated %s", s.path)
+	return s.openLocked()
+}
+
+func (s *FileStore) rotatedPath(i int) string {
+	return fmt.Sprintf("%s.%d", s.path, i)
+}
+
+// Query 从当前文件到最旧的历史文件依次查找，最新的记录在前
+func (s *FileStore) Query(f Filter) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := []Entry{}
+	paths := []string{s.path}
+	for i := 1; i <= s.opts.MaxFiles; i++ {
+		paths = append(paths, s.rotatedPath(i))
+	}
+	for _, path := range paths {
+		entries, err := readEntries(path, f)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		slices.Reverse(entries)
+		out = append(out, entries...)
+		if f.Limit > 0 && len(out) >= f.Limit {
+			return out[:f.Limit], nil
+		}
+	}
+	return out, nil
+}
+
+// readEntries 读取文件中满足条件的记录，按写入顺序返回；无法解析的行被跳过
+func readEntries(path string, f Filter) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var out []Entry
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			logrus.Warnf("audit: skip malformed line in %s: %v", path, err)
+			continue
+		}
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	return out, errors.Wrapf(sc.Err(), "read audit log %s", path)
+}
+
+// Close 关闭文件
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/calendar"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+)
+
+// toolScopes MCP 工具所需的权限，未列出的工具需要 admin 权限
+var toolScopes = map[string]apikey.Scope{
+	"check_login_status":    apikey.ScopeRead,
+	"list_feeds":            apikey.ScopeRead,
+	"search_feeds":          apikey.ScopeRead,
+	"get_feed_detail":       apikey.ScopeRead,
+	"user_profile":          apikey.ScopeRead,
+	"get_job_status":        apikey.ScopeRead,
+	"list_calendar":         apikey.ScopeRead,
+	"list_following":        apikey.ScopeRead,
+	"list_followers":        apikey.ScopeRead,
+	"list_my_notes":         apikey.ScopeRead,
+	"list_drafts":           apikey.ScopeRead,
+	"get_note_analytics":    apikey.ScopeRead,
+	"get_account_analytics": apikey.ScopeRead,
+	"get_notifications":     apikey.ScopeRead,
+	"list_conversations":    apikey.ScopeRead,
+	"get_messages":          apikey.ScopeRead,
+	"get_action_history":    apikey.ScopeRead,
+
+	"publish_content":          apikey.ScopePublish,
+	"save_draft_content":       apikey.ScopePublish,
+	"schedule_publish_content": apikey.ScopePublish,
+	"publish_with_video":       apikey.ScopePublish,
+	"save_draft_video":         apikey.ScopePublish,
+	"schedule_publish_video":   apikey.ScopePublish,
+	"publish_draft":            apikey.ScopePublish,
+	"post_comment_to_feed":     apikey.ScopePublish,
+	"reply_comment_in_feed":    apikey.ScopePublish,
+	"like_comment":             apikey.ScopePublish,
+	"delete_comment":           apikey.ScopePublish,
+	"pin_comment":              apikey.ScopePublish,
+	"edit_my_note":             apikey.ScopePublish,
+	"delete_my_note":           apikey.ScopePublish,
+	"set_note_visibility":      apikey.ScopePublish,
+	"like_feed":                apikey.ScopePublish,
+	"favorite_feed":            apikey.ScopePublish,
+	"follow_user":              apikey.ScopePublish,
+	"unfollow_user":            apikey.ScopePublish,
+	"send_message":             apikey.ScopePublish,
+	"add_calendar_entry":       apikey.ScopePublish,
+	"move_calendar_entry":      apikey.ScopePublish,
+	"cancel_calendar_entry":    apikey.ScopePublish,
+
+	"list_accounts":    apikey.ScopeAdmin,
+	"get_login_qrcode": apikey.ScopeAdmin,
+	"delete_cookies":   apikey.ScopeAdmin,
+}
+
+func toolScope(name string) apikey.Scope {
+	if scope, ok := toolScopes[name]; ok {
+		return scope
+	}
+	return apikey.ScopeAdmin
+}
+
+// authMiddleware 校验 Authorization: Bearer 或 X-API-Key 请求头，通过后把 Key 放入请求的 ctx。
+// 未配置 API Key 时不鉴权。
+func authMiddleware(kr *apikey.Keyring) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if kr == nil {
+			c.Next()
+			return
+		}
+		token := apikey.TokenFromHeader(c.GetHeader("Authorization"), c.GetHeader("X-API-Key"))
+		key, ok := kr.Lookup(token)
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer realm="xiaohongshu-mcp"`)
+			respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "缺少或无效的 API Key", nil)
+			c.Abort()
+			return
+		}
+		c.Set("api_key", key.Name)
+		c.Request = c.Request.WithContext(apikey.WithKey(c.Request.Context(), key))
+		c.Next()
+	}
+}
+
+// requireScope 要求 API Key 拥有指定权限
+func requireScope(scope apikey.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := apikey.CheckScope(c.Request.Context(), scope); err != nil {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "API Key 无权执行该操作", err.Error())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// respondAccountError 账号不允许访问时返回 403，其余按账号不存在处理
+func respondAccountError(c *gin.Context, err error) {
+	if errors.Is(err, apikey.ErrForbidden) {
+		respondError(c, http.StatusForbidden, "FORBIDDEN", "API Key 无权操作该账号", err.Error())
+		return
+	}
+	respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+}
+
+// mcpAuthMiddleware 对 MCP 请求按 API Key 检查工具权限，并把 Key 放入 ctx 供账号检查使用。
+// HTTP 层的 authMiddleware 已拒绝无效 Key，这里从请求头重新取出 Key，因为 SDK 不会把 HTTP 请求的 ctx 传给工具。
+func (s *AppServer) mcpAuthMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if s.apiKeys == nil {
+			return next(ctx, method, req)
+		}
+		var key *apikey.Key
+		if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+			key, _ = s.apiKeys.Lookup(apikey.TokenFromHeader(extra.Header.Get("Authorization"), extra.Header.Get("X-API-Key")))
+		}
+		if key == nil {
+			return nil, apikey.ErrUnauthorized
+		}
+		ctx = apikey.WithKey(ctx, key)
+
+		switch method {
+		case "tools/call":
+			params, _ := req.GetParams().(*mcp.CallToolParamsRaw)
+			if params != nil && !key.Allows(toolScope(params.Name)) {
+				logrus.Warnf("api key %s denied tool %s", key.Name, params.Name)
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{&mcp.TextContent{
+						Text: fmt.Sprintf("API Key %s 无权调用工具 %s（需要 %s 权限）", key.Name, params.Name, toolScope(params.Name)),
+					}},
+				}, nil
+			}
+		case "tools/list":
+			// 只列出当前 Key 可以调用的工具
+			res, err := next(ctx, method, req)
+			if list, ok := res.(*mcp.ListToolsResult); ok && err == nil {
+				tools := make([]*mcp.Tool, 0, len(list.Tools))
+				for _, t := range list.Tools {
+					if key.Allows(toolScope(t.Name)) {
+						tools = append(tools, t)
+					}
+				}
+				list.Tools = tools
+			}
+			return res, err
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// getAccount 按 ID 获取账号，并检查 API Key 是否可以操作该账号
+func (s *AppServer) getAccount(ctx context.Context, id int) (*accounts.Account, error) {
+	if err := apikey.CheckAccount(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.accounts.Get(id)
+}
+
+// checkAccountKey 检查 API Key 能否访问 accountKey 对应账号的数据（任务、日历条目）
+func (s *AppServer) checkAccountKey(ctx context.Context, accountKey string) error {
+	id := 0
+	if acc, err := s.accounts.GetByKey(accountKey); err == nil {
+		id = acc.ID
+	}
+	return apikey.CheckAccount(ctx, id)
+}
+
+// visibleAccounts 过滤出 API Key 可以看到的账号
+func visibleAccounts(ctx context.Context, list []accounts.Account) []accounts.Account {
+	out := make([]accounts.Account, 0, len(list))
+	for _, acc := range list {
+		if apikey.CheckAccount(ctx, acc.ID) == nil {
+			out = append(out, acc)
+		}
+	}
+	return out
+}
+
+// getJob 查询任务；任务属于 Key 无权访问的账号时按不存在处理
+func (s *AppServer) getJob(ctx context.Context, id string) (*jobs.Job, error) {
+	job, err := s.xiaohongshuService.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.checkAccountKey(ctx, job.AccountKey) != nil {
+		return nil, errors.Errorf("job %s not found", id)
+	}
+	return job, nil
+}
+
+// listJobs 列出任务，只保留 Key 可以访问的账号的任务
+func (s *AppServer) listJobs(ctx context.Context, filter jobs.Filter) *JobListResponse {
+	if apikey.FromContext(ctx) == nil {
+		return s.xiaohongshuService.ListJobs(filter)
+	}
+	// 先取全部再过滤，避免 limit 截断后结果变少
+	limit := filter.Limit
+	filter.Limit = 0
+	list := []jobs.Job{}
+	for _, job := range s.xiaohongshuService.ListJobs(filter).Jobs {
+		if s.checkAccountKey(ctx, job.AccountKey) == nil {
+			list = append(list, job)
+		}
+	}
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return &JobListResponse{Jobs: list, Count: len(list)}
+}
+
+// getCalendarEntry 查询日历条目；条目属于 Key 无权访问的账号时按不存在处理
+func (s *AppServer) getCalendarEntry(ctx context.Context, id string) (*calendar.Entry, error) {
+	entry, err := s.xiaohongshuService.GetCalendarEntry(id)
+	if err != nil {
+		return nil, err
+	}
+	if apikey.CheckAccount(ctx, entry.AccountID) != nil {
+		return nil, errors.Wrapf(calendar.ErrNotFound, "entry %s", id)
+	}
+	return entry, nil
+}
+
+// listCalendar 列出日历条目，只保留 Key 可以访问的账号的条目
+func (s *AppServer) listCalendar(ctx context.Context, filter calendar.Filter) *CalendarListResponse {
+	res := s.xiaohongshuService.ListCalendar(filter)
+	if apikey.FromContext(ctx) == nil {
+		return res
+	}
+	list := []calendar.Entry{}
+	for _, entry := range res.Entries {
+		if apikey.CheckAccount(ctx, entry.AccountID) == nil {
+			list = append(list, entry)
+		}
+	}
+	return &CalendarListResponse{Entries: list, Count: len(list)}
+}
+
+// queryAudit 查询审计记录；受限的 Key 未指定账号时只查询其可以访问的账号
+func (s *AppServer) queryAudit(ctx context.Context, filter audit.Filter) (*AuditListResponse, error) {
+	key := apikey.FromContext(ctx)
+	if key == nil || len(key.Accounts) == 0 || filter.AccountKey != "" {
+		return s.xiaohongshuService.QueryAudit(filter)
+	}
+	entries := []audit.Entry{}
+	for _, id := range key.Accounts {
+		acc, err := s.accounts.Get(id)
+		if err != nil {
+			continue
+		}
+		f := filter
+		f.AccountKey = acc.Key
+		res, err := s.xiaohongshuService.QueryAudit(f)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, res.Entries...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = audit.DefaultLimit
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return &AuditListResponse{Entries: entries, Count: len(entries)}, nil
+}
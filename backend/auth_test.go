@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
+	"github.com/xpzouying/xiaohongshu-mcp/calendar"
+)
+
+const testAPIKeys = `{"keys": [
+	{"name": "ops", "key": "ops-token", "scopes": ["*"]},
+	{"name": "reader", "key": "reader-token", "scopes": ["read"], "accounts": [2]}
+]}`
+
+func setupAuthTestApp(t *testing.T) (*AppServer, string, []*calendar.Entry) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(testAPIKeys), 0o600))
+	kr, err := apikey.LoadFile(path)
+	require.NoError(t, err)
+
+	app, ts := setupTestAppWithKeys(t, kr)
+	t.Cleanup(ts.Close)
+
+	var entries []*calendar.Entry
+	for i := 0; i < 2; i++ {
+		acc, err := app.accounts.Create("", "")
+		require.NoError(t, err)
+		entry, err := app.xiaohongshuService.calendar.Add(calendar.Entry{
+			AccountID:  acc.ID,
+			AccountKey: acc.Key,
+			Kind:       calendar.KindImage,
+			Title:      acc.Key,
+			PublishAt:  time.Now().Add(24 * time.Hour),
+		})
+		require.NoError(t, err)
+		entries = append(entries, entry)
+	}
+	return app, ts.URL, entries
+}
+
+func doAuthRequest(t *testing.T, method, url, token string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader("{}"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestAPIAuth(t *testing.T) {
+	_, base, entries := setupAuthTestApp(t)
+
+	// 健康检查不需要鉴权
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, "GET", base+"/health", "").StatusCode)
+
+	assert.Equal(t, http.StatusUnauthorized, doAuthRequest(t, "GET", base+"/api/v1/quota", "").StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, doAuthRequest(t, "GET", base+"/api/v1/quota", "wrong").StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, doAuthRequest(t, "POST", base+"/mcp", "").StatusCode)
+
+	// X-API-Key 与 Bearer 等价
+	req, _ := http.NewRequest("GET", base+"/api/v1/accounts", nil)
+	req.Header.Set("X-API-Key", "ops-token")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// reader 只能读取账号 2
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, "GET", base+"/api/v1/quota?account_id=2", "reader-token").StatusCode)
+	assert.Equal(t, http.StatusForbidden, doAuthRequest(t, "GET", base+"/api/v1/quota", "reader-token").StatusCode)
+	assert.Equal(t, http.StatusForbidden, doAuthRequest(t, "POST", base+"/api/v1/publish", "reader-token").StatusCode)
+	assert.Equal(t, http.StatusForbidden, doAuthRequest(t, "GET", base+"/api/v1/accounts", "reader-token").StatusCode)
+	assert.Equal(t, http.StatusForbidden, doAuthRequest(t, "GET", base+"/metrics", "reader-token").StatusCode)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, "GET", base+"/metrics", "ops-token").StatusCode)
+	assert.Equal(t, http.StatusForbidden, doAuthRequest(t, "GET", base+"/api/v1/status", "reader-token").StatusCode)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, "GET", base+"/api/v1/status", "ops-token").StatusCode)
+	assert.Equal(t, http.StatusNotFound, doAuthRequest(t, "GET", base+"/api/v1/calendar/"+entries[0].ID, "reader-token").StatusCode)
+	assert.Equal(t, http.StatusOK, doAuthRequest(t, "GET", base+"/api/v1/calendar/"+entries[1].ID, "reader-token").StatusCode)
+
+	resp = doAuthRequest(t, "GET", base+"/api/v1/calendar", "reader-token")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var list struct {
+		Data CalendarListResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	require.Len(t, list.Data.Entries, 1)
+	assert.Equal(t, entries[1].ID, list.Data.Entries[0].ID)
+}
+
+// bearerTransport 为每个请求加上 Authorization 头
+type bearerTransport struct {
+	token string
+}
+
+func (b bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestMCPAuth(t *testing.T) {
+	_, base, entries := setupAuthTestApp(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   base + "/mcp",
+		HTTPClient: &http.Client{Transport: bearerTransport{token: "reader-token"}},
+		MaxRetries: -1,
+	}, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	names := map[string]bool{}
+	for _, tool := range tools.Tools {
+		names[tool.Name] = true
+	}
+	assert.True(t, names["list_feeds"])
+	assert.True(t, names["get_action_history"], "与 /api/v1/audit 一样只需要 read 权限")
+	assert.False(t, names["publish_content"])
+	assert.False(t, names["list_accounts"])
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "publish_content", Arguments: map[string]any{"account_id": 2}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "check_login_status", Arguments: map[string]any{"account_id": 1}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "cannot access account 1")
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "list_calendar", Arguments: map[string]any{}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	text := res.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, entries[1].ID)
+	assert.NotContains(t, text, entries[0].ID)
+}
@@ -0,0 +1,388 @@
+package browser
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrPoolClosed is returned by Acquire after the pool has been closed.
+var ErrPoolClosed = errors.New("browser pool closed")
+
+// PoolConfig controls how many browsers are kept warm and for how long.
+type PoolConfig struct {
+	// MaxBrowsers caps the number of live pooled browsers. <= 0 means unlimited.
+	MaxBrowsers int
+	// IdleTTL closes browsers that have not been used for this long. <= 0 disables eviction.
+	IdleTTL time.Duration
+}
+
+// LaunchFunc starts a new browser for a pool key.
+type LaunchFunc func() (*Browser, error)
+
+// Pool keeps one warm browser per account key and hands it out to a single
+// caller at a time, so pages of the same account never race on one profile.
+type Pool struct {
+	mu      sync.Mutex
+	cfg     PoolConfig
+	entries map[string]*poolEntry
+	changed chan struct{}
+	closed  bool
+
+	launches  uint64
+	reuses    uint64
+	evictions uint64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+
+	// alive 检查复用前浏览器是否仍然可用，测试中可替换
+	alive func(*Browser) bool
+}
+
+type poolEntry struct {
+	key        string
+	browser    *Browser
+	busy       bool
+	retire     bool
+	uses       int
+	launchedAt time.Time
+	lastUsed   time.Time
+}
+
+// PoolStats is a point-in-time snapshot of the pool.
+type PoolStats struct {
+	Browsers    int                `json:"browsers"`
+	InUse       int                `json:"in_use"`
+	Idle        int                `json:"idle"`
+	MaxBrowsers int                `json:"max_browsers"`
+	IdleTTL     string             `json:"idle_ttl"`
+	Launches    uint64             `json:"launches"`
+	Reuses      uint64             `json:"reuses"`
+	Evictions   uint64             `json:"evictions"`
+	Accounts    []PoolAccountStats `json:"accounts"`
+}
+
+// PoolAccountStats describes the pooled browser of one account.
+type PoolAccountStats struct {
+	Account    string    `json:"account"`
+	InUse      bool      `json:"in_use"`
+	Uses       int       `json:"uses"`
+	LaunchedAt time.Time `json:"launched_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// NewPool creates a pool and starts the idle eviction loop.
+func NewPool(cfg PoolConfig) *Pool {
+	p := &Pool{
+		cfg:     cfg,
+		entries: map[string]*poolEntry{},
+		changed: make(chan struct{}),
+		stop:    make(chan struct{}),
+		alive:   (*Browser).Alive,
+	}
+	if cfg.IdleTTL > 0 {
+		p.wg.Add(1)
+		go p.evictLoop()
+	}
+	return p
+}
+
+// Lease grants exclusive use of an account's pooled browser until Release.
+type Lease struct {
+	pool  *Pool
+	entry *poolEntry
+	once  sync.Once
+}
+
+// Browser returns the leased browser.
+func (l *Lease) Browser() *Browser {
+	return l.entry.browser
+}
+
+// Release returns the browser to the pool.
+func (l *Lease) Release() {
+	l.once.Do(func() { l.pool.release(l.entry, false) })
+}
+
+// Discard closes the leased browser instead of returning it, e.g. after it crashed.
+func (l *Lease) Discard() {
+	l.once.Do(func() { l.pool.release(l.entry, true) })
+}
+
+// Acquire returns the warm browser for key, launching one if needed. It waits
+// while another caller holds the same key or the pool is full, until ctx is done.
+func (p *Pool) Acquire(ctx context.Context, key string, launch LaunchFunc) (*Lease, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrPoolClosed
+		}
+
+		if e, ok := p.entries[key]; ok {
+			if e.busy {
+				if err := p.waitLocked(ctx); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			e.busy = true
+			p.mu.Unlock()
+
+			if p.alive(e.browser) {
+				p.mu.Lock()
+				p.reuses++
+				p.mu.Unlock()
+				return &Lease{pool: p, entry: e}, nil
+			}
+			logrus.Warnf("browser pool: browser of %s is dead, relaunching", key)
+			p.release(e, true)
+			continue
+		}
+
+		if p.cfg.MaxBrowsers > 0 && len(p.entries) >= p.cfg.MaxBrowsers {
+			victim := p.lruIdleLocked()
+			if victim == nil {
+				if err := p.waitLocked(ctx); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			logrus.Infof("browser pool: full, evicting idle browser of %s", victim.key)
+			p.removeLocked(victim)
+			p.evictions++
+			p.mu.Unlock()
+			victim.browser.Close()
+			continue
+		}
+
+		// 先占位，避免同一账号并发启动两个浏览器，同时计入上限
+		e := &poolEntry{key: key, busy: true}
+		p.entries[key] = e
+		p.mu.Unlock()
+
+		b, err := safeLaunch(launch)
+
+		p.mu.Lock()
+		if err != nil {
+			p.removeLocked(e)
+			p.mu.Unlock()
+			return nil, err
+		}
+		now := time.Now()
+		e.browser = b
+		e.launchedAt = now
+		e.lastUsed = now
+		p.launches++
+		p.mu.Unlock()
+		return &Lease{pool: p, entry: e}, nil
+	}
+}
+
+// safeLaunch runs launch and turns a panic (e.g. from a Must* call) into an
+// error, so the placeholder entry is always removed.
+func safeLaunch(launch LaunchFunc) (b *Browser, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			b, err = nil, errors.Errorf("launch browser panicked: %v", r)
+		}
+	}()
+	return launch()
+}
+
+// Evict closes the pooled browser of key. A browser that is currently leased
+// is closed as soon as it is released.
+func (p *Pool) Evict(key string) {
+	p.mu.Lock()
+	e, ok := p.entries[key]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	if e.busy {
+		e.retire = true
+		p.mu.Unlock()
+		return
+	}
+	p.removeLocked(e)
+	p.evictions++
+	p.mu.Unlock()
+	e.browser.Close()
+}
+
+// Stats returns a snapshot of the pool.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	st := PoolStats{
+		MaxBrowsers: p.cfg.MaxBrowsers,
+		IdleTTL:     p.cfg.IdleTTL.String(),
+		Launches:    p.launches,
+		Reuses:      p.reuses,
+		Evictions:   p.evictions,
+		Accounts:    make([]PoolAccountStats, 0, len(p.entries)),
+	}
+	for _, e := range p.entries {
+		if e.browser == nil {
+			// 正在启动
+			continue
+		}
+		st.Browsers++
+		if e.busy {
+			st.InUse++
+		} else {
+			st.Idle++
+		}
+		st.Accounts = append(st.Accounts, PoolAccountStats{
+			Account:    e.key,
+			InUse:      e.busy,
+			Uses:       e.uses,
+			LaunchedAt: e.launchedAt,
+			LastUsedAt: e.lastUsed,
+		})
+	}
+	sort.Slice(st.Accounts, func(i, j int) bool {
+		return st.Accounts[i].Account < st.Accounts[j].Account
+	})
+	return st
+}
+
+// Close closes all idle browsers; leased browsers are closed on release.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.stop)
+	var idle []*poolEntry
+	for _, e := range p.entries {
+		if e.busy {
+			e.retire = true
+			continue
+		}
+		p.removeLocked(e)
+		idle = append(idle, e)
+	}
+	p.mu.Unlock()
+
+	for _, e := range idle {
+		e.browser.Close()
+	}
+	p.wg.Wait()
+}
+
+func (p *Pool) release(e *poolEntry, discard bool) {
+	p.mu.Lock()
+	e.busy = false
+	e.uses++
+	e.lastUsed = time.Now()
+	closeIt := discard || e.retire || p.closed
+	if closeIt {
+		p.removeLocked(e)
+		if !discard {
+			p.evictions++
+		}
+	}
+	p.broadcastLocked()
+	p.mu.Unlock()
+
+	if closeIt && e.browser != nil {
+		e.browser.Close()
+	}
+}
+
+func (p *Pool) evictLoop() {
+	defer p.wg.Done()
+	interval := p.cfg.IdleTTL / 2
+	if interval > 30*time.Second {
+		interval = 30 * time.Second
+	}
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.evictIdle(time.Now())
+		}
+	}
+}
+
+// evictIdle closes browsers idle for longer than IdleTTL.
+func (p *Pool) evictIdle(now time.Time) {
+	p.mu.Lock()
+	var expired []*poolEntry
+	for _, e := range p.entries {
+		if e.busy || now.Sub(e.lastUsed) < p.cfg.IdleTTL {
+			continue
+		}
+		p.removeLocked(e)
+		p.evictions++
+		expired = append(expired, e)
+	}
+	p.mu.Unlock()
+
+	for _, e := range expired {
+		logrus.Infof("browser pool: closing idle browser of %s", e.key)
+		e.browser.Close()
+	}
+}
+
+func (p *Pool) lruIdleLocked() *poolEntry {
+	var victim *poolEntry
+	for _, e := range p.entries {
+		if e.busy {
+			continue
+		}
+		if victim == nil || e.lastUsed.Before(victim.lastUsed) {
+			victim = e
+		}
+	}
+	return victim
+}
+
+func (p *Pool) removeLocked(e *poolEntry) {
+	if p.entries[e.key] == e {
+		delete(p.entries, e.key)
+	}
+	p.broadcastLocked()
+}
+
+func (p *Pool) broadcastLocked() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// waitLocked releases the lock and blocks until the pool changes or ctx is done.
+func (p *Pool) waitLocked(ctx context.Context) error {
+	ch := p.changed
+	p.mu.Unlock()
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "wait for pooled browser")
+	}
+}
+
+// Alive reports whether the underlying Chromium still responds.
+func (b *Browser) Alive() bool {
+	if b == nil || b.browser == nil {
+		return false
+	}
+	_, err := proto.BrowserGetVersion{}.Call(b.browser.Timeout(3 * time.Second))
+	return err == nil
+}
@@ -0,0 +1,139 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPool 使用空 Browser 代替真实 Chromium，Close 对空 Browser 是安全的
+func newTestPool(cfg PoolConfig) (*Pool, *int) {
+	p := NewPool(cfg)
+	p.alive = func(b *Browser) bool { return b != nil }
+	launches := 0
+	return p, &launches
+}
+
+func fakeLaunch(count *int) LaunchFunc {
+	return func() (*Browser, error) {
+		*count++
+		return &Browser{}, nil
+	}
+}
+
+func TestPoolReuseAndExclusive(t *testing.T) {
+	p, launches := newTestPool(PoolConfig{})
+	defer p.Close()
+	ctx := context.Background()
+
+	lease, err := p.Acquire(ctx, "acc_1", fakeLaunch(launches))
+	require.NoError(t, err)
+
+	// 同一账号在释放前不能再次获取
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = p.Acquire(waitCtx, "acc_1", fakeLaunch(launches))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	first := lease.Browser()
+	lease.Release()
+
+	lease, err = p.Acquire(ctx, "acc_1", fakeLaunch(launches))
+	require.NoError(t, err)
+	assert.Same(t, first, lease.Browser())
+	lease.Release()
+
+	assert.Equal(t, 1, *launches)
+	st := p.Stats()
+	assert.Equal(t, 1, st.Browsers)
+	assert.Equal(t, 1, st.Idle)
+	assert.EqualValues(t, 1, st.Reuses)
+}
+
+func TestPoolLaunchPanic(t *testing.T) {
+	p, launches := newTestPool(PoolConfig{MaxBrowsers: 1})
+	defer p.Close()
+	ctx := context.Background()
+
+	_, err := p.Acquire(ctx, "acc_1", func() (*Browser, error) {
+		panic("set cookies failed")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "set cookies failed")
+
+	// 占位已移除：同一账号可以立即重新启动，也不占用上限
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	lease, err := p.Acquire(waitCtx, "acc_1", fakeLaunch(launches))
+	require.NoError(t, err)
+	lease.Release()
+	assert.Equal(t, 1, *launches)
+	assert.Equal(t, 1, p.Stats().Browsers)
+}
+
+func TestPoolMaxBrowsersEvictsLRU(t *testing.T) {
+	p, launches := newTestPool(PoolConfig{MaxBrowsers: 1})
+	defer p.Close()
+	ctx := context.Background()
+
+	lease, err := p.Acquire(ctx, "acc_1", fakeLaunch(launches))
+	require.NoError(t, err)
+
+	// 池满且唯一的浏览器正在使用，需要等待
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = p.Acquire(waitCtx, "acc_2", fakeLaunch(launches))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	lease.Release()
+
+	lease, err = p.Acquire(ctx, "acc_2", fakeLaunch(launches))
+	require.NoError(t, err)
+	lease.Release()
+
+	st := p.Stats()
+	assert.Equal(t, 1, st.Browsers)
+	assert.Equal(t, "acc_2", st.Accounts[0].Account)
+	assert.EqualValues(t, 1, st.Evictions)
+}
+
+func TestPoolIdleEviction(t *testing.T) {
+	p, launches := newTestPool(PoolConfig{IdleTTL: time.Minute})
+	defer p.Close()
+
+	lease, err := p.Acquire(context.Background(), "acc_1", fakeLaunch(launches))
+	require.NoError(t, err)
+	lease.Release()
+
+	p.evictIdle(time.Now())
+	assert.Equal(t, 1, p.Stats().Browsers)
+
+	p.evictIdle(time.Now().Add(2 * time.Minute))
+	assert.Equal(t, 0, p.Stats().Browsers)
+}
+
+func TestPoolEvictBusyAndDeadBrowser(t *testing.T) {
+	p, launches := newTestPool(PoolConfig{})
+	defer p.Close()
+	ctx := context.Background()
+
+	lease, err := p.Acquire(ctx, "acc_1", fakeLaunch(launches))
+	require.NoError(t, err)
+	p.Evict("acc_1")
+	assert.Equal(t, 1, p.Stats().Browsers, "leased browser must stay until released")
+	lease.Release()
+	assert.Equal(t, 0, p.Stats().Browsers)
+
+	lease, err = p.Acquire(ctx, "acc_1", fakeLaunch(launches))
+	require.NoError(t, err)
+	lease.Release()
+
+	p.alive = func(*Browser) bool { return false }
+	lease, err = p.Acquire(ctx, "acc_1", fakeLaunch(launches))
+	require.NoError(t, err)
+	lease.Release()
+	assert.Equal(t, 3, *launches)
+}
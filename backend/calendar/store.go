@@ -0,0 +1,450 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Kind 日历条目的内容类型
+type Kind string
+
+const (
+	KindImage Kind = "image"
+	KindVideo Kind = "video"
+)
+
+// Status 日历条目状态
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusPublishing Status = "publishing"
+	StatusPublished  Status = "published"
+	StatusFailed     Status = "failed"
+	StatusCancelled  Status = "cancelled"
+)
+
+var (
+	// ErrNotFound 条目不存在
+	ErrNotFound = errors.New("calendar entry not found")
+	// ErrNotPending 条目已经发布、正在发布或已取消，不能再修改
+	ErrNotPending = errors.New("calendar entry is not pending")
+	// ErrInterrupted 发布过程中服务退出，无法确定是否已经发布
+	ErrInterrupted = errors.New("service stopped while publishing, outcome unknown")
+	// ErrMissed 服务停止期间错过了发布时间，且超过了允许的最大延迟
+	ErrMissed = errors.New("missed publish time")
+)
+
+// Entry 内容日历中的一条待发布内容，到点后由服务端自行发布。
+type Entry struct {
+	ID          string          `json:"id"`
+	AccountID   int             `json:"account_id"`
+	AccountKey  string          `json:"account_key"`
+	Kind        Kind            `json:"kind"`
+	Title       string          `json:"title"`
+	Content     string          `json:"content"`
+	Images      []string        `json:"images,omitempty"`
+	Video       string          `json:"video,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	PublishAt   time.Time       `json:"publish_at"`
+	Status      Status          `json:"status"`
+	Error       string          `json:"error,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	PublishedAt *time.Time      `json:"published_at,omitempty"`
+}
+
+// Filter 列表筛选条件，零值表示不过滤。
+type Filter struct {
+	AccountKey string
+	Status     Status
+	From       time.Time
+	To         time.Time
+}
+
+// Update 修改待发布条目的字段，nil 表示不修改。
+type Update struct {
+	Title     *string
+	Content   *string
+	Images    []string
+	Video     *string
+	Tags      []string
+	PublishAt *time.Time
+}
+
+// Options 日历存储配置
+type Options struct {
+	// MaxLateness 条目最多可以晚于发布时间多久发出，超过后标记为失败而不再发布；<= 0 表示不限制
+	MaxLateness time.Duration
+}
+
+// Store 内容日历的本地持久化存储。
+type Store struct {
+	mu        sync.Mutex
+	entries   map[string]*Entry
+	storePath string
+	opts      Options
+	changed   chan struct{}
+}
+
+// NewStore 创建日历存储并从 storePath 恢复条目。
+// 上次退出时仍处于 publishing 状态的条目无法确定是否已经发出，会被标记为失败，不会自动重新发布。
+func NewStore(storePath string, opts Options) (*Store, error) {
+	s := &Store{
+		entries:   map[string]*Entry{},
+		storePath: storePath,
+		opts:      opts,
+		changed:   make(chan struct{}, 1),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Changed 条目新增或时间变化时收到通知，供调度器重新计算下一次触发时间。
+func (s *Store) Changed() <-chan struct{} {
+	return s.changed
+}
+
+// Add 新增一条待发布条目。
+func (s *Store) Add(e Entry) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e.ID = newEntryID()
+	e.Status = StatusPending
+	e.Error = ""
+	e.Result = nil
+	e.PublishedAt = nil
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	s.entries[e.ID] = &e
+	if err := s.saveLocked(); err != nil {
+		delete(s.entries, e.ID)
+		return nil, err
+	}
+	s.notify()
+
+	copyEntry := e
+	return &copyEntry, nil
+}
+
+// Get 按 ID 获取条目快照。
+func (s *Store) Get(id string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok {
+		return nil, errors.Wrapf(ErrNotFound, "entry %s", id)
+	}
+	copyEntry := *e
+	return &copyEntry, nil
+}
+
+// List 返回按发布时间升序排列的条目快照。
+func (s *Store) List(f Filter) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		if f.AccountKey != "" && e.AccountKey != f.AccountKey {
+			continue
+		}
+		if f.Status != "" && e.Status != f.Status {
+			continue
+		}
+		if !f.From.IsZero() && e.PublishAt.Before(f.From) {
+			continue
+		}
+		if !f.To.IsZero() && e.PublishAt.After(f.To) {
+			continue
+		}
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].PublishAt.Before(out[j].PublishAt)
+	})
+	return out
+}
+
+// Modify 修改待发布条目。
+func (s *Store) Modify(id string, u Update) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.pendingLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	old := *e
+	if u.Title != nil {
+		e.Title = *u.Title
+	}
+	if u.Content != nil {
+		e.Content = *u.Content
+	}
+	if u.Images != nil {
+		e.Images = u.Images
+	}
+	if u.Video != nil {
+		e.Video = *u.Video
+	}
+	if u.Tags != nil {
+		e.Tags = u.Tags
+	}
+	if u.PublishAt != nil {
+		e.PublishAt = *u.PublishAt
+	}
+	e.UpdatedAt = time.Now()
+	if err := s.saveLocked(); err != nil {
+		*e = old
+		return nil, err
+	}
+	if u.PublishAt != nil {
+		s.notify()
+	}
+
+	copyEntry := *e
+	return &copyEntry, nil
+}
+
+// Move 调整待发布条目的发布时间。
+func (s *Store) Move(id string, when time.Time) (*Entry, error) {
+	return s.Modify(id, Update{PublishAt: &when})
+}
+
+// Cancel 取消待发布条目，条目保留在日历中作为记录。
+func (s *Store) Cancel(id string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.pendingLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	e.Status = StatusCancelled
+	e.UpdatedAt = time.Now()
+	if err := s.saveLocked(); err != nil {
+		e.Status = StatusPending
+		return nil, err
+	}
+	copyEntry := *e
+	return &copyEntry, nil
+}
+
+// NextDue 返回最早一条可以领取的待发布条目的发布时间，没有时 ok 为 false。
+// 账号已有条目在发布时，该账号的其他条目要等它结束（Finish/Release 会发出 Changed 通知）。
+func (s *Store) NextDue() (when time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	busy := s.busyAccountsLocked()
+	for _, e := range s.entries {
+		if e.Status != StatusPending || busy[e.AccountKey] {
+			continue
+		}
+		if !ok || e.PublishAt.Before(when) {
+			when, ok = e.PublishAt, true
+		}
+	}
+	return when, ok
+}
+
+// ClaimNext 领取一条已到发布时间的待发布条目并标记为 publishing，没有可领取的条目时 ok 为 false。
+// 每个账号同时只有一条条目处于 publishing；其余条目保持 pending，在领取前仍可修改或取消。
+// 超过 MaxLateness 仍未发布的条目直接标记为失败。
+func (s *Store) ClaimNext(now time.Time) (entry Entry, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	busy := s.busyAccountsLocked()
+	var next *Entry
+	for _, e := range s.entries {
+		if e.Status != StatusPending || e.PublishAt.After(now) {
+			continue
+		}
+		if s.opts.MaxLateness > 0 && now.Sub(e.PublishAt) > s.opts.MaxLateness {
+			logrus.Warnf("calendar: entry %s missed its publish time %s", e.ID, e.PublishAt.Format(time.RFC3339))
+			e.Status = StatusFailed
+			e.Error = fmt.Sprintf("%v: %s late, max %s", ErrMissed, now.Sub(e.PublishAt).Round(time.Second), s.opts.MaxLateness)
+			e.UpdatedAt = now
+			changed = true
+			continue
+		}
+		if busy[e.AccountKey] {
+			continue
+		}
+		if next == nil || e.PublishAt.Before(next.PublishAt) {
+			next = e
+		}
+	}
+	if next != nil {
+		next.Status = StatusPublishing
+		next.UpdatedAt = now
+		entry, ok = *next, true
+		changed = true
+	}
+	if changed {
+		if err := s.saveLocked(); err != nil {
+			// 写盘失败不影响本次发布
+			logrus.Warnf("persist calendar failed: %v", err)
+		}
+	}
+	return entry, ok
+}
+
+// Release 把尚未提交发布的条目放回 pending，供服务退出时使用。
+func (s *Store) Release(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok {
+		return errors.Wrapf(ErrNotFound, "entry %s", id)
+	}
+	if e.Status != StatusPublishing {
+		return nil
+	}
+	e.Status = StatusPending
+	e.UpdatedAt = time.Now()
+	s.notify()
+	return s.saveLocked()
+}
+
+// Finish 记录条目的发布结果。
+func (s *Store) Finish(id string, result any, runErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok {
+		return errors.Wrapf(ErrNotFound, "entry %s", id)
+	}
+	now := time.Now()
+	e.UpdatedAt = now
+	if runErr != nil {
+		e.Status = StatusFailed
+		e.Error = runErr.Error()
+	} else {
+		e.Status = StatusPublished
+		e.Error = ""
+		e.PublishedAt = &now
+		if result != nil {
+			if data, err := json.Marshal(result); err == nil {
+				e.Result = data
+			}
+		}
+	}
+	// 同账号的下一条条目可以开始发布
+	s.notify()
+	return s.saveLocked()
+}
+
+func (s *Store) pendingLocked(id string) (*Entry, error) {
+	e, ok := s.entries[id]
+	if !ok {
+		return nil, errors.Wrapf(ErrNotFound, "entry %s", id)
+	}
+	if e.Status != StatusPending {
+		return nil, errors.Wrapf(ErrNotPending, "entry %s is %s", id, e.Status)
+	}
+	return e, nil
+}
+
+// busyAccountsLocked 返回有条目正在发布的账号
+func (s *Store) busyAccountsLocked() map[string]bool {
+	busy := map[string]bool{}
+	for _, e := range s.entries {
+		if e.Status == StatusPublishing {
+			busy[e.AccountKey] = true
+		}
+	}
+	return busy
+}
+
+func (s *Store) notify() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Store) saveLocked() error {
+	if s.storePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.storePath), 0o755); err != nil {
+		return err
+	}
+	list := make([]*Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	data, err := json.MarshalIndent(struct {
+		Entries []*Entry `json:"entries"`
+	}{Entries: list}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.storePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.storePath)
+}
+
+func (s *Store) load() error {
+	if s.storePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.storePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var payload struct {
+		Entries []*Entry `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return errors.Wrapf(err, "parse calendar store %s", s.storePath)
+	}
+	interrupted := 0
+	for _, e := range payload.Entries {
+		if e.Status == StatusPublishing {
+			// 可能已经点击了发布，重新发布会重复发帖
+			logrus.Warnf("calendar: entry %s was publishing when the service stopped, marking as failed", e.ID)
+			e.Status = StatusFailed
+			e.Error = ErrInterrupted.Error()
+			e.UpdatedAt = time.Now()
+			interrupted++
+		}
+		s.entries[e.ID] = e
+	}
+	if interrupted > 0 {
+		return s.saveLocked()
+	}
+	return nil
+}
+
+func newEntryID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("cal_%d", time.Now().UnixNano())
+	}
+	return "cal_" + hex.EncodeToString(b)
+}
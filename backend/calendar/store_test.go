@@ -0,0 +1,138 @@
+package calendar
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreLifecycle(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), "calendar.json"), Options{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	later, err := s.Add(Entry{AccountKey: "acc_1", Kind: KindImage, Title: "晚", PublishAt: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	earlier, err := s.Add(Entry{AccountKey: "acc_2", Kind: KindVideo, Title: "早", PublishAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, later.Status)
+
+	list := s.List(Filter{})
+	require.Len(t, list, 2)
+	assert.Equal(t, earlier.ID, list[0].ID, "list should be ordered by publish_at")
+	assert.Len(t, s.List(Filter{AccountKey: "acc_1"}), 1)
+
+	next, ok := s.NextDue()
+	require.True(t, ok)
+	assert.True(t, next.Equal(earlier.PublishAt))
+
+	moved, err := s.Move(later.ID, now.Add(30*time.Minute))
+	require.NoError(t, err)
+	next, _ = s.NextDue()
+	assert.True(t, next.Equal(moved.PublishAt))
+
+	_, err = s.Cancel(earlier.ID)
+	require.NoError(t, err)
+	_, err = s.Move(earlier.ID, now.Add(time.Hour))
+	assert.True(t, errors.Is(err, ErrNotPending))
+
+	_, err = s.Get("cal_missing")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestClaimNextAndFinish(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "calendar.json")
+	s, err := NewStore(storePath, Options{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	due, err := s.Add(Entry{AccountKey: "acc_1", Kind: KindImage, PublishAt: now.Add(time.Minute)})
+	require.NoError(t, err)
+	_, err = s.Add(Entry{AccountKey: "acc_1", Kind: KindImage, PublishAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+
+	_, ok := s.ClaimNext(now)
+	assert.False(t, ok)
+
+	claimed, ok := s.ClaimNext(now.Add(2 * time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, due.ID, claimed.ID)
+	_, ok = s.ClaimNext(now.Add(2 * time.Minute))
+	assert.False(t, ok, "claimed entries must not be returned twice")
+
+	// 发布中途退出：重启后无法确定是否已发出，标记为失败而不是重新发布
+	reloaded, err := NewStore(storePath, Options{})
+	require.NoError(t, err)
+	entry, err := reloaded.Get(due.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, entry.Status)
+	assert.Equal(t, ErrInterrupted.Error(), entry.Error)
+
+	require.NoError(t, s.Finish(due.ID, map[string]string{"status": "发布完成"}, nil))
+	entry, err = s.Get(due.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPublished, entry.Status)
+	assert.NotNil(t, entry.PublishedAt)
+	assert.JSONEq(t, `{"status":"发布完成"}`, string(entry.Result))
+}
+
+func TestClaimNextPerAccount(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), "calendar.json"), Options{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	a1, err := s.Add(Entry{AccountKey: "acc_1", Kind: KindImage, PublishAt: now.Add(time.Minute)})
+	require.NoError(t, err)
+	a2, err := s.Add(Entry{AccountKey: "acc_1", Kind: KindImage, PublishAt: now.Add(2 * time.Minute)})
+	require.NoError(t, err)
+	b1, err := s.Add(Entry{AccountKey: "acc_2", Kind: KindImage, PublishAt: now.Add(3 * time.Minute)})
+	require.NoError(t, err)
+
+	later := now.Add(5 * time.Minute)
+	first, ok := s.ClaimNext(later)
+	require.True(t, ok)
+	assert.Equal(t, a1.ID, first.ID)
+
+	// acc_1 正在发布，下一条领取的是 acc_2 的条目
+	second, ok := s.ClaimNext(later)
+	require.True(t, ok)
+	assert.Equal(t, b1.ID, second.ID)
+	_, ok = s.ClaimNext(later)
+	assert.False(t, ok)
+	_, ok = s.NextDue()
+	assert.False(t, ok, "entries of busy accounts are not due")
+
+	// 未领取的条目仍可取消
+	_, err = s.Cancel(a2.ID)
+	require.NoError(t, err)
+
+	// 放回后可以再次领取
+	require.NoError(t, s.Release(first.ID))
+	again, ok := s.ClaimNext(later)
+	require.True(t, ok)
+	assert.Equal(t, a1.ID, again.ID)
+}
+
+func TestClaimNextMissed(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), "calendar.json"), Options{MaxLateness: 30 * time.Minute})
+	require.NoError(t, err)
+
+	now := time.Now()
+	missed, err := s.Add(Entry{AccountKey: "acc_1", Kind: KindImage, PublishAt: now.Add(time.Minute)})
+	require.NoError(t, err)
+	late, err := s.Add(Entry{AccountKey: "acc_1", Kind: KindImage, PublishAt: now.Add(40 * time.Minute)})
+	require.NoError(t, err)
+
+	claimed, ok := s.ClaimNext(now.Add(time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, late.ID, claimed.ID)
+
+	entry, err := s.Get(missed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, entry.Status)
+	assert.Contains(t, entry.Error, ErrMissed.Error())
+}
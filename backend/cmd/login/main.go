@@ -9,6 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
@@ -19,6 +20,12 @@ func main() {
 	flag.StringVar(&binPath, "bin", "", "浏览器二进制文件路径")
 	flag.Parse()
 
+	box, err := secret.FromEnv()
+	if err != nil {
+		logrus.Fatalf("failed to load encryption key: %v", err)
+	}
+	secret.SetDefault(box)
+
 	// 登录的时候，需要界面，所以不能无头模式
 	b, err := browser.New(browser.Config{
 		Headless: false,
@@ -0,0 +1,192 @@
+package concurrency
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrAccountBusy 账号正在执行其他操作，且在等待时间内未结束
+	ErrAccountBusy = errors.New("account is busy with another operation")
+	// ErrNoCapacity 同时运行的浏览器操作已达上限，且在等待时间内没有空位
+	ErrNoCapacity = errors.New("too many concurrent browser operations")
+)
+
+// IsBusy 判断错误是否由账号忙或并发已满引起
+func IsBusy(err error) bool {
+	return errors.Is(err, ErrAccountBusy) || errors.Is(err, ErrNoCapacity)
+}
+
+// WaitForever 作为等待时间时表示一直等到 ctx 结束
+const WaitForever time.Duration = -1
+
+type waitKey struct{}
+
+// WithWait 覆盖本次操作的排队等待时间：0 表示忙时立即返回错误，WaitForever 表示一直等待
+func WithWait(ctx context.Context, wait time.Duration) context.Context {
+	return context.WithValue(ctx, waitKey{}, wait)
+}
+
+func waitFrom(ctx context.Context, def time.Duration) time.Duration {
+	if v, ok := ctx.Value(waitKey{}).(time.Duration); ok {
+		return v
+	}
+	return def
+}
+
+// Config 并发限制配置
+type Config struct {
+	// MaxParallel 所有账号同时运行的浏览器操作上限，<= 0 表示不限制
+	MaxParallel int
+	// Wait 默认排队等待时间，可通过 WithWait 按次覆盖
+	Wait time.Duration
+}
+
+// Limiter 保证同一账号的操作依次执行，并限制跨账号的总并发数
+type Limiter struct {
+	cfg    Config
+	global chan struct{}
+
+	mu       sync.Mutex
+	accounts map[string]*accountSlot
+}
+
+type accountSlot struct {
+	ch   chan struct{}
+	refs int
+}
+
+// Stats 并发状态快照
+type Stats struct {
+	Running     int      `json:"running"`
+	MaxParallel int      `json:"max_parallel"`
+	Wait        string   `json:"wait"`
+	Accounts    []string `json:"busy_accounts"`
+}
+
+// NewLimiter 创建并发限制器
+func NewLimiter(cfg Config) *Limiter {
+	l := &Limiter{
+		cfg:      cfg,
+		accounts: map[string]*accountSlot{},
+	}
+	if cfg.MaxParallel > 0 {
+		l.global = make(chan struct{}, cfg.MaxParallel)
+	}
+	return l
+}
+
+// Acquire 获取账号的独占执行权和一个全局并发名额，返回的 release 必须调用。
+// 排队超时返回 ErrAccountBusy 或 ErrNoCapacity；ctx 本身结束时返回 ctx 的错误。
+func (l *Limiter) Acquire(ctx context.Context, key string) (release func(), err error) {
+	wait := waitFrom(ctx, l.cfg.Wait)
+	waitCtx := ctx
+	if wait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, wait)
+		defer cancel()
+	}
+
+	slot := l.ref(key)
+	if err := take(ctx, waitCtx, slot.ch, wait == 0); err != nil {
+		l.unref(key)
+		return nil, wrapBusy(err, ErrAccountBusy, "account %s", key)
+	}
+
+	if l.global != nil {
+		if err := take(ctx, waitCtx, l.global, wait == 0); err != nil {
+			<-slot.ch
+			l.unref(key)
+			return nil, wrapBusy(err, ErrNoCapacity, "max %d", l.cfg.MaxParallel)
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if l.global != nil {
+				<-l.global
+			}
+			<-slot.ch
+			l.unref(key)
+		})
+	}, nil
+}
+
+// Stats 返回当前并发状态
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	st := Stats{
+		Running:     len(l.global),
+		MaxParallel: l.cfg.MaxParallel,
+		Wait:        l.cfg.Wait.String(),
+		Accounts:    make([]string, 0, len(l.accounts)),
+	}
+	for key, slot := range l.accounts {
+		if len(slot.ch) > 0 {
+			st.Accounts = append(st.Accounts, key)
+		}
+	}
+	if l.global == nil {
+		st.Running = len(st.Accounts)
+	}
+	sort.Strings(st.Accounts)
+	return st
+}
+
+func (l *Limiter) ref(key string) *accountSlot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slot, ok := l.accounts[key]
+	if !ok {
+		slot = &accountSlot{ch: make(chan struct{}, 1)}
+		l.accounts[key] = slot
+	}
+	slot.refs++
+	return slot
+}
+
+func (l *Limiter) unref(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slot := l.accounts[key]
+	slot.refs--
+	if slot.refs == 0 {
+		delete(l.accounts, key)
+	}
+}
+
+// take 占用 ch 中的一个名额；noWait 时不排队
+func take(ctx, waitCtx context.Context, ch chan struct{}, noWait bool) error {
+	select {
+	case ch <- struct{}{}:
+		return nil
+	default:
+	}
+	if noWait {
+		return errNoWait
+	}
+	select {
+	case ch <- struct{}{}:
+		return nil
+	case <-waitCtx.Done():
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return errNoWait
+	}
+}
+
+var errNoWait = errors.New("wait timeout")
+
+func wrapBusy(err, busy error, format string, args ...any) error {
+	if err == errNoWait {
+		return errors.Wrapf(busy, format, args...)
+	}
+	return errors.Wrap(err, "wait for browser slot")
+}
@@ -0,0 +1,105 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountSerialization(t *testing.T) {
+	l := NewLimiter(Config{Wait: WaitForever})
+	ctx := context.Background()
+
+	var (
+		mu      sync.Mutex
+		running int
+		maxSeen int
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.Acquire(ctx, "acc_1")
+			require.NoError(t, err)
+			defer release()
+
+			mu.Lock()
+			running++
+			if running > maxSeen {
+				maxSeen = running
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, maxSeen)
+	assert.Empty(t, l.Stats().Accounts)
+}
+
+func TestAccountBusy(t *testing.T) {
+	l := NewLimiter(Config{Wait: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	release, err := l.Acquire(ctx, "acc_1")
+	require.NoError(t, err)
+
+	_, err = l.Acquire(ctx, "acc_1")
+	assert.True(t, errors.Is(err, ErrAccountBusy))
+
+	_, err = l.Acquire(WithWait(ctx, 0), "acc_1")
+	assert.True(t, IsBusy(err))
+
+	// 其他账号不受影响
+	other, err := l.Acquire(ctx, "acc_2")
+	require.NoError(t, err)
+	other()
+
+	assert.Equal(t, []string{"acc_1"}, l.Stats().Accounts)
+	release()
+	release() // 重复释放无副作用
+
+	release, err = l.Acquire(WithWait(ctx, 0), "acc_1")
+	require.NoError(t, err)
+	release()
+}
+
+func TestGlobalCapacity(t *testing.T) {
+	l := NewLimiter(Config{MaxParallel: 1, Wait: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	release, err := l.Acquire(ctx, "acc_1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, l.Stats().Running)
+
+	_, err = l.Acquire(ctx, "acc_2")
+	assert.True(t, errors.Is(err, ErrNoCapacity))
+
+	// 失败的获取不能占住账号
+	release()
+	release, err = l.Acquire(WithWait(ctx, 0), "acc_2")
+	require.NoError(t, err)
+	release()
+}
+
+func TestAcquireContextCancelled(t *testing.T) {
+	l := NewLimiter(Config{Wait: WaitForever})
+	release, err := l.Acquire(context.Background(), "acc_1")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, "acc_1")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, IsBusy(err))
+}
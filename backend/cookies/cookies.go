@@ -3,8 +3,11 @@ package cookies
 import (
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
 )
 
 type Cookier interface {
@@ -17,14 +20,89 @@ type localCookie struct {
 	path string
 }
 
-func NewLoadCookie(path string) Cookier {
+// Backend 按 cookies 路径创建底层存储，返回的 Cookier 只负责原样读写字节。
+// 数据不存在时 LoadCookies 返回的错误应满足 errors.Is(err, os.ErrNotExist)。
+type Backend func(path string) Cookier
+
+var (
+	backendMu sync.RWMutex
+	backend   Backend = NewFileCookie
+)
+
+// SetBackend 替换 cookies 的存储后端，nil 恢复为本地文件。需在服务启动前调用。
+func SetBackend(b Backend) {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	if b == nil {
+		b = NewFileCookie
+	}
+	backend = b
+}
+
+// NewFileCookie 返回按文件存储的 Cookier，不经过加密层。
+func NewFileCookie(path string) Cookier {
+	return &localCookie{path: path}
+}
+
+// NewRawCookie 返回当前后端的 Cookier，不经过加密层，供密钥轮换与数据导入使用。
+func NewRawCookie(path string) Cookier {
 	if path == "" {
 		panic("path is required")
 	}
 
-	return &localCookie{
-		path: path,
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend(path)
+}
+
+// NewLoadCookie 返回当前后端（默认本地文件）的 Cookier，读写经过 secret.Default() 加密层；
+// 未启用加密时按明文读写，但遇到已加密的数据会返回 secret.ErrNoKey。
+func NewLoadCookie(path string) Cookier {
+	return NewEncryptedCookie(NewRawCookie(path), secret.Default())
+}
+
+type encryptedCookie struct {
+	inner Cookier
+	box   *secret.Box
+}
+
+// NewEncryptedCookie 用 box 加密 inner 中保存的 cookies，box 为 nil 时不加密。
+// 读取到旧的明文 cookies 时会立即以密文重新写入。
+func NewEncryptedCookie(inner Cookier, box *secret.Box) Cookier {
+	return &encryptedCookie{inner: inner, box: box}
+}
+
+func (c *encryptedCookie) LoadCookies() ([]byte, error) {
+	data, err := c.inner.LoadCookies()
+	if err != nil {
+		return nil, err
+	}
+	if !secret.IsSealed(data) {
+		if c.box == nil {
+			return data, nil
+		}
+		if err := c.SaveCookies(data); err != nil {
+			logrus.Warnf("encrypt plaintext cookies failed: %v", err)
+		}
+		return data, nil
+	}
+	plain, err := c.box.Open(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt cookies")
 	}
+	return plain, nil
+}
+
+func (c *encryptedCookie) SaveCookies(data []byte) error {
+	sealed, err := c.box.Seal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt cookies")
+	}
+	return c.inner.SaveCookies(sealed)
+}
+
+func (c *encryptedCookie) DeleteCookies() error {
+	return c.inner.DeleteCookies()
 }
 
 // LoadCookies 从文件中加载 cookies。
@@ -44,7 +122,12 @@ func (c *localCookie) SaveCookies(data []byte) error {
 	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
 		return errors.Wrap(err, "failed to create cookies directory")
 	}
-	return os.WriteFile(c.path, data, 0644)
+	// 先写临时文件再重命名，避免写到一半时留下损坏的 cookies
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
 }
 
 // DeleteCookies 删除 cookies 文件。
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
@@ -11,9 +12,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
+	"github.com/xpzouying/xiaohongshu-mcp/artifacts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/calendar"
+	"github.com/xpzouying/xiaohongshu-mcp/concurrency"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
 	"github.com/xpzouying/xiaohongshu-mcp/session"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 	"golang.org/x/net/proxy"
@@ -22,9 +31,10 @@ import (
 // respondError 返回错误响应
 func respondError(c *gin.Context, statusCode int, code, message string, details any) {
 	response := ErrorResponse{
-		Error:   message,
-		Code:    code,
-		Details: details,
+		Error:      message,
+		Code:       code,
+		Details:    details,
+		ArtifactID: c.GetString("artifact_id"),
 	}
 
 	logrus.Errorf("%s %s %s %d", c.Request.Method, c.Request.URL.Path,
@@ -33,6 +43,37 @@ func respondError(c *gin.Context, statusCode int, code, message string, details
 	c.JSON(statusCode, response)
 }
 
+// respondServiceError 返回业务操作失败的响应；额度超限、账号忙或并发已满时返回对应状态码，调用方可稍后重试。
+// 浏览器操作失败时响应中带有失败现场的 artifact_id。
+func respondServiceError(c *gin.Context, code, message string, err error) {
+	if id := artifacts.IDOf(err); id != "" {
+		c.Set("artifact_id", id)
+	}
+	var exceeded *quota.ExceededError
+	switch {
+	case errors.As(err, &exceeded):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(exceeded.RetryAfter.Seconds()))))
+		respondError(c, http.StatusTooManyRequests, "QUOTA_EXCEEDED",
+			fmt.Sprintf("操作频率超限，请在 %s 后重试", exceeded.RetryAfter.Round(time.Second)), err.Error())
+	case errors.Is(err, concurrency.ErrAccountBusy):
+		respondError(c, http.StatusConflict, "ACCOUNT_BUSY", "账号正在执行其他操作，请稍后重试", err.Error())
+	case errors.Is(err, concurrency.ErrNoCapacity):
+		respondError(c, http.StatusServiceUnavailable, "BROWSERS_BUSY", "同时运行的浏览器操作已达上限，请稍后重试", err.Error())
+	case errors.Is(err, xiaohongshu.ErrInvalidCursor):
+		respondError(c, http.StatusBadRequest, "INVALID_CURSOR", "cursor 无效，请使用上一页返回的 next_cursor", err.Error())
+	case errors.Is(err, xiaohongshu.ErrCreatorNoteNotFound):
+		respondError(c, http.StatusNotFound, "NOTE_NOT_FOUND", "创作者中心中没有找到该笔记", err.Error())
+	case errors.Is(err, xiaohongshu.ErrInvalidDateRange):
+		respondError(c, http.StatusBadRequest, "INVALID_DATE_RANGE", "日期范围无效", err.Error())
+	case errors.Is(err, xiaohongshu.ErrConversationNotFound):
+		respondError(c, http.StatusNotFound, "CONVERSATION_NOT_FOUND", "没有找到与该用户的私信会话", err.Error())
+	case errors.Is(err, xiaohongshu.ErrConversationMismatch):
+		respondError(c, http.StatusConflict, "CONVERSATION_MISMATCH", "打开的会话或私信接收方与目标用户不一致", err.Error())
+	default:
+		respondError(c, http.StatusInternalServerError, code, message, err.Error())
+	}
+}
+
 // respondSuccess 返回成功响应
 func respondSuccess(c *gin.Context, data any, message string) {
 	response := SuccessResponse{
@@ -47,6 +88,19 @@ func respondSuccess(c *gin.Context, data any, message string) {
 	c.JSON(http.StatusOK, response)
 }
 
+// parsePageOptions 解析查询参数中的 limit 与 cursor
+func parsePageOptions(c *gin.Context) (xiaohongshu.PageOptions, error) {
+	opts := xiaohongshu.PageOptions{Cursor: c.Query("cursor")}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return opts, fmt.Errorf("limit 无效: %s", v)
+		}
+		opts.Limit = limit
+	}
+	return opts, nil
+}
+
 func parseAccountID(c *gin.Context) (int, error) {
 	if v := c.GetHeader("X-Account-ID"); v != "" {
 		return strconv.Atoi(v)
@@ -151,6 +205,9 @@ func (s *AppServer) bindAccountContext(c *gin.Context) (*accounts.Account, conte
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := apikey.CheckAccount(c.Request.Context(), id); err != nil {
+		return nil, nil, err
+	}
 	acc, err := s.accounts.Get(id)
 	if err != nil && id == 1 {
 		acc, err = s.accounts.Create("", "")
@@ -158,11 +215,32 @@ func (s *AppServer) bindAccountContext(c *gin.Context) (*accounts.Account, conte
 	if err != nil {
 		return nil, nil, err
 	}
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 	c.Set("account", acc.Key)
 	return acc, ctx, nil
 }
 
+// withRequestWait 读取 X-Wait-Timeout 请求头（如 "30s"，"0" 表示账号忙时立即返回），覆盖默认排队等待时间
+func withRequestWait(c *gin.Context, ctx context.Context) context.Context {
+	v := c.GetHeader("X-Wait-Timeout")
+	if v == "" {
+		return ctx
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		secs, aerr := strconv.Atoi(v)
+		if aerr != nil {
+			logrus.Warnf("ignore invalid X-Wait-Timeout %q", v)
+			return ctx
+		}
+		d = time.Duration(secs) * time.Second
+	}
+	if d < 0 {
+		d = 0
+	}
+	return concurrency.WithWait(ctx, d)
+}
+
 // startLoginHandler 创建/更新账号并生成登录二维码
 func (s *AppServer) startLoginHandler(c *gin.Context) {
 	logrus.Infof("start login request received")
@@ -181,6 +259,11 @@ func (s *AppServer) startLoginHandler(c *gin.Context) {
 	}
 	pcfg := buildProxyConfig(proxyVal, req.ProxyType, req.ProxyHost, req.ProxyPort, req.ProxyUser, req.ProxyPass)
 
+	// account_id 为 0 表示新建账号，只有不限账号的 API Key 可以新建
+	if err := apikey.CheckAccount(c.Request.Context(), req.AccountID); err != nil {
+		respondAccountError(c, err)
+		return
+	}
 	if req.AccountID == 0 {
 		acc, err = s.accounts.Create(proxyVal, req.Name)
 	} else {
@@ -199,11 +282,11 @@ func (s *AppServer) startLoginHandler(c *gin.Context) {
 		logrus.Warnf("apply proxy config failed: %v", err)
 	}
 
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 	logrus.Infof("begin login flow for account=%s(id=%d)", acc.Key, acc.ID)
 	if err := s.xiaohongshuService.LoginAndWait(ctx, 10*time.Minute); err != nil {
 		_ = s.accounts.Delete(acc.ID)
-		respondError(c, http.StatusInternalServerError, "LOGIN_FAILED", "登录失败", err.Error())
+		respondServiceError(c, "LOGIN_FAILED", "登录失败", err)
 		return
 	}
 
@@ -215,7 +298,7 @@ func (s *AppServer) startLoginHandler(c *gin.Context) {
 
 // listAccountsHandler 列出账号信息
 func (s *AppServer) listAccountsHandler(c *gin.Context) {
-	respondSuccess(c, s.accounts.List(), "获取账号列表成功")
+	respondSuccess(c, visibleAccounts(c.Request.Context(), s.accounts.List()), "获取账号列表成功")
 }
 
 // updateProxyHandler 更新账号代理并重新发起登录
@@ -231,21 +314,26 @@ func (s *AppServer) updateProxyHandler(c *gin.Context) {
 		return
 	}
 
+	if err := apikey.CheckAccount(c.Request.Context(), id); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
 	pcfg := buildProxyConfig(req.Proxy, req.ProxyType, req.ProxyHost, req.ProxyPort, req.ProxyUser, req.ProxyPass)
 	acc, err := s.accounts.UpdateProxy(id, req.Proxy, req.Name)
 	if err == nil {
 		acc, err = s.accounts.ApplyProxyConfig(id, pcfg)
 	}
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
 
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 	ctx = session.WithHeadless(ctx, false)
 	result, err := s.xiaohongshuService.GetLoginQrcode(ctx)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED", "获取登录二维码失败", err.Error())
+		respondServiceError(c, "STATUS_CHECK_FAILED", "获取登录二维码失败", err)
 		return
 	}
 
@@ -290,13 +378,49 @@ func (s *AppServer) deleteAccountHandler(c *gin.Context) {
 		respondError(c, http.StatusBadRequest, "INVALID_ACCOUNT_ID", "账号ID无效", err.Error())
 		return
 	}
+	acc, err := s.getAccount(c.Request.Context(), id)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
 	if err := s.accounts.Delete(id); err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
+	s.xiaohongshuService.EvictAccountBrowser(acc.Key)
 	respondSuccess(c, gin.H{"account_id": id}, "账号已删除")
 }
 
+// accountLoginsHandler 账号的登录历史，按时间倒序，limit 默认 20
+func (s *AppServer) accountLoginsHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_ACCOUNT_ID", "账号ID无效", err.Error())
+		return
+	}
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			respondError(c, http.StatusBadRequest, "INVALID_LIMIT", "limit 必须为正整数", v)
+			return
+		}
+	}
+	acc, err := s.getAccount(c.Request.Context(), id)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	logins, err := s.accounts.LoginHistory(acc.Key, limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "LOGIN_HISTORY_FAILED", "获取登录历史失败", err.Error())
+		return
+	}
+	if logins == nil {
+		logins = []time.Time{}
+	}
+	respondSuccess(c, gin.H{"account_id": id, "logins": logins}, "获取登录历史成功")
+}
+
 // startAccountWindowHandler 启动可视化浏览器窗口
 func (s *AppServer) startAccountWindowHandler(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -304,16 +428,16 @@ func (s *AppServer) startAccountWindowHandler(c *gin.Context) {
 		respondError(c, http.StatusBadRequest, "INVALID_ACCOUNT_ID", "账号ID无效", err.Error())
 		return
 	}
-	acc, err := s.accounts.Get(id)
+	acc, err := s.getAccount(c.Request.Context(), id)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 	ctx = session.WithHeadless(ctx, false)
 
 	if err := s.xiaohongshuService.StartVisibleWindow(ctx); err != nil {
-		respondError(c, http.StatusInternalServerError, "START_FAILED", "启动窗口失败", err.Error())
+		respondServiceError(c, "START_FAILED", "启动窗口失败", err)
 		return
 	}
 	respondSuccess(c, gin.H{"account_id": acc.ID}, "账号窗口已启动")
@@ -322,7 +446,7 @@ func (s *AppServer) startAccountWindowHandler(c *gin.Context) {
 // startRawWindowHandler 启动最小化可视浏览器（不加载账号数据）
 func (s *AppServer) startRawWindowHandler(c *gin.Context) {
 	if err := s.xiaohongshuService.StartRawVisibleWindow(context.Background()); err != nil {
-		respondError(c, http.StatusInternalServerError, "START_FAILED", "启动浏览器失败", err.Error())
+		respondServiceError(c, "START_FAILED", "启动浏览器失败", err)
 		return
 	}
 	respondSuccess(c, gin.H{"status": "ok"}, "已启动原生浏览器窗口")
@@ -332,13 +456,13 @@ func (s *AppServer) startRawWindowHandler(c *gin.Context) {
 func (s *AppServer) checkLoginStatusHandler(c *gin.Context) {
 	acc, ctx, err := s.bindAccountContext(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
 	status, err := s.xiaohongshuService.CheckLoginStatus(ctx)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED",
-			"检查登录状态失败", err.Error())
+		respondServiceError(c, "STATUS_CHECK_FAILED",
+			"检查登录状态失败", err)
 		return
 	}
 
@@ -349,13 +473,13 @@ func (s *AppServer) checkLoginStatusHandler(c *gin.Context) {
 func (s *AppServer) getLoginQrcodeHandler(c *gin.Context) {
 	acc, ctx, err := s.bindAccountContext(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
 	result, err := s.xiaohongshuService.GetLoginQrcode(ctx)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED",
-			"获取登录二维码失败", err.Error())
+		respondServiceError(c, "STATUS_CHECK_FAILED",
+			"获取登录二维码失败", err)
 		return
 	}
 
@@ -366,13 +490,13 @@ func (s *AppServer) getLoginQrcodeHandler(c *gin.Context) {
 func (s *AppServer) deleteCookiesHandler(c *gin.Context) {
 	acc, ctx, err := s.bindAccountContext(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
 	err = s.xiaohongshuService.DeleteCookies(ctx)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "DELETE_COOKIES_FAILED",
-			"删除 cookies 失败", err.Error())
+		respondServiceError(c, "DELETE_COOKIES_FAILED",
+			"删除 cookies 失败", err)
 		return
 	}
 
@@ -395,21 +519,25 @@ func (s *AppServer) publishHandler(c *gin.Context) {
 
 	acc, ctx, err := s.bindAccountContext(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
 
 	req.AccountID = acc.ID
 
-	// 执行发布
-	result, err := s.xiaohongshuService.PublishContent(ctx, &req)
+	// 提交发布任务，由后台异步执行
+	job, err := s.xiaohongshuService.EnqueuePublishContent(ctx, &req)
+	if isPublishAtError(err) {
+		respondError(c, http.StatusBadRequest, "INVALID_PUBLISH_AT", "定时发布时间无效", err.Error())
+		return
+	}
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "PUBLISH_FAILED",
-			"发布失败", err.Error())
+		respondError(c, http.StatusBadRequest, "PUBLISH_FAILED",
+			"发布任务提交失败", err.Error())
 		return
 	}
 
-	respondSuccess(c, gin.H{"account_id": acc.ID, "result": result}, "发布成功")
+	respondSuccess(c, gin.H{"account_id": acc.ID, "job_id": job.ID, "job": job}, "发布任务已提交")
 }
 
 // publishVideoHandler 发布视频内容
@@ -423,34 +551,256 @@ func (s *AppServer) publishVideoHandler(c *gin.Context) {
 
 	acc, ctx, err := s.bindAccountContext(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
 	req.AccountID = acc.ID
 
-	// 执行视频发布
-	result, err := s.xiaohongshuService.PublishVideo(ctx, &req)
+	// 提交视频发布任务，由后台异步执行
+	job, err := s.xiaohongshuService.EnqueuePublishVideo(ctx, &req)
+	if isPublishAtError(err) {
+		respondError(c, http.StatusBadRequest, "INVALID_PUBLISH_AT", "定时发布时间无效", err.Error())
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "PUBLISH_VIDEO_FAILED",
+			"视频发布任务提交失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, gin.H{"account_id": acc.ID, "job_id": job.ID, "job": job}, "视频发布任务已提交")
+}
+
+// listJobsHandler 列出后台任务，支持 account_id/status/type/limit 筛选
+func (s *AppServer) listJobsHandler(c *gin.Context) {
+	filter := jobs.Filter{
+		Status: jobs.Status(c.Query("status")),
+		Type:   c.Query("type"),
+	}
+	if v := c.Query("account_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "account_id 无效", err.Error())
+			return
+		}
+		acc, err := s.getAccount(c.Request.Context(), id)
+		if err != nil {
+			respondAccountError(c, err)
+			return
+		}
+		filter.AccountKey = acc.Key
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "limit 无效", v)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	respondSuccess(c, s.listJobs(c.Request.Context(), filter), "获取任务列表成功")
+}
+
+// getJobHandler 查询单个任务状态
+func (s *AppServer) getJobHandler(c *gin.Context) {
+	job, err := s.getJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "JOB_NOT_FOUND", "任务不存在", err.Error())
+		return
+	}
+
+	respondSuccess(c, job, "获取任务状态成功")
+}
+
+// listAuditHandler 查询审计记录
+func (s *AppServer) listAuditHandler(c *gin.Context) {
+	filter := audit.Filter{
+		Action:      audit.Action(c.Query("action")),
+		FeedID:      c.Query("feed_id"),
+		ContentHash: c.Query("content_hash"),
+		Caller:      c.Query("caller"),
+		Outcome:     audit.Outcome(c.Query("outcome")),
+	}
+	if v := c.Query("content"); v != "" {
+		filter.ContentHash = audit.HashContent(v)
+	}
+	if v := c.Query("account_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "account_id 无效", err.Error())
+			return
+		}
+		acc, err := s.getAccount(c.Request.Context(), id)
+		if err != nil {
+			respondAccountError(c, err)
+			return
+		}
+		filter.AccountKey = acc.Key
+	}
+	for param, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		t, err := xiaohongshu.ParseScheduleTime(v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", param+" 无效", err.Error())
+			return
+		}
+		*dst = t
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "limit 无效", v)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	res, err := s.queryAudit(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "AUDIT_QUERY_FAILED", "查询审计记录失败", err.Error())
+		return
+	}
+	respondSuccess(c, res, "获取审计记录成功")
+}
+
+// respondCalendarError 按错误类型返回日历接口的错误响应
+func respondCalendarError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, calendar.ErrNotFound):
+		respondError(c, http.StatusNotFound, "CALENDAR_ENTRY_NOT_FOUND", "日历条目不存在", err.Error())
+	case errors.Is(err, calendar.ErrNotPending):
+		respondError(c, http.StatusConflict, "CALENDAR_ENTRY_NOT_PENDING", "日历条目已发布或已取消", err.Error())
+	case isPublishAtError(err):
+		respondError(c, http.StatusBadRequest, "INVALID_PUBLISH_AT", "发布时间无效", err.Error())
+	default:
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+	}
+}
+
+// listCalendarHandler 列出内容日历，支持 account_id/status/from/to 筛选
+func (s *AppServer) listCalendarHandler(c *gin.Context) {
+	filter := calendar.Filter{Status: calendar.Status(c.Query("status"))}
+	if v := c.Query("account_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "account_id 无效", err.Error())
+			return
+		}
+		acc, err := s.getAccount(c.Request.Context(), id)
+		if err != nil {
+			respondAccountError(c, err)
+			return
+		}
+		filter.AccountKey = acc.Key
+	}
+	for param, dst := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		t, err := xiaohongshu.ParseScheduleTime(v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", param+" 无效", err.Error())
+			return
+		}
+		*dst = t
+	}
+
+	respondSuccess(c, s.listCalendar(c.Request.Context(), filter), "获取内容日历成功")
+}
+
+// addCalendarEntryHandler 新增日历条目
+func (s *AppServer) addCalendarEntryHandler(c *gin.Context) {
+	var req CalendarEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+
+	acc, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	entry, err := s.xiaohongshuService.AddCalendarEntry(ctx, acc.ID, &req)
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+
+	respondSuccess(c, entry, "日历条目已添加")
+}
+
+// getCalendarEntryHandler 查询日历条目
+func (s *AppServer) getCalendarEntryHandler(c *gin.Context) {
+	entry, err := s.getCalendarEntry(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+
+	respondSuccess(c, entry, "获取日历条目成功")
+}
+
+// updateCalendarEntryHandler 修改待发布的日历条目（包括调整发布时间）
+func (s *AppServer) updateCalendarEntryHandler(c *gin.Context) {
+	var req CalendarUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+
+	if _, err := s.getCalendarEntry(c.Request.Context(), c.Param("id")); err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+	entry, err := s.xiaohongshuService.UpdateCalendarEntry(c.Param("id"), &req)
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+
+	respondSuccess(c, entry, "日历条目已更新")
+}
+
+// cancelCalendarEntryHandler 取消日历条目
+func (s *AppServer) cancelCalendarEntryHandler(c *gin.Context) {
+	if _, err := s.getCalendarEntry(c.Request.Context(), c.Param("id")); err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+	entry, err := s.xiaohongshuService.CancelCalendarEntry(c.Param("id"))
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "PUBLISH_VIDEO_FAILED",
-			"视频发布失败", err.Error())
+		respondCalendarError(c, err)
 		return
 	}
 
-	respondSuccess(c, gin.H{"account_id": acc.ID, "result": result}, "视频发布成功")
+	respondSuccess(c, entry, "日历条目已取消")
 }
 
 // listFeedsHandler 获取Feeds列表
 func (s *AppServer) listFeedsHandler(c *gin.Context) {
 	_, ctx, err := s.bindAccountContext(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
+	opts, err := parsePageOptions(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+
 	// 获取 Feeds 列表
-	result, err := s.xiaohongshuService.ListFeeds(ctx)
+	result, err := s.xiaohongshuService.ListFeeds(ctx, opts)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "LIST_FEEDS_FAILED",
-			"获取Feeds列表失败", err.Error())
+		respondServiceError(c, "LIST_FEEDS_FAILED",
+			"获取Feeds列表失败", err)
 		return
 	}
 
@@ -462,6 +812,7 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 	var keyword string
 	var filters xiaohongshu.FilterOption
 	var accountID int
+	var opts xiaohongshu.PageOptions
 
 	switch c.Request.Method {
 	case http.MethodPost:
@@ -475,10 +826,16 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 		keyword = searchReq.Keyword
 		filters = searchReq.Filters
 		accountID = searchReq.AccountID
+		opts = xiaohongshu.PageOptions{Limit: searchReq.Limit, Cursor: searchReq.Cursor}
 	default:
 		keyword = c.Query("keyword")
 		id, _ := parseAccountID(c)
 		accountID = id
+		var err error
+		if opts, err = parsePageOptions(c); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+			return
+		}
 	}
 
 	if accountID == 0 {
@@ -491,18 +848,18 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 		return
 	}
 
-	acc, err := s.accounts.Get(accountID)
+	acc, err := s.getAccount(c.Request.Context(), accountID)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 
 	// 搜索 Feeds
-	result, err := s.xiaohongshuService.SearchFeeds(ctx, keyword, filters)
+	result, err := s.xiaohongshuService.SearchFeeds(ctx, keyword, opts, filters)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "SEARCH_FEEDS_FAILED",
-			"搜索Feeds失败", err.Error())
+		respondServiceError(c, "SEARCH_FEEDS_FAILED",
+			"搜索Feeds失败", err)
 		return
 	}
 
@@ -521,12 +878,12 @@ func (s *AppServer) getFeedDetailHandler(c *gin.Context) {
 	if req.AccountID == 0 {
 		req.AccountID = 1
 	}
-	acc, err := s.accounts.Get(req.AccountID)
+	acc, err := s.getAccount(c.Request.Context(), req.AccountID)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 
 	var result *FeedDetailResponse
 	var er error
@@ -546,8 +903,8 @@ func (s *AppServer) getFeedDetailHandler(c *gin.Context) {
 	}
 
 	if er != nil {
-		respondError(c, http.StatusInternalServerError, "GET_FEED_DETAIL_FAILED",
-			"获取Feed详情失败", er.Error())
+		respondServiceError(c, "GET_FEED_DETAIL_FAILED",
+			"获取Feed详情失败", er)
 		return
 	}
 
@@ -566,18 +923,26 @@ func (s *AppServer) userProfileHandler(c *gin.Context) {
 	if req.AccountID == 0 {
 		req.AccountID = 1
 	}
-	acc, err := s.accounts.Get(req.AccountID)
+	acc, err := s.getAccount(c.Request.Context(), req.AccountID)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	opts := xiaohongshu.ProfileNotesOptions{MaxNotes: req.MaxNotes}
+	if req.Since != "" {
+		if opts.Since, err = xiaohongshu.ParseScheduleTime(req.Since); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"since 格式错误", err.Error())
+			return
+		}
+	}
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 
 	// 获取用户信息
-	result, err := s.xiaohongshuService.UserProfile(ctx, req.UserID, req.XsecToken)
+	result, err := s.xiaohongshuService.UserProfile(ctx, req.UserID, req.XsecToken, opts)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "GET_USER_PROFILE_FAILED",
-			"获取用户主页失败", err.Error())
+		respondServiceError(c, "GET_USER_PROFILE_FAILED",
+			"获取用户主页失败", err)
 		return
 	}
 
@@ -596,18 +961,18 @@ func (s *AppServer) postCommentHandler(c *gin.Context) {
 	if req.AccountID == 0 {
 		req.AccountID = 1
 	}
-	acc, err := s.accounts.Get(req.AccountID)
+	acc, err := s.getAccount(c.Request.Context(), req.AccountID)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 
 	// 发表评论
 	result, err := s.xiaohongshuService.PostCommentToFeed(ctx, req.FeedID, req.XsecToken, req.Content)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "POST_COMMENT_FAILED",
-			"发表评论失败", err.Error())
+		respondServiceError(c, "POST_COMMENT_FAILED",
+			"发表评论失败", err)
 		return
 	}
 
@@ -626,48 +991,588 @@ func (s *AppServer) replyCommentHandler(c *gin.Context) {
 	if req.AccountID == 0 {
 		req.AccountID = 1
 	}
-	acc, err := s.accounts.Get(req.AccountID)
+	acc, err := s.getAccount(c.Request.Context(), req.AccountID)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
-	ctx := session.WithAccount(c.Request.Context(), acc.Key)
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
 
 	result, err := s.xiaohongshuService.ReplyCommentToFeed(ctx, req.FeedID, req.XsecToken, req.CommentID, req.UserID, req.Content)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "REPLY_COMMENT_FAILED",
-			"回复评论失败", err.Error())
+		respondServiceError(c, "REPLY_COMMENT_FAILED",
+			"回复评论失败", err)
 		return
 	}
 
 	respondSuccess(c, result, result.Message)
 }
 
-// healthHandler 健康检查
-func healthHandler(c *gin.Context) {
+// quotaHandler 查询账号剩余互动额度
+func (s *AppServer) quotaHandler(c *gin.Context) {
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.GetQuota(ctx)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "GET_QUOTA_FAILED", "获取互动额度失败", err.Error())
+		return
+	}
+	respondSuccess(c, result, "获取互动额度成功")
+}
+
+// healthHandler 健康检查，不需要认证，只返回浏览器池与并发的汇总数量；各账号的明细见 statusHandler
+func (s *AppServer) healthHandler(c *gin.Context) {
+	pool := s.xiaohongshuService.PoolStats()
+	conc := s.xiaohongshuService.ConcurrencyStats()
 	respondSuccess(c, map[string]any{
 		"status":    "healthy",
 		"service":   "xiaohongshu-mcp",
-		"account":   "ai-report",
-		"timestamp": "now",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"browser_pool": map[string]any{
+			"browsers":     pool.Browsers,
+			"in_use":       pool.InUse,
+			"idle":         pool.Idle,
+			"max_browsers": pool.MaxBrowsers,
+		},
+		"concurrency": map[string]any{
+			"running":      conc.Running,
+			"max_parallel": conc.MaxParallel,
+			"busy":         len(conc.Accounts),
+		},
 	}, "服务正常")
 }
 
+// statusHandler 浏览器池与并发的完整状态，包含各账号的明细，需要 admin 权限
+func (s *AppServer) statusHandler(c *gin.Context) {
+	respondSuccess(c, map[string]any{
+		"browser_pool": s.xiaohongshuService.PoolStats(),
+		"concurrency":  s.xiaohongshuService.ConcurrencyStats(),
+	}, "获取服务状态成功")
+}
+
 // myProfileHandler 我的信息
 func (s *AppServer) myProfileHandler(c *gin.Context) {
 	acc, ctx, err := s.bindAccountContext(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "ACCOUNT_NOT_FOUND", "账号不存在", err.Error())
+		respondAccountError(c, err)
 		return
 	}
 
 	// 获取当前登录用户信息
 	result, err := s.xiaohongshuService.GetMyProfile(ctx)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "GET_MY_PROFILE_FAILED",
-			"获取我的主页失败", err.Error())
+		respondServiceError(c, "GET_MY_PROFILE_FAILED",
+			"获取我的主页失败", err)
 		return
 	}
 
 	respondSuccess(c, map[string]any{"account_id": acc.ID, "data": result}, "获取我的主页成功")
 }
+
+// followUserHandler 关注用户
+func (s *AppServer) followUserHandler(c *gin.Context) {
+	s.followUser(c, false)
+}
+
+// unfollowUserHandler 取消关注用户
+func (s *AppServer) unfollowUserHandler(c *gin.Context) {
+	s.followUser(c, true)
+}
+
+func (s *AppServer) followUser(c *gin.Context, unfollow bool) {
+	var req FollowUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	if req.AccountID == 0 {
+		req.AccountID = 1
+	}
+	acc, err := s.getAccount(c.Request.Context(), req.AccountID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
+
+	var result *FollowResult
+	if unfollow {
+		result, err = s.xiaohongshuService.UnfollowUser(ctx, req.UserID, req.XsecToken)
+	} else {
+		result, err = s.xiaohongshuService.FollowUser(ctx, req.UserID, req.XsecToken)
+	}
+	if err != nil {
+		if unfollow {
+			respondServiceError(c, "UNFOLLOW_USER_FAILED", "取消关注失败", err)
+		} else {
+			respondServiceError(c, "FOLLOW_USER_FAILED", "关注失败", err)
+		}
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// listFollowingHandler 当前账号的关注列表
+func (s *AppServer) listFollowingHandler(c *gin.Context) {
+	s.listFollows(c, xiaohongshu.FollowListFollowing)
+}
+
+// listFollowersHandler 当前账号的粉丝列表
+func (s *AppServer) listFollowersHandler(c *gin.Context) {
+	s.listFollows(c, xiaohongshu.FollowListFollowers)
+}
+
+func (s *AppServer) listFollows(c *gin.Context, kind xiaohongshu.FollowListKind) {
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	opts, err := parsePageOptions(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListFollows(ctx, kind, opts)
+	if err != nil {
+		respondServiceError(c, "LIST_FOLLOWS_FAILED", "获取关注/粉丝列表失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取关注/粉丝列表成功")
+}
+
+// likeCommentHandler 点赞/取消点赞评论
+func (s *AppServer) likeCommentHandler(c *gin.Context) {
+	var req LikeCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	if req.AccountID == 0 {
+		req.AccountID = 1
+	}
+	acc, err := s.getAccount(c.Request.Context(), req.AccountID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
+
+	result, err := s.xiaohongshuService.LikeComment(ctx, req.FeedID, req.XsecToken, req.CommentID, req.UserID, req.Unlike)
+	if err != nil {
+		respondServiceError(c, "LIKE_COMMENT_FAILED",
+			"评论点赞失败", err)
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// deleteCommentHandler 删除评论
+func (s *AppServer) deleteCommentHandler(c *gin.Context) {
+	var req DeleteCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	if req.AccountID == 0 {
+		req.AccountID = 1
+	}
+	acc, err := s.getAccount(c.Request.Context(), req.AccountID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
+
+	result, err := s.xiaohongshuService.DeleteComment(ctx, req.FeedID, req.XsecToken, req.CommentID)
+	if err != nil {
+		respondServiceError(c, "DELETE_COMMENT_FAILED",
+			"删除评论失败", err)
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// pinCommentHandler 置顶/取消置顶评论
+func (s *AppServer) pinCommentHandler(c *gin.Context) {
+	var req PinCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	if req.AccountID == 0 {
+		req.AccountID = 1
+	}
+	acc, err := s.getAccount(c.Request.Context(), req.AccountID)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	ctx := withRequestWait(c, session.WithAccount(c.Request.Context(), acc.Key))
+
+	result, err := s.xiaohongshuService.PinComment(ctx, req.FeedID, req.XsecToken, req.CommentID, req.UserID, req.Unpin)
+	if err != nil {
+		respondServiceError(c, "PIN_COMMENT_FAILED",
+			"置顶评论失败", err)
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// listCreatorNotesHandler 列出当前账号在创作者中心的已发布笔记
+func (s *AppServer) listCreatorNotesHandler(c *gin.Context) {
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	opts, err := parsePageOptions(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListCreatorNotes(ctx, opts)
+	if err != nil {
+		respondServiceError(c, "LIST_CREATOR_NOTES_FAILED", "获取已发布笔记失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取已发布笔记成功")
+}
+
+// editCreatorNoteHandler 编辑已发布笔记
+func (s *AppServer) editCreatorNoteHandler(c *gin.Context) {
+	var req EditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	if req.Title == "" && req.Content == "" && len(req.Tags) == 0 {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", "title、content、tags 至少提供一个")
+		return
+	}
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.EditCreatorNote(ctx, c.Param("id"), &req)
+	if err != nil {
+		respondServiceError(c, "EDIT_NOTE_FAILED", "编辑笔记失败", err)
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// deleteCreatorNoteHandler 删除已发布笔记
+func (s *AppServer) deleteCreatorNoteHandler(c *gin.Context) {
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.DeleteCreatorNote(ctx, c.Param("id"))
+	if err != nil {
+		respondServiceError(c, "DELETE_NOTE_FAILED", "删除笔记失败", err)
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// setNoteVisibilityHandler 修改笔记可见范围
+func (s *AppServer) setNoteVisibilityHandler(c *gin.Context) {
+	var req NoteVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.SetNoteVisibility(ctx, c.Param("id"), req.Visibility)
+	if err != nil {
+		respondServiceError(c, "SET_NOTE_VISIBILITY_FAILED", "修改可见范围失败", err)
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// listDraftsHandler 列出草稿箱中的草稿
+func (s *AppServer) listDraftsHandler(c *gin.Context) {
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListDrafts(ctx)
+	if err != nil {
+		respondServiceError(c, "LIST_DRAFTS_FAILED", "获取草稿列表失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取草稿列表成功")
+}
+
+// publishDraftHandler 提交草稿发布任务，请求体可选，用于修改草稿内容或设置定时发布
+func (s *AppServer) publishDraftHandler(c *gin.Context) {
+	var req PublishDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+
+	acc, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+	req.AccountID = acc.ID
+	req.DraftID = c.Param("id")
+
+	job, err := s.xiaohongshuService.EnqueuePublishDraft(ctx, &req)
+	if isPublishAtError(err) {
+		respondError(c, http.StatusBadRequest, "INVALID_PUBLISH_AT", "定时发布时间无效", err.Error())
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "PUBLISH_FAILED", "草稿发布任务提交失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, gin.H{"account_id": acc.ID, "job_id": job.ID, "job": job}, "草稿发布任务已提交")
+}
+
+// noteAnalyticsHandler 列出笔记数据，传 note_id 时只返回该笔记
+func (s *AppServer) noteAnalyticsHandler(c *gin.Context) {
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	if noteID := c.Query("note_id"); noteID != "" {
+		result, err := s.xiaohongshuService.GetNoteAnalytics(ctx, noteID)
+		if err != nil {
+			respondServiceError(c, "GET_NOTE_ANALYTICS_FAILED", "获取笔记数据失败", err)
+			return
+		}
+		respondSuccess(c, result, "获取笔记数据成功")
+		return
+	}
+
+	opts, err := parsePageOptions(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	result, err := s.xiaohongshuService.ListNoteAnalytics(ctx, opts)
+	if err != nil {
+		respondServiceError(c, "GET_NOTE_ANALYTICS_FAILED", "获取笔记数据失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取笔记数据成功")
+}
+
+// accountAnalyticsHandler 获取账号每日趋势，支持 from/to 日期范围
+func (s *AppServer) accountAnalyticsHandler(c *gin.Context) {
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.GetAccountAnalytics(ctx, c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondServiceError(c, "GET_ACCOUNT_ANALYTICS_FAILED", "获取账号数据失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取账号数据成功")
+}
+
+// listNotificationsHandler 读取通知列表，only_new=true 时只返回已读位置之后的通知
+func (s *AppServer) listNotificationsHandler(c *gin.Context) {
+	kind, err := xiaohongshu.ParseNotificationKind(c.DefaultQuery("kind", string(xiaohongshu.NotificationMentions)))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	opts, err := parsePageOptions(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListNotifications(ctx, NotificationQuery{
+		Kind:        kind,
+		PageOptions: opts,
+		OnlyNew:     c.Query("only_new") == "true",
+		MarkSeen:    c.Query("mark_seen") == "true",
+	})
+	if err != nil {
+		respondServiceError(c, "LIST_NOTIFICATIONS_FAILED", "获取通知失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取通知成功")
+}
+
+// listConversationsHandler 列出私信会话
+func (s *AppServer) listConversationsHandler(c *gin.Context) {
+	opts, err := parsePageOptions(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListConversations(ctx, opts)
+	if err != nil {
+		respondServiceError(c, "LIST_CONVERSATIONS_FAILED", "获取私信会话失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取私信会话成功")
+}
+
+// getMessagesHandler 读取与某个用户的私信
+func (s *AppServer) getMessagesHandler(c *gin.Context) {
+	opts, err := parsePageOptions(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.GetMessages(ctx, c.Param("user_id"), opts)
+	if err != nil {
+		respondServiceError(c, "GET_MESSAGES_FAILED", "获取私信失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取私信成功")
+}
+
+// sendMessageHandler 给某个用户发送文字私信
+func (s *AppServer) sendMessageHandler(c *gin.Context) {
+	var req SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.SendMessage(ctx, c.Param("user_id"), req.Content)
+	if err != nil {
+		respondServiceError(c, "SEND_MESSAGE_FAILED", "私信发送失败", err)
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// checkSelectorsHandler 打开各页面检查选择器是否还能匹配，pages 为逗号分隔的页面，不传时检查全部
+func (s *AppServer) checkSelectorsHandler(c *gin.Context) {
+	pages, err := xiaohongshu.ParseSelectorPages(c.Query("pages"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "请求参数错误", err.Error())
+		return
+	}
+	_, ctx, err := s.bindAccountContext(c)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	report, err := s.xiaohongshuService.CheckSelectors(ctx, pages)
+	if err != nil {
+		respondServiceError(c, "CHECK_SELECTORS_FAILED", "检查选择器失败", err)
+		return
+	}
+
+	respondSuccess(c, report, "检查选择器完成")
+}
+
+// downloadArtifactHandler 下载失败现场：指定文件名时返回单个文件，否则把整个现场打包为 zip
+func (s *AppServer) downloadArtifactHandler(c *gin.Context) {
+	id := c.Param("id")
+	if name := c.Param("file"); name != "" {
+		f, err := s.xiaohongshuService.OpenArtifact(id, name)
+		if err != nil {
+			respondArtifactError(c, err)
+			return
+		}
+		defer f.Close()
+		var modTime time.Time
+		if info, err := f.Stat(); err == nil {
+			modTime = info.ModTime()
+		}
+		http.ServeContent(c.Writer, c.Request, name, modTime, f)
+		return
+	}
+
+	if _, err := s.xiaohongshuService.GetArtifact(id); err != nil {
+		respondArtifactError(c, err)
+		return
+	}
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, id))
+	c.Status(http.StatusOK)
+	if err := s.xiaohongshuService.WriteArtifactZip(c.Writer, id); err != nil {
+		logrus.Errorf("write artifact %s failed: %v", id, err)
+	}
+}
+
+func respondArtifactError(c *gin.Context, err error) {
+	if errors.Is(err, artifacts.ErrNotFound) {
+		respondError(c, http.StatusNotFound, "ARTIFACT_NOT_FOUND", "失败现场不存在或已被清理", err.Error())
+		return
+	}
+	respondError(c, http.StatusInternalServerError, "GET_ARTIFACT_FAILED", "读取失败现场失败", err.Error())
+}
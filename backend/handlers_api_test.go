@@ -1,16 +1,35 @@
 package main
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
+	"github.com/xpzouying/xiaohongshu-mcp/artifacts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/calendar"
+	"github.com/xpzouying/xiaohongshu-mcp/concurrency"
+	"github.com/xpzouying/xiaohongshu-mcp/inbox"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
+	"github.com/xpzouying/xiaohongshu-mcp/session"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // 测试配置
@@ -18,6 +37,11 @@ const testAPIBase = "http://127.0.0.1:18060"
 
 // setupTestApp 创建测试应用实例
 func setupTestApp(t *testing.T) (*AppServer, *httptest.Server) {
+	return setupTestAppWithKeys(t, nil)
+}
+
+// setupTestAppWithKeys 创建启用 API Key 鉴权的测试服务，apiKeys 为 nil 时不鉴权
+func setupTestAppWithKeys(t *testing.T, apiKeys *apikey.Keyring) (*AppServer, *httptest.Server) {
 	// 创建临时目录用于测试
 	tempDir := t.TempDir()
 	storePath := filepath.Join(tempDir, "accounts.json")
@@ -29,11 +53,55 @@ func setupTestApp(t *testing.T) (*AppServer, *httptest.Server) {
 		t.Fatalf("failed to create account manager: %v", err)
 	}
 
+	// 创建任务管理器
+	jobManager, err := jobs.NewManager(filepath.Join(tempDir, "jobs.json"), jobs.Options{Retryable: isTransientError, Resumable: isResumableError})
+	if err != nil {
+		t.Fatalf("failed to create job manager: %v", err)
+	}
+
+	// 创建内容日历
+	calendarStore, err := calendar.NewStore(filepath.Join(tempDir, "calendar.json"), calendar.Options{})
+	if err != nil {
+		t.Fatalf("failed to create calendar store: %v", err)
+	}
+
+	// 创建互动额度管理器
+	quotaManager, err := quota.NewManager(filepath.Join(tempDir, "quota.json"), quota.DefaultPolicies())
+	if err != nil {
+		t.Fatalf("failed to create quota manager: %v", err)
+	}
+
+	// 创建审计日志
+	auditStore, err := audit.NewFileStore(filepath.Join(tempDir, "audit.jsonl"), audit.FileOptions{})
+	if err != nil {
+		t.Fatalf("failed to create audit log: %v", err)
+	}
+
+	// 创建通知已读位置存储
+	inboxStore, err := inbox.NewStore(filepath.Join(tempDir, "inbox.json"))
+	if err != nil {
+		t.Fatalf("failed to create inbox store: %v", err)
+	}
+
+	// 创建失败现场存储
+	artifactStore, err := artifacts.NewStore(filepath.Join(tempDir, "artifacts"), 10)
+	if err != nil {
+		t.Fatalf("failed to create artifact store: %v", err)
+	}
+
 	// 创建服务
-	xiaohongshuService := NewXiaohongshuService(accountManager)
+	xiaohongshuService := NewXiaohongshuService(accountManager, jobManager, calendarStore,
+		browser.NewPool(browser.PoolConfig{}), concurrency.NewLimiter(concurrency.Config{Wait: time.Second}), quotaManager,
+		audit.New(auditStore), inboxStore, artifactStore)
+	t.Cleanup(func() {
+		// 发布任务可能阻塞在浏览器或网络上，不必等待其结束
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = xiaohongshuService.Close(ctx)
+	})
 
 	// 创建应用服务器
-	appServer := NewAppServer(xiaohongshuService)
+	appServer := NewAppServer(xiaohongshuService, apiKeys)
 
 	// 创建测试路由
 	gin.SetMode(gin.TestMode)
@@ -96,6 +164,50 @@ func TestHealthHandler(t *testing.T) {
 	if data["status"] != "healthy" {
 		t.Errorf("expected status=healthy, got %v", data["status"])
 	}
+	pool, ok := data["browser_pool"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected browser_pool stats, got %v", data["browser_pool"])
+	}
+	// 健康检查不需要认证，不能带出账号信息
+	if _, ok := pool["accounts"]; ok {
+		t.Errorf("expected no per-account pool stats, got %v", pool["accounts"])
+	}
+	conc, ok := data["concurrency"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected concurrency stats, got %v", data["concurrency"])
+	}
+	if _, ok := conc["busy_accounts"]; ok {
+		t.Errorf("expected no busy account keys, got %v", conc["busy_accounts"])
+	}
+	if _, ok := data["account"]; ok {
+		t.Errorf("expected no account field, got %v", data["account"])
+	}
+}
+
+func TestStatusHandler(t *testing.T) {
+	_, ts := setupTestApp(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/status")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	assertSuccess(t, resp)
+
+	var result struct {
+		Data map[string]map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := result.Data["browser_pool"]["accounts"]; !ok {
+		t.Errorf("expected per-account pool stats, got %v", result.Data["browser_pool"])
+	}
+	if _, ok := result.Data["concurrency"]["busy_accounts"]; !ok {
+		t.Errorf("expected busy_accounts, got %v", result.Data["concurrency"])
+	}
 }
 
 // ==================== 账号管理 ====================
@@ -202,6 +314,44 @@ func TestDeleteAccountHandler(t *testing.T) {
 	}
 }
 
+func TestAccountLoginsHandler(t *testing.T) {
+	app, ts := setupTestApp(t)
+	defer ts.Close()
+
+	acc, err := app.accounts.Create("", "logins")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	app.accounts.MarkLoggedIn(acc.Key)
+	app.accounts.MarkLoggedIn(acc.Key)
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/accounts/%d/logins?limit=1", ts.URL, acc.ID))
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertSuccess(t, resp)
+
+	var result struct {
+		Data struct {
+			Logins []time.Time `json:"logins"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result.Data.Logins) != 1 {
+		t.Errorf("expected 1 login, got %d", len(result.Data.Logins))
+	}
+
+	resp, err = http.Get(ts.URL + "/api/v1/accounts/999/logins")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertStatusCode(t, resp, http.StatusBadRequest)
+}
+
 // ==================== 登录相关 ====================
 
 func TestCheckLoginStatusHandler(t *testing.T) {
@@ -377,6 +527,510 @@ func TestPublishVideoHandler(t *testing.T) {
 	t.Logf("Publish video result: %+v", result)
 }
 
+func TestPublishHandler_InvalidPublishAt(t *testing.T) {
+	_, ts := setupTestApp(t)
+	defer ts.Close()
+
+	for _, publishAt := range []string{"下周一", "2000-01-01 08:00"} {
+		req := PublishRequest{
+			Title:     "测试标题",
+			Content:   "测试内容",
+			Images:    []string{"https://example.com/test.jpg"},
+			PublishAt: publishAt,
+		}
+
+		resp, err := http.Post(ts.URL+"/api/v1/publish", "application/json", jsonBody(req))
+		if err != nil {
+			t.Fatalf("failed to request: %v", err)
+		}
+		defer resp.Body.Close()
+		assertStatusCode(t, resp, http.StatusBadRequest)
+
+		var result ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if result.Code != "INVALID_PUBLISH_AT" {
+			t.Errorf("publish_at=%q: expected code INVALID_PUBLISH_AT, got %s", publishAt, result.Code)
+		}
+	}
+}
+
+func TestPublishDraftHandler_InvalidRequest(t *testing.T) {
+	_, ts := setupTestApp(t)
+	defer ts.Close()
+
+	cases := []struct {
+		req  PublishDraftRequest
+		code string
+	}{
+		{PublishDraftRequest{PublishAt: "下周一"}, "INVALID_PUBLISH_AT"},
+		{PublishDraftRequest{PublishAt: "2000-01-01 08:00"}, "INVALID_PUBLISH_AT"},
+		{PublishDraftRequest{Title: "这是一个非常非常非常非常非常非常长的草稿标题"}, "PUBLISH_FAILED"},
+	}
+	for _, tc := range cases {
+		resp, err := http.Post(ts.URL+"/api/v1/drafts/abc12345/publish", "application/json", jsonBody(tc.req))
+		if err != nil {
+			t.Fatalf("failed to request: %v", err)
+		}
+		defer resp.Body.Close()
+		assertStatusCode(t, resp, http.StatusBadRequest)
+
+		var result ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if result.Code != tc.code {
+			t.Errorf("%+v: expected code %s, got %s", tc.req, tc.code, result.Code)
+		}
+	}
+}
+
+func TestJobsHandler(t *testing.T) {
+	_, ts := setupTestApp(t)
+	defer ts.Close()
+
+	req := PublishRequest{
+		Title:   "测试标题",
+		Content: "测试内容",
+		Images:  []string{"https://example.com/test.jpg"},
+	}
+	resp, err := http.Post(ts.URL+"/api/v1/publish", "application/json", jsonBody(req))
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertSuccess(t, resp)
+
+	var published struct {
+		Data struct {
+			JobID string `json:"job_id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&published); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if published.Data.JobID == "" {
+		t.Fatalf("expected job_id in publish response")
+	}
+
+	// 查询单个任务
+	resp, err = http.Get(ts.URL + "/api/v1/jobs/" + published.Data.JobID)
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertSuccess(t, resp)
+
+	// 列出任务
+	resp, err = http.Get(ts.URL + "/api/v1/jobs?account_id=1&type=publish_content&limit=10")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertSuccess(t, resp)
+
+	var listed struct {
+		Data JobListResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if listed.Data.Count != 1 || listed.Data.Jobs[0].ID != published.Data.JobID {
+		t.Errorf("expected the submitted job in list, got %+v", listed.Data)
+	}
+
+	// 不存在的任务
+	resp, err = http.Get(ts.URL + "/api/v1/jobs/job_missing")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+// ==================== 内容日历 ====================
+
+func TestCalendarHandlers(t *testing.T) {
+	_, ts := setupTestApp(t)
+	defer ts.Close()
+
+	req := CalendarEntryRequest{
+		Title:     "日历测试",
+		Content:   "日历测试内容",
+		Images:    []string{"https://example.com/test.jpg"},
+		PublishAt: time.Now().Add(30 * 24 * time.Hour).Format(time.RFC3339),
+	}
+	resp, err := http.Post(ts.URL+"/api/v1/calendar", "application/json", jsonBody(req))
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertSuccess(t, resp)
+
+	var added struct {
+		Data struct {
+			ID     string `json:"id"`
+			Kind   string `json:"kind"`
+			Status string `json:"status"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&added); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if added.Data.ID == "" || added.Data.Kind != "image" || added.Data.Status != "pending" {
+		t.Fatalf("unexpected entry: %+v", added.Data)
+	}
+	entryURL := ts.URL + "/api/v1/calendar/" + added.Data.ID
+
+	// 调整发布时间
+	moveReq, _ := http.NewRequest(http.MethodPut, entryURL, jsonBody(map[string]string{
+		"publish_at": time.Now().Add(48 * time.Hour).Format("2006-01-02 15:04"),
+	}))
+	moveReq.Header.Set("Content-Type", "application/json")
+	resp, err = http.DefaultClient.Do(moveReq)
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertSuccess(t, resp)
+
+	// 过去的时间
+	pastReq, _ := http.NewRequest(http.MethodPut, entryURL, jsonBody(map[string]string{
+		"publish_at": "2000-01-01 08:00",
+	}))
+	pastReq.Header.Set("Content-Type", "application/json")
+	resp, err = http.DefaultClient.Do(pastReq)
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertStatusCode(t, resp, http.StatusBadRequest)
+
+	// 列表
+	resp, err = http.Get(ts.URL + "/api/v1/calendar?account_id=1&status=pending")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertSuccess(t, resp)
+
+	var listed struct {
+		Data CalendarListResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if listed.Data.Count != 1 {
+		t.Errorf("expected 1 pending entry, got %d", listed.Data.Count)
+	}
+
+	// 取消后不能再次取消
+	for _, want := range []int{http.StatusOK, http.StatusConflict} {
+		delReq, _ := http.NewRequest(http.MethodDelete, entryURL, nil)
+		resp, err = http.DefaultClient.Do(delReq)
+		if err != nil {
+			t.Fatalf("failed to request: %v", err)
+		}
+		defer resp.Body.Close()
+		assertStatusCode(t, resp, want)
+	}
+}
+
+func TestAuditHandler(t *testing.T) {
+	app, ts := setupTestApp(t)
+	defer ts.Close()
+
+	acc, err := app.accounts.Create("", "audit")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	// 删除 cookies 不需要浏览器，可以直接验证服务层的审计记录
+	ctx := audit.WithCaller(session.WithAccount(context.Background(), acc.Key), "test")
+	if err := app.xiaohongshuService.DeleteCookies(ctx); err != nil {
+		t.Fatalf("failed to delete cookies: %v", err)
+	}
+	app.xiaohongshuService.audit.Record(audit.Entry{AccountKey: acc.Key, Action: audit.ActionComment, FeedID: "feed1",
+		ContentHash: audit.HashContent("写得真好"), Outcome: audit.OutcomeSuccess})
+	app.xiaohongshuService.audit.Record(audit.Entry{AccountKey: "acc_other", Action: audit.ActionComment, FeedID: "feed1",
+		ContentHash: audit.HashContent("写得真好"), Outcome: audit.OutcomeSuccess})
+
+	query := func(params string) AuditListResponse {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/api/v1/audit?" + params)
+		if err != nil {
+			t.Fatalf("failed to request: %v", err)
+		}
+		defer resp.Body.Close()
+		assertSuccess(t, resp)
+		var result struct {
+			Data AuditListResponse `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return result.Data
+	}
+
+	res := query(fmt.Sprintf("account_id=%d", acc.ID))
+	if res.Count != 2 {
+		t.Fatalf("expected 2 entries, got %d", res.Count)
+	}
+	last := res.Entries[1]
+	if last.Action != audit.ActionDeleteCookies || last.Caller != "test" || last.Outcome != audit.OutcomeSuccess {
+		t.Errorf("unexpected delete_cookies entry: %+v", last)
+	}
+
+	res = query("content=" + url.QueryEscape("写得真好") + "&feed_id=feed1")
+	if res.Count != 2 {
+		t.Errorf("expected 2 entries with same content, got %d", res.Count)
+	}
+	res = query("action=like")
+	if res.Count != 0 {
+		t.Errorf("expected no like entries, got %d", res.Count)
+	}
+
+	resp, err := http.Get(ts.URL + "/api/v1/audit?since=bad")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+	assertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func TestIsTransientError(t *testing.T) {
+	// 点击发布之后的失败可能已经发出笔记，不能重试
+	submitted := fmt.Errorf("%w: %w", xiaohongshu.ErrSubmitUncertain, context.DeadlineExceeded)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"deadline before submit", errors.Wrap(context.DeadlineExceeded, "上传图片"), true},
+		{"panic before submit", &jobs.PanicError{Value: "element not found"}, true},
+		{"permanent", errors.New("标题过长"), false},
+		{"deadline after submit", errors.Wrap(submitted, "点击发布按钮失败"), false},
+		{"artifact after submit", &artifacts.Error{ID: "a1", Err: submitted}, false},
+	}
+	for _, tt := range tests {
+		if got := isTransientError(tt.err); got != tt.want {
+			t.Errorf("%s: isTransientError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSchedulePublishAt(t *testing.T) {
+	// 入队时已校验窗口：执行时只要仍满足最少提前量就照常使用，不再检查上限
+	later := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	when, err := schedulePublishAt(later.Format(time.RFC3339), true)
+	if err != nil {
+		t.Fatalf("queued publish_at rejected: %v", err)
+	}
+	if !when.Equal(later) {
+		t.Errorf("expected %v, got %v", later, when)
+	}
+
+	// 排队期间已不足平台要求的 1 小时，直接失败，不交给页面填写
+	soon := time.Now().Add(30 * time.Minute).Truncate(time.Minute)
+	if _, err := schedulePublishAt(soon.Format(time.RFC3339), true); !errors.Is(err, xiaohongshu.ErrScheduleOutOfRange) {
+		t.Errorf("queued publish_at under the minimum lead should be rejected, got %v", err)
+	}
+	if _, err := schedulePublishAt(soon.Format(time.RFC3339), false); !errors.Is(err, xiaohongshu.ErrScheduleOutOfRange) {
+		t.Errorf("direct call should validate the platform window, got %v", err)
+	}
+
+	past := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	if _, err := schedulePublishAt(past, true); !errors.Is(err, errPublishAtInPast) {
+		t.Errorf("expected errPublishAtInPast, got %v", err)
+	}
+}
+
+func TestNotificationQueryPageOptions(t *testing.T) {
+	// 默认轮询（only_new=true&mark_seen=true）不能只取首屏，否则总有 next_cursor，已读位置永远不会推进
+	poll := NotificationQuery{Kind: xiaohongshu.NotificationMentions, OnlyNew: true, MarkSeen: true}
+	if got := poll.pageOptions(); got.Limit != xiaohongshu.DefaultPageLimit {
+		t.Errorf("default poll: expected limit %d, got %d", xiaohongshu.DefaultPageLimit, got.Limit)
+	}
+	if got := (NotificationQuery{MarkSeen: true}).pageOptions(); got.Limit != xiaohongshu.DefaultPageLimit {
+		t.Errorf("mark_seen only: expected limit %d, got %d", xiaohongshu.DefaultPageLimit, got.Limit)
+	}
+
+	// 显式的 limit、cursor 与普通浏览保持不变
+	withLimit := poll
+	withLimit.Limit = 5
+	if got := withLimit.pageOptions(); got.Limit != 5 {
+		t.Errorf("explicit limit changed to %d", got.Limit)
+	}
+	withCursor := poll
+	withCursor.Cursor = "next"
+	if got := withCursor.pageOptions(); got.Limit != 0 || got.Cursor != "next" {
+		t.Errorf("cursor page changed: %+v", got)
+	}
+	if got := (NotificationQuery{}).pageOptions(); got.Limit != 0 {
+		t.Errorf("plain listing should stay first-screen only, got limit %d", got.Limit)
+	}
+}
+
+func TestAuditRecordsPanic(t *testing.T) {
+	app, ts := setupTestApp(t)
+	defer ts.Close()
+
+	acc, err := app.accounts.Create("", "panic")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	svc := app.xiaohongshuService
+	ctx := session.WithAccount(context.Background(), acc.Key)
+
+	// rod 的 Must* 方法失败时 panic，err 仍为空
+	like := func() (err error) {
+		defer svc.recordAudit(ctx, audit.Entry{Action: audit.ActionLike, FeedID: "feed1"}, time.Now(), &err)
+		panic(errors.New("element not found"))
+	}
+	search := func() (err error) {
+		defer svc.observeAction(ctx, "search_feeds", time.Now(), &err)
+		panic("timeout")
+	}
+	for _, fn := range []func() error{like, search} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic to propagate")
+				}
+			}()
+			_ = fn()
+		}()
+	}
+
+	entries, err := svc.audit.Query(audit.Filter{AccountKey: acc.Key})
+	if err != nil {
+		t.Fatalf("failed to query audit: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Outcome != audit.OutcomeFailure || !strings.Contains(entries[0].Error, "element not found") {
+		t.Errorf("expected failure entry with panic value, got %+v", entries[0])
+	}
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, line := range []string{
+		// 指标是进程级的，只检查已记录为失败
+		fmt.Sprintf(`xhs_actions_total{account=%q,action="like",outcome="failure"} `, acc.Key),
+		fmt.Sprintf(`xhs_actions_total{account=%q,action="search_feeds",outcome="failure"} `, acc.Key),
+	} {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("metrics missing %s", line)
+		}
+	}
+}
+
+func TestArtifactHandler(t *testing.T) {
+	app, ts := setupTestApp(t)
+	defer ts.Close()
+
+	id, err := app.xiaohongshuService.artifacts.Save(&artifacts.Snapshot{
+		Meta:    artifacts.Meta{Account: "acc_1", URL: "https://www.xiaohongshu.com/explore", Error: "selector comment.submit: timeout"},
+		HTML:    "<html></html>",
+		Console: []string{"10:00:00.000 [network] POST https://edith.xiaohongshu.com/api/sns/web/v1/comment/post 461"},
+	})
+	if err != nil {
+		t.Fatalf("failed to save artifact: %v", err)
+	}
+
+	// 失败响应中带有现场 ID
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/feeds/comment", nil)
+	respondServiceError(c, "POST_COMMENT_FAILED", "发表评论失败", &artifacts.Error{ID: id, Err: errors.New("timeout")})
+	var errResp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp.ArtifactID != id {
+		t.Errorf("expected artifact_id %s, got %q", id, errResp.ArtifactID)
+	}
+
+	resp, err := http.Get(ts.URL + "/api/v1/artifacts/" + id)
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assertStatusCode(t, resp, http.StatusOK)
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("failed to read zip: %v", err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	if want := []string{id + "/meta.json", id + "/page.html", id + "/console.log"}; strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("expected zip entries %v, got %v", want, names)
+	}
+
+	resp, err = http.Get(ts.URL + "/api/v1/artifacts/" + id + "/console.log")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assertStatusCode(t, resp, http.StatusOK)
+	if !strings.Contains(string(body), "comment/post 461") {
+		t.Errorf("unexpected console.log: %s", body)
+	}
+
+	for _, path := range []string{"/api/v1/artifacts/" + id + "/screenshot.png", "/api/v1/artifacts/20250101-000000-abcdef", "/api/v1/artifacts/..%2Faccounts.json"} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("failed to request: %v", err)
+		}
+		resp.Body.Close()
+		assertStatusCode(t, resp, http.StatusNotFound)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	app, ts := setupTestApp(t)
+	defer ts.Close()
+
+	acc, err := app.accounts.Create("", "metrics")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	app.accounts.MarkLoggedIn(acc.Key)
+	// 删除 cookies 后账号变为未登录，并计入操作指标
+	if err := app.xiaohongshuService.DeleteCookies(session.WithAccount(context.Background(), acc.Key)); err != nil {
+		t.Fatalf("failed to delete cookies: %v", err)
+	}
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assertStatusCode(t, resp, http.StatusOK)
+
+	for _, line := range []string{
+		// 指标是进程级的，其他测试也可能删除同名账号的 cookies
+		fmt.Sprintf(`xhs_actions_total{account=%q,action="delete_cookies",outcome="success"} `, acc.Key),
+		fmt.Sprintf(`xhs_account_logged_in{account=%q} 0`, acc.Key),
+		`xhs_live_browsers 0`,
+	} {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("metrics missing %s", line)
+		}
+	}
+}
+
 // ==================== 内容获取 ====================
 
 func TestListFeedsHandler(t *testing.T) {
@@ -397,6 +1051,88 @@ func TestListFeedsHandler(t *testing.T) {
 	t.Logf("List feeds result: %+v", result)
 }
 
+func TestFeedsHandler_InvalidPage(t *testing.T) {
+	_, ts := setupTestApp(t)
+	defer ts.Close()
+
+	for _, tc := range []struct {
+		method, path, body, code string
+	}{
+		{"GET", "/api/v1/feeds/list?cursor=bad", "", "INVALID_CURSOR"},
+		{"GET", "/api/v1/feeds/list?limit=-1", "", "INVALID_REQUEST"},
+		{"GET", "/api/v1/feeds/search?keyword=test&cursor=bad", "", "INVALID_CURSOR"},
+		{"POST", "/api/v1/feeds/search", `{"keyword":"test","cursor":"bad"}`, "INVALID_CURSOR"},
+		{"GET", "/api/v1/user/followers?cursor=bad", "", "INVALID_CURSOR"},
+		{"GET", "/api/v1/user/following?limit=-1", "", "INVALID_REQUEST"},
+		{"POST", "/api/v1/user/follow", `{"user_id":"u1"}`, "INVALID_REQUEST"},
+		{"GET", "/api/v1/creator/notes?cursor=bad", "", "INVALID_CURSOR"},
+		{"PUT", "/api/v1/creator/notes/n1", `{}`, "INVALID_REQUEST"},
+		{"POST", "/api/v1/creator/notes/n1/visibility", `{"visibility":"friends"}`, "INVALID_REQUEST"},
+		{"GET", "/api/v1/analytics/notes?cursor=bad", "", "INVALID_CURSOR"},
+		{"GET", "/api/v1/notifications?kind=comments", "", "INVALID_REQUEST"},
+		{"GET", "/api/v1/notifications?kind=likes&cursor=bad", "", "INVALID_CURSOR"},
+		{"GET", "/api/v1/analytics/account?from=2025/01/01", "", "INVALID_DATE_RANGE"},
+		{"GET", "/api/v1/analytics/account?from=2000-01-01&to=2000-01-07", "", "INVALID_DATE_RANGE"},
+		{"GET", "/api/v1/messages?cursor=bad", "", "INVALID_CURSOR"},
+		{"GET", "/api/v1/messages/u1?limit=-1", "", "INVALID_REQUEST"},
+		{"GET", "/api/v1/messages/u1?cursor=bad", "", "INVALID_CURSOR"},
+		{"POST", "/api/v1/messages/u1", `{}`, "INVALID_REQUEST"},
+		{"POST", "/api/v1/messages/u1", `{"content":"` + strings.Repeat("很", 501) + `"}`, "INVALID_REQUEST"},
+		{"GET", "/api/v1/selectors/check?pages=note,home", "", "INVALID_REQUEST"},
+	} {
+		req, _ := http.NewRequest(tc.method, ts.URL+tc.path, bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to request: %v", err)
+		}
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		var result ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		resp.Body.Close()
+		if result.Code != tc.code {
+			t.Errorf("%s %s: expected code %s, got %s", tc.method, tc.path, tc.code, result.Code)
+		}
+	}
+}
+
+func TestListFeedsHandler_AccountBusy(t *testing.T) {
+	appServer, ts := setupTestApp(t)
+	defer ts.Close()
+
+	acc, err := appServer.accounts.Create("", "")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	// 模拟该账号正在执行其他操作
+	release, err := appServer.xiaohongshuService.limiter.Acquire(context.Background(), acc.Key)
+	if err != nil {
+		t.Fatalf("failed to acquire account: %v", err)
+	}
+	defer release()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/feeds/list", nil)
+	req.Header.Set("X-Account-ID", strconv.Itoa(acc.ID))
+	req.Header.Set("X-Wait-Timeout", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	assertStatusCode(t, resp, http.StatusConflict)
+	var result ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Code != "ACCOUNT_BUSY" {
+		t.Errorf("expected code ACCOUNT_BUSY, got %s", result.Code)
+	}
+}
+
 func TestSearchFeedsHandler_GET(t *testing.T) {
 	_, ts := setupTestApp(t)
 	defer ts.Close()
@@ -528,6 +1264,67 @@ func TestPostCommentHandler(t *testing.T) {
 	t.Logf("Post comment result: %+v", result)
 }
 
+func TestPostCommentHandler_QuotaExceeded(t *testing.T) {
+	appServer, ts := setupTestApp(t)
+	defer ts.Close()
+
+	acc, err := appServer.accounts.Create("", "")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	// 刚评论过一次，最小间隔内再次评论会被拒绝
+	if err := appServer.xiaohongshuService.quota.Consume(acc.Key, quota.ActionComment, time.Now()); err != nil {
+		t.Fatalf("failed to consume quota: %v", err)
+	}
+
+	body := jsonBody(PostCommentRequest{FeedID: "12345", XsecToken: "test-token", Content: "测试评论"})
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/v1/feeds/comment", body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Account-ID", strconv.Itoa(acc.ID))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	assertStatusCode(t, resp, http.StatusTooManyRequests)
+	if resp.Header.Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+	var errResp ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp.Code != "QUOTA_EXCEEDED" {
+		t.Errorf("expected code QUOTA_EXCEEDED, got %s", errResp.Code)
+	}
+
+	// 额度查询
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/api/v1/quota", nil)
+	req.Header.Set("X-Account-ID", strconv.Itoa(acc.ID))
+	resp2, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to request: %v", err)
+	}
+	defer resp2.Body.Close()
+	assertSuccess(t, resp2)
+
+	var result struct {
+		Data QuotaResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp2.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	for _, st := range result.Data.Actions {
+		if st.Action == quota.ActionComment && st.UsedDay != 1 {
+			t.Errorf("expected 1 comment today, got %d", st.UsedDay)
+		}
+	}
+	if len(result.Data.Actions) != len(quota.DefaultPolicies()) {
+		t.Errorf("expected %d actions, got %d", len(quota.DefaultPolicies()), len(result.Data.Actions))
+	}
+}
+
 func TestReplyCommentHandler(t *testing.T) {
 	_, ts := setupTestApp(t)
 	defer ts.Close()
@@ -553,6 +1350,34 @@ func TestReplyCommentHandler(t *testing.T) {
 	t.Logf("Reply comment result: %+v", result)
 }
 
+func TestCommentManageHandlers_InvalidRequest(t *testing.T) {
+	_, ts := setupTestApp(t)
+	defer ts.Close()
+
+	for _, tc := range []struct {
+		path, body string
+	}{
+		{"/api/v1/feeds/comment/like", `{"feed_id":"12345","xsec_token":"test-token"}`},
+		{"/api/v1/feeds/comment/pin", `{"feed_id":"12345","xsec_token":"test-token","unpin":true}`},
+		// 删除只能按 comment_id 定位，避免误删同一用户的其他评论
+		{"/api/v1/feeds/comment/delete", `{"feed_id":"12345","xsec_token":"test-token","user_id":"u1"}`},
+	} {
+		resp, err := http.Post(ts.URL+tc.path, "application/json", bytes.NewBufferString(tc.body))
+		if err != nil {
+			t.Fatalf("failed to request: %v", err)
+		}
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		var result ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		resp.Body.Close()
+		if result.Code != "INVALID_REQUEST" {
+			t.Errorf("%s: expected code INVALID_REQUEST, got %s", tc.path, result.Code)
+		}
+	}
+}
+
 // ==================== 集成测试 ====================
 
 // TestAllEndpoints 测试所有端点的基本可访问性
@@ -574,6 +1399,8 @@ func TestAllEndpoints(t *testing.T) {
 		{"GET", "/api/v1/feeds/list", nil},
 		{"GET", "/api/v1/feeds/search?keyword=test", nil},
 		{"GET", "/api/v1/user/me", nil},
+		{"GET", "/api/v1/jobs", nil},
+		{"GET", "/api/v1/quota", nil},
 	}
 
 	for _, ep := range endpoints {
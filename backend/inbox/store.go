@@ -0,0 +1,147 @@
+// Package inbox 按账号保存通知的“已读位置”，让调用方轮询时只拿到上次之后的新通知。
+package inbox
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Watermark 某类通知的已读位置：Time 之前的通知都已看过，
+// IDs 记录恰好在 Time 这一秒的已读通知（通知时间只精确到秒）。
+type Watermark struct {
+	Time time.Time `json:"time"`
+	IDs  []string  `json:"ids,omitempty"`
+}
+
+// IsZero 从未标记过已读
+func (w Watermark) IsZero() bool {
+	return w.Time.IsZero()
+}
+
+// Covers 时间为 t、ID 为 id 的通知是否已读
+func (w Watermark) Covers(t time.Time, id string) bool {
+	if w.IsZero() || t.After(w.Time) {
+		return false
+	}
+	if t.Before(w.Time) {
+		return true
+	}
+	for _, seen := range w.IDs {
+		if seen == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge 返回向后推进后的已读位置，不会回退
+func (w Watermark) Merge(o Watermark) Watermark {
+	switch {
+	case o.Time.After(w.Time):
+		return Watermark{Time: o.Time, IDs: append([]string(nil), o.IDs...)}
+	case o.Time.Equal(w.Time):
+		merged := Watermark{Time: w.Time, IDs: append([]string(nil), w.IDs...)}
+		for _, id := range o.IDs {
+			if !merged.Covers(w.Time, id) {
+				merged.IDs = append(merged.IDs, id)
+			}
+		}
+		return merged
+	}
+	return w
+}
+
+// Store 通知已读位置的本地持久化存储，按账号 Key 与通知类型区分。
+type Store struct {
+	mu        sync.Mutex
+	marks     map[string]map[string]Watermark
+	storePath string
+}
+
+// NewStore 创建存储并从 storePath 恢复已读位置，storePath 为空时只保存在内存中。
+func NewStore(storePath string) (*Store, error) {
+	s := &Store{marks: map[string]map[string]Watermark{}, storePath: storePath}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get 返回账号某类通知的已读位置，未记录时为零值。
+func (s *Store) Get(accountKey, kind string) Watermark {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.marks[accountKey][kind]
+}
+
+// Advance 把已读位置推进到 w（早于当前位置时不变），返回推进后的位置。
+func (s *Store) Advance(accountKey, kind string, w Watermark) (Watermark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byKind, ok := s.marks[accountKey]
+	if !ok {
+		byKind = map[string]Watermark{}
+		s.marks[accountKey] = byKind
+	}
+	prev, had := byKind[kind]
+	next := prev.Merge(w)
+	byKind[kind] = next
+	if err := s.saveLocked(); err != nil {
+		if had {
+			byKind[kind] = prev
+		} else {
+			delete(byKind, kind)
+		}
+		return prev, err
+	}
+	return next, nil
+}
+
+func (s *Store) saveLocked() error {
+	if s.storePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.storePath), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(struct {
+		Accounts map[string]map[string]Watermark `json:"accounts"`
+	}{Accounts: s.marks}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.storePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.storePath)
+}
+
+func (s *Store) load() error {
+	if s.storePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.storePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var payload struct {
+		Accounts map[string]map[string]Watermark `json:"accounts"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return errors.Wrapf(err, "parse inbox store %s", s.storePath)
+	}
+	for key, byKind := range payload.Accounts {
+		s.marks[key] = byKind
+	}
+	return nil
+}
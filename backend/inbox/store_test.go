@@ -0,0 +1,53 @@
+package inbox
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatermarkCovers(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	w := Watermark{Time: t0, IDs: []string{"a"}}
+
+	assert.True(t, w.Covers(t0.Add(-time.Second), "x"))
+	assert.True(t, w.Covers(t0, "a"))
+	assert.False(t, w.Covers(t0, "b"), "同一秒的其他通知仍是新的")
+	assert.False(t, w.Covers(t0.Add(time.Second), "a"))
+	assert.False(t, Watermark{}.Covers(t0, "a"))
+}
+
+func TestStoreAdvance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inbox.json")
+	s, err := NewStore(path)
+	require.NoError(t, err)
+	t0 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.True(t, s.Get("acc_1", "mentions").IsZero())
+
+	w, err := s.Advance("acc_1", "mentions", Watermark{Time: t0, IDs: []string{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, w.IDs)
+
+	// 同一秒合并 ID，更早的位置不会回退
+	w, err = s.Advance("acc_1", "mentions", Watermark{Time: t0, IDs: []string{"a", "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, w.IDs)
+	w, err = s.Advance("acc_1", "mentions", Watermark{Time: t0.Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.True(t, w.Time.Equal(t0))
+
+	// 账号与通知类型互不影响
+	assert.True(t, s.Get("acc_2", "mentions").IsZero())
+	assert.True(t, s.Get("acc_1", "likes").IsZero())
+
+	// 重新打开后恢复
+	reopened, err := NewStore(path)
+	require.NoError(t, err)
+	got := reopened.Get("acc_1", "mentions")
+	assert.True(t, got.Time.Equal(t0))
+	assert.Equal(t, []string{"a", "b"}, got.IDs)
+}
@@ -0,0 +1,513 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Status 任务状态
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Job 一个持久化的后台任务（例如发布图文/视频）。
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AccountKey  string          `json:"account_key"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Status      Status          `json:"status"`
+	Error       string          `json:"error,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	NextRunAt   *time.Time      `json:"next_run_at,omitempty"`
+}
+
+// Handler 执行某一类任务，返回值会序列化后写入 Job.Result。
+type Handler func(ctx context.Context, job *Job) (any, error)
+
+// Options 任务执行与重试策略。
+type Options struct {
+	// MaxAttempts 单个任务最多执行次数（含首次），<=0 时使用默认值 3。
+	MaxAttempts int
+	// BaseDelay 首次重试前的等待时间，之后按指数退避。
+	BaseDelay time.Duration
+	// MaxDelay 退避上限。
+	MaxDelay time.Duration
+	// Retryable 判断错误是否值得重试，为 nil 时所有错误都会重试。
+	Retryable func(error) bool
+	// Resumable 判断服务退出时被中断的任务能否在下次启动时重新执行（例如还没有点击提交），
+	// 为 nil 时都重新执行；返回 false 的任务直接失败。
+	Resumable func(error) bool
+}
+
+// ErrInterrupted 任务执行过程中服务异常退出，无法确定是否已经生效
+var ErrInterrupted = errors.New("service stopped while running, outcome unknown")
+
+// Filter 列表筛选条件，零值表示不过滤。
+type Filter struct {
+	AccountKey string
+	Status     Status
+	Type       string
+	Limit      int
+}
+
+// PanicError 表示任务执行过程中发生的 panic（rod 的 Must* 系列方法失败时会 panic）。
+type PanicError struct {
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap 当 panic 的值本身是 error 时，允许 errors.Is/As 继续匹配。
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+type worker struct {
+	notify chan struct{}
+}
+
+// Manager 管理任务的持久化、按账号串行执行以及失败重试。
+type Manager struct {
+	mu        sync.Mutex
+	jobs      map[string]*Job
+	handlers  map[string]Handler
+	workers   map[string]*worker
+	storePath string
+	opts      Options
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	wg      sync.WaitGroup
+}
+
+// NewManager 创建任务管理器并从 storePath 恢复历史任务。
+// 上次异常退出时处于 running 状态的任务可能已经生效，标记为失败（ErrInterrupted），不再重新执行。
+func NewManager(storePath string, opts Options) (*Manager, error) {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = 30 * time.Second
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = 10 * time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		jobs:      map[string]*Job{},
+		handlers:  map[string]Handler{},
+		workers:   map[string]*worker{},
+		storePath: storePath,
+		opts:      opts,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	if err := m.load(); err != nil {
+		cancel()
+		return nil, err
+	}
+	return m, nil
+}
+
+// Register 注册某类任务的执行函数，需在 Start 之前调用。
+func (m *Manager) Register(jobType string, h Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[jobType] = h
+}
+
+// Start 为所有存在待执行任务的账号启动 worker。
+func (m *Manager) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started {
+		return
+	}
+	m.started = true
+	for _, job := range m.jobs {
+		if job.Status == StatusQueued {
+			m.wakeLocked(job.AccountKey)
+		}
+	}
+}
+
+// Stop 停止所有 worker，正在执行的任务会收到 context 取消。
+// 若 ctx 到期时仍有任务未退出则直接返回，这些任务在下次启动时会被重新执行。
+func (m *Manager) Stop(ctx context.Context) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "wait for running jobs")
+	}
+}
+
+// Enqueue 新建任务并交给对应账号的 worker 执行。
+func (m *Manager) Enqueue(accountKey, jobType string, payload any) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal job payload")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.handlers[jobType]; !ok {
+		return nil, errors.Errorf("unknown job type: %s", jobType)
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          newJobID(),
+		Type:        jobType,
+		AccountKey:  accountKey,
+		Payload:     data,
+		Status:      StatusQueued,
+		MaxAttempts: m.opts.MaxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	m.jobs[job.ID] = job
+	if err := m.saveLocked(); err != nil {
+		delete(m.jobs, job.ID)
+		return nil, err
+	}
+	if m.started {
+		m.wakeLocked(accountKey)
+	}
+
+	copyJob := *job
+	return &copyJob, nil
+}
+
+// Get 按 ID 获取任务快照。
+func (m *Manager) Get(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, errors.Errorf("job %s not found", id)
+	}
+	copyJob := *job
+	return &copyJob, nil
+}
+
+// List 返回按创建时间倒序排列的任务快照。
+func (m *Manager) List(f Filter) []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		if f.AccountKey != "" && job.AccountKey != f.AccountKey {
+			continue
+		}
+		if f.Status != "" && job.Status != f.Status {
+			continue
+		}
+		if f.Type != "" && job.Type != f.Type {
+			continue
+		}
+		out = append(out, *job)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.After(out[j].CreatedAt)
+	})
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	return out
+}
+
+// wakeLocked 确保账号的 worker 已启动并通知其检查队列。
+func (m *Manager) wakeLocked(accountKey string) {
+	w, ok := m.workers[accountKey]
+	if !ok {
+		w = &worker{notify: make(chan struct{}, 1)}
+		m.workers[accountKey] = w
+		m.wg.Add(1)
+		go m.runWorker(accountKey, w)
+	}
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) runWorker(accountKey string, w *worker) {
+	defer m.wg.Done()
+	for m.ctx.Err() == nil {
+		job, wait := m.nextJob(accountKey)
+		if job != nil {
+			m.execute(job)
+			continue
+		}
+
+		var t *time.Timer
+		var timer <-chan time.Time
+		if wait > 0 {
+			t = time.NewTimer(wait)
+			timer = t.C
+		}
+		select {
+		case <-m.ctx.Done():
+			if t != nil {
+				t.Stop()
+			}
+			return
+		case <-w.notify:
+		case <-timer:
+		}
+		if t != nil {
+			t.Stop()
+		}
+	}
+}
+
+// nextJob 取出账号下最早可执行的任务；若都在退避中，返回最近一次可执行的等待时长。
+func (m *Manager) nextJob(accountKey string) (*Job, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var next *Job
+	var wait time.Duration
+	for _, job := range m.jobs {
+		if job.AccountKey != accountKey || job.Status != StatusQueued {
+			continue
+		}
+		if job.NextRunAt != nil && job.NextRunAt.After(now) {
+			d := job.NextRunAt.Sub(now)
+			if wait == 0 || d < wait {
+				wait = d
+			}
+			continue
+		}
+		if next == nil || job.CreatedAt.Before(next.CreatedAt) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, wait
+	}
+
+	next.Status = StatusRunning
+	next.Attempts++
+	next.StartedAt = &now
+	next.UpdatedAt = now
+	next.NextRunAt = nil
+	if err := m.saveLocked(); err != nil {
+		logrus.Warnf("persist job %s failed: %v", next.ID, err)
+	}
+
+	copyJob := *next
+	return &copyJob, 0
+}
+
+func (m *Manager) execute(job *Job) {
+	m.mu.Lock()
+	handler := m.handlers[job.Type]
+	m.mu.Unlock()
+
+	logrus.Infof("job %s(%s) for %s: attempt %d/%d", job.ID, job.Type, job.AccountKey, job.Attempts, job.MaxAttempts)
+
+	var result any
+	var err error
+	if handler == nil {
+		err = errors.Errorf("no handler registered for job type %s", job.Type)
+	} else {
+		result, err = runHandler(m.ctx, handler, job)
+	}
+
+	m.finish(job.ID, result, err)
+}
+
+func runHandler(ctx context.Context, h Handler, job *Job) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r}
+		}
+	}()
+	return h(ctx, job)
+}
+
+func (m *Manager) finish(id string, result any, runErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	job.UpdatedAt = now
+
+	switch {
+	case runErr == nil:
+		job.Status = StatusSucceeded
+		job.Error = ""
+		job.FinishedAt = &now
+		if result != nil {
+			if data, err := json.Marshal(result); err == nil {
+				job.Result = data
+			}
+		}
+		logrus.Infof("job %s succeeded after %d attempt(s)", job.ID, job.Attempts)
+	case m.ctx.Err() != nil && m.resumable(runErr):
+		// 服务正在退出，下次启动时重新执行
+		job.Status = StatusQueued
+		job.Attempts--
+		job.Error = runErr.Error()
+	case m.ctx.Err() == nil && job.Attempts < job.MaxAttempts && m.retryable(runErr):
+		delay := m.backoff(job.Attempts)
+		nextRun := now.Add(delay)
+		job.Status = StatusQueued
+		job.Error = runErr.Error()
+		job.NextRunAt = &nextRun
+		logrus.Warnf("job %s attempt %d failed, retry in %s: %v", job.ID, job.Attempts, delay, runErr)
+	default:
+		job.Status = StatusFailed
+		job.Error = runErr.Error()
+		job.FinishedAt = &now
+		logrus.Errorf("job %s failed after %d attempt(s): %v", job.ID, job.Attempts, runErr)
+	}
+
+	if err := m.saveLocked(); err != nil {
+		logrus.Warnf("persist job %s failed: %v", job.ID, err)
+	}
+}
+
+func (m *Manager) retryable(err error) bool {
+	if m.opts.Retryable == nil {
+		return true
+	}
+	return m.opts.Retryable(err)
+}
+
+func (m *Manager) resumable(err error) bool {
+	if m.opts.Resumable == nil {
+		return true
+	}
+	return m.opts.Resumable(err)
+}
+
+// backoff 第 n 次失败后的等待时间：BaseDelay * 2^(n-1)，不超过 MaxDelay。
+func (m *Manager) backoff(attempt int) time.Duration {
+	delay := m.opts.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= m.opts.MaxDelay {
+			return m.opts.MaxDelay
+		}
+	}
+	return delay
+}
+
+func (m *Manager) saveLocked() error {
+	if m.storePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(m.storePath), 0o755); err != nil {
+		return err
+	}
+	list := make([]*Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		list = append(list, job)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	data, err := json.MarshalIndent(struct {
+		Jobs []*Job `json:"jobs"`
+	}{Jobs: list}, "", "  ")
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再替换，避免进程中断导致任务文件损坏
+	tmp := m.storePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.storePath)
+}
+
+func (m *Manager) load() error {
+	if m.storePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(m.storePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var payload struct {
+		Jobs []*Job `json:"jobs"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return errors.Wrapf(err, "parse jobs store %s", m.storePath)
+	}
+	interrupted := 0
+	for _, job := range payload.Jobs {
+		if job.Status == StatusRunning {
+			// 可能已经点击了提交，重新执行会重复发布
+			logrus.Warnf("job %s was running when the service stopped, marking as failed", job.ID)
+			now := time.Now()
+			job.Status = StatusFailed
+			job.Error = ErrInterrupted.Error()
+			job.UpdatedAt = now
+			job.FinishedAt = &now
+			job.NextRunAt = nil
+			interrupted++
+		}
+		m.jobs[job.ID] = job
+	}
+	if interrupted > 0 {
+		return m.saveLocked()
+	}
+	return nil
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("job_%d", time.Now().UnixNano())
+	}
+	return "job_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,200 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForStatus(t *testing.T, m *Manager, id string, want Status) *Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := m.Get(id)
+		require.NoError(t, err)
+		if job.Status == want {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	job, _ := m.Get(id)
+	t.Fatalf("job %s did not reach %s, last status %s", id, want, job.Status)
+	return nil
+}
+
+func TestEnqueueAndRun(t *testing.T) {
+	m, err := NewManager(filepath.Join(t.TempDir(), "jobs.json"), Options{})
+	require.NoError(t, err)
+	m.Register("echo", func(ctx context.Context, job *Job) (any, error) {
+		return map[string]string{"account": job.AccountKey}, nil
+	})
+	m.Start()
+	defer m.Stop(context.Background())
+
+	job, err := m.Enqueue("acc_1", "echo", map[string]string{"k": "v"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+
+	done := waitForStatus(t, m, job.ID, StatusSucceeded)
+	assert.Equal(t, 1, done.Attempts)
+	assert.JSONEq(t, `{"account":"acc_1"}`, string(done.Result))
+
+	_, err = m.Enqueue("acc_1", "unknown", nil)
+	assert.Error(t, err)
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	m, err := NewManager("", Options{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	var calls int32
+	m.Register("flaky", func(ctx context.Context, job *Job) (any, error) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			panic("element not found")
+		}
+		return nil, nil
+	})
+	m.Start()
+	defer m.Stop(context.Background())
+
+	job, err := m.Enqueue("acc_1", "flaky", nil)
+	require.NoError(t, err)
+
+	done := waitForStatus(t, m, job.ID, StatusSucceeded)
+	assert.Equal(t, 3, done.Attempts)
+}
+
+func TestNonRetryableFailsImmediately(t *testing.T) {
+	permanent := errors.New("标题长度超过限制")
+	m, err := NewManager("", Options{
+		BaseDelay: 10 * time.Millisecond,
+		Retryable: func(err error) bool { return !errors.Is(err, permanent) },
+	})
+	require.NoError(t, err)
+	m.Register("bad", func(ctx context.Context, job *Job) (any, error) {
+		return nil, permanent
+	})
+	m.Start()
+	defer m.Stop(context.Background())
+
+	job, err := m.Enqueue("acc_1", "bad", nil)
+	require.NoError(t, err)
+
+	done := waitForStatus(t, m, job.ID, StatusFailed)
+	assert.Equal(t, 1, done.Attempts)
+	assert.Equal(t, permanent.Error(), done.Error)
+}
+
+func TestRecoverAfterRestart(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "jobs.json")
+
+	// 第一次运行：任务执行中服务退出
+	m1, err := NewManager(storePath, Options{})
+	require.NoError(t, err)
+	started := make(chan struct{})
+	m1.Register("publish", func(ctx context.Context, job *Job) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	m1.Start()
+	job, err := m1.Enqueue("acc_1", "publish", nil)
+	require.NoError(t, err)
+	<-started
+	require.NoError(t, m1.Stop(context.Background()))
+
+	// 第二次运行：任务从磁盘恢复并重新执行
+	m2, err := NewManager(storePath, Options{})
+	require.NoError(t, err)
+	restored, err := m2.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, restored.Status)
+	assert.Equal(t, 0, restored.Attempts)
+
+	m2.Register("publish", func(ctx context.Context, job *Job) (any, error) {
+		return "ok", nil
+	})
+	m2.Start()
+	defer m2.Stop(context.Background())
+
+	done := waitForStatus(t, m2, job.ID, StatusSucceeded)
+	assert.Equal(t, 1, done.Attempts)
+}
+
+func TestShutdownAfterSubmitNotResumed(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "jobs.json")
+	submitted := errors.New("submit may have taken effect")
+
+	// 服务退出时已经点击了提交：不能放回队列
+	m1, err := NewManager(storePath, Options{Resumable: func(err error) bool { return !errors.Is(err, submitted) }})
+	require.NoError(t, err)
+	started := make(chan struct{})
+	m1.Register("publish", func(ctx context.Context, job *Job) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, errors.Wrap(submitted, ctx.Err().Error())
+	})
+	m1.Start()
+	job, err := m1.Enqueue("acc_1", "publish", nil)
+	require.NoError(t, err)
+	<-started
+	require.NoError(t, m1.Stop(context.Background()))
+
+	m2, err := NewManager(storePath, Options{})
+	require.NoError(t, err)
+	restored, err := m2.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, restored.Status)
+	assert.Equal(t, 1, restored.Attempts)
+	assert.Contains(t, restored.Error, submitted.Error())
+}
+
+func TestCrashedRunningJobMarkedFailed(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "jobs.json")
+	require.NoError(t, os.WriteFile(storePath, []byte(`{"jobs": [
+		{"id": "job_1", "type": "publish", "account_key": "acc_1", "status": "running", "attempts": 1, "max_attempts": 3},
+		{"id": "job_2", "type": "publish", "account_key": "acc_1", "status": "queued", "attempts": 0, "max_attempts": 3}
+	]}`), 0o644))
+
+	// 进程崩溃时仍在执行的任务可能已经发布，不再重新执行
+	m, err := NewManager(storePath, Options{})
+	require.NoError(t, err)
+	var runs int32
+	m.Register("publish", func(ctx context.Context, job *Job) (any, error) {
+		atomic.AddInt32(&runs, 1)
+		return nil, nil
+	})
+	m.Start()
+	defer m.Stop(context.Background())
+
+	waitForStatus(t, m, "job_2", StatusSucceeded)
+	crashed, err := m.Get("job_1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, crashed.Status)
+	assert.Equal(t, ErrInterrupted.Error(), crashed.Error)
+	assert.NotNil(t, crashed.FinishedAt)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+
+	// 失败状态已写回磁盘
+	reloaded, err := NewManager(storePath, Options{})
+	require.NoError(t, err)
+	again, err := reloaded.Get("job_1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, again.Status)
+}
+
+func TestBackoffCapped(t *testing.T) {
+	m, err := NewManager("", Options{BaseDelay: time.Second, MaxDelay: 5 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, m.backoff(1))
+	assert.Equal(t, 2*time.Second, m.backoff(2))
+	assert.Equal(t, 4*time.Second, m.backoff(3))
+	assert.Equal(t, 5*time.Second, m.backoff(4))
+}
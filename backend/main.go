@@ -1,14 +1,27 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/sirupsen/logrus"
-	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
+	"github.com/xpzouying/xiaohongshu-mcp/artifacts"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/calendar"
+	"github.com/xpzouying/xiaohongshu-mcp/concurrency"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/inbox"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 func resolveDefaultChromePath() string {
@@ -33,7 +46,36 @@ func resolveDefaultChromePath() string {
 	return ""
 }
 
+// accountStorePaths 返回账号文件与浏览器用户目录的根目录
+func accountStorePaths() (storePath, profileBase string) {
+	storePath = os.Getenv("ACCOUNTS_STORE")
+	if storePath == "" {
+		storePath = "accounts.json"
+	}
+	profileBase = os.Getenv("USER_DATA_BASE_DIR")
+	if profileBase == "" {
+		profileBase = "accounts"
+	}
+	return storePath, profileBase
+}
+
 func main() {
+	// 子命令
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "rotate-key":
+			if err := runRotateKey(os.Args[2:]); err != nil {
+				logrus.Fatalf("rotate-key: %v", err)
+			}
+			return
+		case "import-sqlite":
+			if err := runImportSQLite(os.Args[2:]); err != nil {
+				logrus.Fatalf("import-sqlite: %v", err)
+			}
+			return
+		}
+	}
+
 	// 日志级别：默认 info，可用环境变量 LOG_LEVEL=debug 切换
 	levelStr := os.Getenv("LOG_LEVEL")
 	if levelStr == "" {
@@ -49,10 +91,12 @@ func main() {
 		headless bool
 		binPath  string // 浏览器二进制文件路径
 		port     string
+		storage  string // 账号与 cookies 的存储后端
 	)
 	flag.BoolVar(&headless, "headless", true, "是否无头模式")
 	flag.StringVar(&binPath, "bin", "", "浏览器二进制文件路径")
 	flag.StringVar(&port, "port", ":18060", "端口")
+	flag.StringVar(&storage, "storage", storageBackend(), "账号与 cookies 存储后端：json 或 sqlite")
 	flag.Parse()
 
 	if len(binPath) == 0 {
@@ -71,26 +115,177 @@ func main() {
 	configs.InitHeadless(headless)
 	configs.SetBinPath(binPath)
 
-	storePath := os.Getenv("ACCOUNTS_STORE")
-	if storePath == "" {
-		storePath = "accounts.json"
+	// 落盘加密：配置 XHS_ENCRYPTION_KEY 或 XHS_ENCRYPTION_KEY_FILE 后，cookies 与代理凭据加密保存
+	box, err := secret.FromEnv()
+	if err != nil {
+		logrus.Fatalf("failed to load encryption key: %v", err)
 	}
-	profileBase := os.Getenv("USER_DATA_BASE_DIR")
-	if profileBase == "" {
-		profileBase = "accounts"
+	if box != nil {
+		secret.SetDefault(box)
+		logrus.Infof("已启用 cookies 与代理凭据加密，密钥指纹 %s", box.ID())
 	}
 
-	accountManager, err := accounts.NewManager(storePath, profileBase)
+	storePath, _ := accountStorePaths()
+	accountManager, where, err := openAccountManager(storage)
 	if err != nil {
 		logrus.Fatalf("failed to init account manager: %v", err)
 	}
+	logrus.Infof("账号存储: %s (%s)", storage, where)
+
+	jobsPath := os.Getenv("JOBS_STORE")
+	if jobsPath == "" {
+		jobsPath = "jobs.json"
+	}
+	jobManager, err := jobs.NewManager(jobsPath, jobs.Options{Retryable: isTransientError, Resumable: isResumableError})
+	if err != nil {
+		logrus.Fatalf("failed to init job manager: %v", err)
+	}
+
+	calendarPath := os.Getenv("CALENDAR_STORE")
+	if calendarPath == "" {
+		calendarPath = "calendar.json"
+	}
+	// 服务停止期间错过的条目，超过 CALENDAR_MAX_LATENESS 后不再补发
+	calendarOpts := calendar.Options{MaxLateness: time.Hour}
+	if v := os.Getenv("CALENDAR_MAX_LATENESS"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logrus.Fatalf("invalid CALENDAR_MAX_LATENESS %q: %v", v, err)
+		}
+		calendarOpts.MaxLateness = d
+	}
+	calendarStore, err := calendar.NewStore(calendarPath, calendarOpts)
+	if err != nil {
+		logrus.Fatalf("failed to init calendar store: %v", err)
+	}
+
+	// 浏览器池：每个账号保留一个常驻浏览器，空闲超时后关闭
+	poolCfg := browser.PoolConfig{MaxBrowsers: 4, IdleTTL: 5 * time.Minute}
+	if v := os.Getenv("BROWSER_POOL_MAX"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			logrus.Fatalf("invalid BROWSER_POOL_MAX %q: %v", v, err)
+		}
+		poolCfg.MaxBrowsers = n
+	}
+	if v := os.Getenv("BROWSER_POOL_IDLE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logrus.Fatalf("invalid BROWSER_POOL_IDLE_TTL %q: %v", v, err)
+		}
+		poolCfg.IdleTTL = d
+	}
+	browserPool := browser.NewPool(poolCfg)
+
+	// 并发限制：同一账号的操作依次执行，所有账号同时运行的操作数有上限
+	limiterCfg := concurrency.Config{MaxParallel: 4, Wait: 2 * time.Minute}
+	if v := os.Getenv("MAX_PARALLEL_BROWSERS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			logrus.Fatalf("invalid MAX_PARALLEL_BROWSERS %q: %v", v, err)
+		}
+		limiterCfg.MaxParallel = n
+	}
+	if v := os.Getenv("ACCOUNT_WAIT_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			logrus.Fatalf("invalid ACCOUNT_WAIT_TIMEOUT %q: %v", v, err)
+		}
+		limiterCfg.Wait = d
+	}
+	limiter := concurrency.NewLimiter(limiterCfg)
+
+	// 互动额度：策略文件由 QUOTA_POLICY 指定，计数默认与账号文件放在同一目录
+	policies, err := quota.LoadPolicies(os.Getenv("QUOTA_POLICY"))
+	if err != nil {
+		logrus.Fatalf("failed to load quota policy: %v", err)
+	}
+	quotaPath := os.Getenv("QUOTA_STORE")
+	if quotaPath == "" {
+		quotaPath = filepath.Join(filepath.Dir(storePath), "quota.json")
+	}
+	quotaManager, err := quota.NewManager(quotaPath, policies)
+	if err != nil {
+		logrus.Fatalf("failed to init quota manager: %v", err)
+	}
+
+	// 审计日志：记录每个账号的发布、评论、点赞等操作
+	auditLog, auditWhere, err := openAuditLog(storage)
+	if err != nil {
+		logrus.Fatalf("failed to init audit log: %v", err)
+	}
+	logrus.Infof("审计日志: %s", auditWhere)
+
+	// 通知已读位置：INBOX_STORE 指定，默认与账号文件放在同一目录
+	inboxPath := os.Getenv("INBOX_STORE")
+	if inboxPath == "" {
+		inboxPath = filepath.Join(filepath.Dir(storePath), "inbox.json")
+	}
+	inboxStore, err := inbox.NewStore(inboxPath)
+	if err != nil {
+		logrus.Fatalf("failed to init inbox store: %v", err)
+	}
+
+	// 失败现场：操作出错时的截图、HTML 与控制台日志，ARTIFACTS_DIR 指定目录，默认与账号文件放在同一目录，
+	// 只保留最近 ARTIFACTS_KEEP 个（默认 100）
+	artifactsDir := os.Getenv("ARTIFACTS_DIR")
+	if artifactsDir == "" {
+		artifactsDir = filepath.Join(filepath.Dir(storePath), "artifacts")
+	}
+	artifactsKeep := 100
+	if v := os.Getenv("ARTIFACTS_KEEP"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			logrus.Fatalf("invalid ARTIFACTS_KEEP %q: %v", v, err)
+		}
+		artifactsKeep = n
+	}
+	artifactStore, err := artifacts.NewStore(artifactsDir, artifactsKeep)
+	if err != nil {
+		logrus.Fatalf("failed to init artifact store: %v", err)
+	}
+
+	// 页面选择器：SELECTORS_FILE 指定覆盖文件，修改后自动重新加载
+	if path := os.Getenv("SELECTORS_FILE"); path != "" {
+		reg, err := xiaohongshu.LoadSelectors(path)
+		if err != nil {
+			logrus.Fatalf("failed to load selectors: %v", err)
+		}
+		xiaohongshu.SetSelectors(reg)
+		go xiaohongshu.WatchSelectors(context.Background(), path, 10*time.Second)
+		logrus.Infof("页面选择器: %s (版本 %s)", path, reg.Version)
+	}
+
+	// 链路追踪：OTEL_TRACES_EXPORTER=otlp 时导出到 OTLP collector，stdout 时打印到标准输出，默认不启用
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		logrus.Fatalf("failed to init tracing: %v", err)
+	}
 
 	// 初始化服务
-	xiaohongshuService := NewXiaohongshuService(accountManager)
+	xiaohongshuService := NewXiaohongshuService(accountManager, jobManager, calendarStore, browserPool, limiter, quotaManager, auditLog, inboxStore, artifactStore)
+
+	// API Key 鉴权：API_KEYS_FILE 指定 Key 配置文件，未配置时所有人都可以调用接口
+	var apiKeys *apikey.Keyring
+	if path := os.Getenv("API_KEYS_FILE"); path != "" {
+		if apiKeys, err = apikey.LoadFile(path); err != nil {
+			logrus.Fatalf("failed to load api keys: %v", err)
+		}
+		logrus.Infof("已启用 API Key 鉴权: %v", apiKeys.Names())
+	} else {
+		logrus.Warn("未配置 API_KEYS_FILE，HTTP API 与 MCP 端点不鉴权，请勿暴露到公网")
+	}
 
 	// 创建并启动应用服务器
-	appServer := NewAppServer(xiaohongshuService)
+	appServer := NewAppServer(xiaohongshuService, apiKeys)
 	if err := appServer.Start(port); err != nil {
 		logrus.Fatalf("failed to run server: %v", err)
 	}
+
+	// 退出前导出尚未发送的 span
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		logrus.Warnf("failed to flush traces: %v", err)
+	}
 }
@@ -7,15 +7,45 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/calendar"
+	"github.com/xpzouying/xiaohongshu-mcp/concurrency"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
 	"github.com/xpzouying/xiaohongshu-mcp/session"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // MCP 工具处理函数
 
+// errorText 将额度超限、账号忙等可重试的错误转换为便于调用方理解的提示
+func errorText(err error) string {
+	var exceeded *quota.ExceededError
+	switch {
+	case errors.As(err, &exceeded):
+		return fmt.Sprintf("操作频率超限（%s %s），请在 %s 后重试", exceeded.Action, exceeded.Rule, exceeded.RetryAfter.Round(time.Second))
+	case errors.Is(err, concurrency.ErrAccountBusy):
+		return "账号正在执行其他操作，请稍后重试"
+	case errors.Is(err, concurrency.ErrNoCapacity):
+		return "同时运行的浏览器操作已达上限，请稍后重试"
+	case errors.Is(err, xiaohongshu.ErrInvalidCursor):
+		return "cursor 无效，请使用上一页返回的 next_cursor，且不要修改关键词与筛选条件"
+	case errors.Is(err, xiaohongshu.ErrCreatorNoteNotFound):
+		return "创作者中心中没有找到该笔记，请先用 list_my_notes 确认 note_id"
+	case errors.Is(err, xiaohongshu.ErrInvalidDateRange):
+		return "日期范围无效: " + err.Error()
+	case errors.Is(err, xiaohongshu.ErrConversationNotFound):
+		return "没有找到与该用户的私信会话，请先用 list_conversations 确认 user_id"
+	case errors.Is(err, xiaohongshu.ErrConversationMismatch):
+		return "打开的会话或私信接收方与目标用户不一致，已停止操作: " + err.Error()
+	}
+	return err.Error()
+}
+
 // handleCheckLoginStatus 处理检查登录状态
 func (s *AppServer) handleCheckLoginStatus(ctx context.Context) *MCPToolResult {
 	logrus.Info("MCP: 检查登录状态")
@@ -25,7 +55,7 @@ func (s *AppServer) handleCheckLoginStatus(ctx context.Context) *MCPToolResult {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "检查登录状态失败: " + err.Error(),
+				Text: "检查登录状态失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -55,7 +85,7 @@ func (s *AppServer) handleGetLoginQrcode(ctx context.Context) *MCPToolResult {
 	result, err := s.xiaohongshuService.GetLoginQrcode(ctx)
 	if err != nil {
 		return &MCPToolResult{
-			Content: []MCPContent{{Type: "text", Text: "获取登录扫码图片失败: " + err.Error()}},
+			Content: []MCPContent{{Type: "text", Text: "获取登录扫码图片失败: " + errorText(err)}},
 			IsError: true,
 		}
 	}
@@ -94,7 +124,7 @@ func (s *AppServer) handleDeleteCookies(ctx context.Context) *MCPToolResult {
 	err := s.xiaohongshuService.DeleteCookies(ctx)
 	if err != nil {
 		return &MCPToolResult{
-			Content: []MCPContent{{Type: "text", Text: "删除 cookies 失败: " + err.Error()}},
+			Content: []MCPContent{{Type: "text", Text: "删除 cookies 失败: " + errorText(err)}},
 			IsError: true,
 		}
 	}
@@ -143,19 +173,19 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 		Tags:    tags,
 	}
 
-	// 执行发布
-	result, err := s.xiaohongshuService.PublishContent(ctx, req)
+	// 提交发布任务，由后台异步执行
+	job, err := s.xiaohongshuService.EnqueuePublishContent(ctx, req)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "发布失败: " + err.Error(),
+				Text: "发布失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
 	}
 
-	resultText := fmt.Sprintf("内容发布成功: %+v", result)
+	resultText := fmt.Sprintf("发布任务已提交，job_id: %s\n\n发布在后台执行，可使用 get_job_status 查询进度。", job.ID)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -199,7 +229,7 @@ func (s *AppServer) handleSaveDraftContent(ctx context.Context, args map[string]
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "保存草稿失败: " + err.Error(),
+				Text: "保存草稿失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -237,11 +267,14 @@ func (s *AppServer) handlePublishContentScheduled(ctx context.Context, args map[
 		}
 	}
 
+	publishAt, _ := args["publish_at"].(string)
+
 	req := &PublishRequest{
-		Title:   title,
-		Content: content,
-		Images:  imagePaths,
-		Tags:    tags,
+		Title:     title,
+		Content:   content,
+		Images:    imagePaths,
+		Tags:      tags,
+		PublishAt: publishAt,
 	}
 
 	result, err := s.xiaohongshuService.PublishContentScheduled(ctx, req)
@@ -249,7 +282,7 @@ func (s *AppServer) handlePublishContentScheduled(ctx context.Context, args map[
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "定时发布失败: " + err.Error(),
+				Text: "定时发布失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -302,19 +335,19 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 		Tags:    tags,
 	}
 
-	// 执行发布
-	result, err := s.xiaohongshuService.PublishVideo(ctx, req)
+	// 提交发布任务，由后台异步执行
+	job, err := s.xiaohongshuService.EnqueuePublishVideo(ctx, req)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "发布失败: " + err.Error(),
+				Text: "发布失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
 	}
 
-	resultText := fmt.Sprintf("视频发布成功: %+v", result)
+	resultText := fmt.Sprintf("视频发布任务已提交，job_id: %s\n\n发布在后台执行，可使用 get_job_status 查询进度。", job.ID)
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -349,11 +382,14 @@ func (s *AppServer) handlePublishVideoScheduled(ctx context.Context, args map[st
 		}
 	}
 
+	publishAt, _ := args["publish_at"].(string)
+
 	req := &PublishVideoRequest{
-		Title:   title,
-		Content: content,
-		Video:   videoPath,
-		Tags:    tags,
+		Title:     title,
+		Content:   content,
+		Video:     videoPath,
+		Tags:      tags,
+		PublishAt: publishAt,
 	}
 
 	result, err := s.xiaohongshuService.PublishVideoScheduled(ctx, req)
@@ -361,7 +397,7 @@ func (s *AppServer) handlePublishVideoScheduled(ctx context.Context, args map[st
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "定时发布失败: " + err.Error(),
+				Text: "定时发布失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -416,7 +452,7 @@ func (s *AppServer) handleSaveDraftVideo(ctx context.Context, args map[string]in
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "保存草稿失败: " + err.Error(),
+				Text: "保存草稿失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -432,15 +468,15 @@ func (s *AppServer) handleSaveDraftVideo(ctx context.Context, args map[string]in
 }
 
 // handleListFeeds 处理获取Feeds列表
-func (s *AppServer) handleListFeeds(ctx context.Context) *MCPToolResult {
+func (s *AppServer) handleListFeeds(ctx context.Context, args ListFeedsArgs) *MCPToolResult {
 	logrus.Info("MCP: 获取Feeds列表")
 
-	result, err := s.xiaohongshuService.ListFeeds(ctx)
+	result, err := s.xiaohongshuService.ListFeeds(ctx, xiaohongshu.PageOptions{Limit: args.Limit, Cursor: args.Cursor})
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "获取Feeds列表失败: " + err.Error(),
+				Text: "获取Feeds列表失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -491,12 +527,12 @@ func (s *AppServer) handleSearchFeeds(ctx context.Context, args SearchFeedsArgs)
 		Location:    args.Filters.Location,
 	}
 
-	result, err := s.xiaohongshuService.SearchFeeds(ctx, args.Keyword, filter)
+	result, err := s.xiaohongshuService.SearchFeeds(ctx, args.Keyword, xiaohongshu.PageOptions{Limit: args.Limit, Cursor: args.Cursor}, filter)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "搜索Feeds失败: " + err.Error(),
+				Text: "搜索Feeds失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -614,7 +650,7 @@ func (s *AppServer) handleGetFeedDetail(ctx context.Context, args map[string]any
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "获取Feed详情失败: " + err.Error(),
+				Text: "获取Feed详情失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -667,14 +703,30 @@ func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any)
 		}
 	}
 
-	logrus.Infof("MCP: 获取用户主页 - User ID: %s", userID)
+	opts := xiaohongshu.ProfileNotesOptions{}
+	opts.MaxNotes, _ = args["max_notes"].(int)
+	if since, _ := args["since"].(string); since != "" {
+		t, err := xiaohongshu.ParseScheduleTime(since)
+		if err != nil {
+			return &MCPToolResult{
+				Content: []MCPContent{{
+					Type: "text",
+					Text: "获取用户主页失败: since 格式错误: " + err.Error(),
+				}},
+				IsError: true,
+			}
+		}
+		opts.Since = t
+	}
+
+	logrus.Infof("MCP: 获取用户主页 - User ID: %s, max_notes: %d", userID, opts.MaxNotes)
 
-	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken)
+	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken, opts)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "获取用户主页失败: " + err.Error(),
+				Text: "获取用户主页失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -726,7 +778,7 @@ func (s *AppServer) handleLikeFeed(ctx context.Context, args map[string]interfac
 		if unlike {
 			action = "取消点赞"
 		}
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + errorText(err)}}, IsError: true}
 	}
 
 	action := "点赞"
@@ -762,7 +814,7 @@ func (s *AppServer) handleFavoriteFeed(ctx context.Context, args map[string]inte
 		if unfavorite {
 			action = "取消收藏"
 		}
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + errorText(err)}}, IsError: true}
 	}
 
 	action := "收藏"
@@ -818,7 +870,7 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "发表评论失败: " + err.Error(),
+				Text: "发表评论失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -892,7 +944,7 @@ func (s *AppServer) handleReplyComment(ctx context.Context, args map[string]inte
 		return &MCPToolResult{
 			Content: []MCPContent{{
 				Type: "text",
-				Text: "回复评论失败: " + err.Error(),
+				Text: "回复评论失败: " + errorText(err),
 			}},
 			IsError: true,
 		}
@@ -907,3 +959,522 @@ func (s *AppServer) handleReplyComment(ctx context.Context, args map[string]inte
 		}},
 	}
 }
+
+// handleGetJobStatus 查询后台任务状态
+func (s *AppServer) handleGetJobStatus(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	jobID, _ := args["job_id"].(string)
+	logrus.Infof("MCP: 查询任务状态 - job_id: %s", jobID)
+
+	if jobID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "查询失败: 缺少 job_id 参数",
+			}},
+			IsError: true,
+		}
+	}
+
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "查询任务失败: " + err.Error(),
+			}},
+			IsError: true,
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("查询任务成功，但序列化失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// jsonResult 将 v 序列化为 MCP 文本结果
+func jsonResult(prefix string, v any) *MCPToolResult {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("%s，但序列化失败: %v", prefix, err),
+			}},
+			IsError: true,
+		}
+	}
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: prefix + ":\n" + string(jsonData),
+		}},
+	}
+}
+
+func errorResult(prefix string, err error) *MCPToolResult {
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: prefix + ": " + err.Error(),
+		}},
+		IsError: true,
+	}
+}
+
+// handleListCalendar 列出内容日历
+func (s *AppServer) handleListCalendar(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.Info("MCP: 列出内容日历")
+
+	filter := calendar.Filter{}
+	if status, _ := args["status"].(string); status != "" {
+		filter.Status = calendar.Status(status)
+	}
+	if accountID, _ := args["account_id"].(int); accountID > 0 {
+		acc, err := s.getAccount(ctx, accountID)
+		if err != nil {
+			return errorResult("获取内容日历失败", err)
+		}
+		filter.AccountKey = acc.Key
+	}
+	for key, dst := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		v, _ := args[key].(string)
+		if v == "" {
+			continue
+		}
+		t, err := xiaohongshu.ParseScheduleTime(v)
+		if err != nil {
+			return errorResult("获取内容日历失败", err)
+		}
+		*dst = t
+	}
+
+	return jsonResult("获取内容日历成功", s.listCalendar(ctx, filter))
+}
+
+// handleAddCalendarEntry 添加日历条目
+func (s *AppServer) handleAddCalendarEntry(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	accountID, _ := args["account_id"].(int)
+	title, _ := args["title"].(string)
+	content, _ := args["content"].(string)
+	imagesInterface, _ := args["images"].([]interface{})
+	video, _ := args["video"].(string)
+	tagsInterface, _ := args["tags"].([]interface{})
+	publishAt, _ := args["publish_at"].(string)
+
+	var images []string
+	for _, img := range imagesInterface {
+		if imgStr, ok := img.(string); ok {
+			images = append(images, imgStr)
+		}
+	}
+
+	var tags []string
+	for _, tag := range tagsInterface {
+		if tagStr, ok := tag.(string); ok {
+			tags = append(tags, tagStr)
+		}
+	}
+
+	logrus.Infof("MCP: 添加日历条目 - 标题: %s, 发布时间: %s", title, publishAt)
+
+	entry, err := s.xiaohongshuService.AddCalendarEntry(ctx, accountID, &CalendarEntryRequest{
+		Title:     title,
+		Content:   content,
+		Images:    images,
+		Video:     video,
+		Tags:      tags,
+		PublishAt: publishAt,
+	})
+	if err != nil {
+		return errorResult("添加日历条目失败", err)
+	}
+
+	return jsonResult("日历条目已添加", entry)
+}
+
+// handleMoveCalendarEntry 调整日历条目发布时间
+func (s *AppServer) handleMoveCalendarEntry(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	entryID, _ := args["entry_id"].(string)
+	publishAt, _ := args["publish_at"].(string)
+	logrus.Infof("MCP: 调整日历条目 - %s -> %s", entryID, publishAt)
+
+	if _, err := s.getCalendarEntry(ctx, entryID); err != nil {
+		return errorResult("调整日历条目失败", err)
+	}
+	entry, err := s.xiaohongshuService.MoveCalendarEntry(entryID, publishAt)
+	if err != nil {
+		return errorResult("调整日历条目失败", err)
+	}
+
+	return jsonResult("日历条目已调整", entry)
+}
+
+// handleCancelCalendarEntry 取消日历条目
+func (s *AppServer) handleCancelCalendarEntry(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	entryID, _ := args["entry_id"].(string)
+	logrus.Infof("MCP: 取消日历条目 - %s", entryID)
+
+	if _, err := s.getCalendarEntry(ctx, entryID); err != nil {
+		return errorResult("取消日历条目失败", err)
+	}
+	entry, err := s.xiaohongshuService.CancelCalendarEntry(entryID)
+	if err != nil {
+		return errorResult("取消日历条目失败", err)
+	}
+
+	return jsonResult("日历条目已取消", entry)
+}
+
+// handleGetActionHistory 查询操作记录
+func (s *AppServer) handleGetActionHistory(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.Info("MCP: 查询操作记录")
+
+	filter := audit.Filter{}
+	if action, _ := args["action"].(string); action != "" {
+		filter.Action = audit.Action(action)
+	}
+	filter.FeedID, _ = args["feed_id"].(string)
+	if content, _ := args["content"].(string); content != "" {
+		filter.ContentHash = audit.HashContent(content)
+	}
+	if outcome, _ := args["outcome"].(string); outcome != "" {
+		filter.Outcome = audit.Outcome(outcome)
+	}
+	filter.Limit, _ = args["limit"].(int)
+	if accountID, _ := args["account_id"].(int); accountID > 0 {
+		acc, err := s.getAccount(ctx, accountID)
+		if err != nil {
+			return errorResult("查询操作记录失败", err)
+		}
+		filter.AccountKey = acc.Key
+	}
+	for param, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		v, _ := args[param].(string)
+		if v == "" {
+			continue
+		}
+		t, err := xiaohongshu.ParseScheduleTime(v)
+		if err != nil {
+			return errorResult("查询操作记录失败", errors.Wrapf(err, "%s 无效", param))
+		}
+		*dst = t
+	}
+
+	res, err := s.queryAudit(ctx, filter)
+	if err != nil {
+		return errorResult("查询操作记录失败", err)
+	}
+	return jsonResult("查询操作记录成功", res)
+}
+
+// handleFollowUser 处理关注/取消关注用户
+func (s *AppServer) handleFollowUser(ctx context.Context, args FollowUserArgs, unfollow bool) *MCPToolResult {
+	action := "关注"
+	if unfollow {
+		action = "取消关注"
+	}
+	if args.UserID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: 缺少user_id参数"}}, IsError: true}
+	}
+	if args.XsecToken == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: 缺少xsec_token参数"}}, IsError: true}
+	}
+	logrus.Infof("MCP: %s用户 - User ID: %s", action, args.UserID)
+
+	var res *FollowResult
+	var err error
+	if unfollow {
+		res, err = s.xiaohongshuService.UnfollowUser(ctx, args.UserID, args.XsecToken)
+	} else {
+		res, err = s.xiaohongshuService.FollowUser(ctx, args.UserID, args.XsecToken)
+	}
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + errorText(err)}}, IsError: true}
+	}
+
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - User ID: %s, 当前关系: %s", res.Message, res.UserID, res.Status)}}}
+}
+
+// handleListFollows 获取当前账号的关注或粉丝列表
+func (s *AppServer) handleListFollows(ctx context.Context, kind xiaohongshu.FollowListKind, args ListFollowsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取%s列表", kind)
+
+	result, err := s.xiaohongshuService.ListFollows(ctx, kind, xiaohongshu.PageOptions{Limit: args.Limit, Cursor: args.Cursor})
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "获取关注/粉丝列表失败: " + errorText(err),
+			}},
+			IsError: true,
+		}
+	}
+	return jsonResult("获取关注/粉丝列表成功", result)
+}
+
+// checkCommentArgs 校验评论管理工具的公共参数，返回错误提示
+func checkCommentArgs(feedID, xsecToken, commentID, userID string) string {
+	switch {
+	case feedID == "":
+		return "缺少feed_id参数"
+	case xsecToken == "":
+		return "缺少xsec_token参数"
+	case commentID == "" && userID == "":
+		return "缺少comment_id或user_id参数"
+	}
+	return ""
+}
+
+// handleLikeComment 处理评论点赞/取消点赞
+func (s *AppServer) handleLikeComment(ctx context.Context, args LikeCommentArgs) *MCPToolResult {
+	action := "评论点赞"
+	if args.Unlike {
+		action = "取消评论点赞"
+	}
+	if msg := checkCommentArgs(args.FeedID, args.XsecToken, args.CommentID, args.UserID); msg != "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + msg}}, IsError: true}
+	}
+	logrus.Infof("MCP: %s - Feed ID: %s, Comment ID: %s, User ID: %s", action, args.FeedID, args.CommentID, args.UserID)
+
+	res, err := s.xiaohongshuService.LikeComment(ctx, args.FeedID, args.XsecToken, args.CommentID, args.UserID, args.Unlike)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + errorText(err)}}, IsError: true}
+	}
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - Feed ID: %s", res.Message, res.FeedID)}}}
+}
+
+// handleDeleteComment 处理删除评论
+func (s *AppServer) handleDeleteComment(ctx context.Context, args DeleteCommentArgs) *MCPToolResult {
+	if args.CommentID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除评论失败: 缺少comment_id参数"}}, IsError: true}
+	}
+	if msg := checkCommentArgs(args.FeedID, args.XsecToken, args.CommentID, ""); msg != "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除评论失败: " + msg}}, IsError: true}
+	}
+	logrus.Infof("MCP: 删除评论 - Feed ID: %s, Comment ID: %s", args.FeedID, args.CommentID)
+
+	res, err := s.xiaohongshuService.DeleteComment(ctx, args.FeedID, args.XsecToken, args.CommentID)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除评论失败: " + errorText(err)}}, IsError: true}
+	}
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - Comment ID: %s", res.Message, res.CommentID)}}}
+}
+
+// handlePinComment 处理评论置顶/取消置顶
+func (s *AppServer) handlePinComment(ctx context.Context, args PinCommentArgs) *MCPToolResult {
+	action := "置顶评论"
+	if args.Unpin {
+		action = "取消置顶评论"
+	}
+	if msg := checkCommentArgs(args.FeedID, args.XsecToken, args.CommentID, args.UserID); msg != "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + msg}}, IsError: true}
+	}
+	logrus.Infof("MCP: %s - Feed ID: %s, Comment ID: %s, User ID: %s", action, args.FeedID, args.CommentID, args.UserID)
+
+	res, err := s.xiaohongshuService.PinComment(ctx, args.FeedID, args.XsecToken, args.CommentID, args.UserID, args.Unpin)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + errorText(err)}}, IsError: true}
+	}
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - Feed ID: %s", res.Message, res.FeedID)}}}
+}
+
+// handleListMyNotes 列出创作者中心的已发布笔记
+func (s *AppServer) handleListMyNotes(ctx context.Context, args ListMyNotesArgs) *MCPToolResult {
+	logrus.Info("MCP: 获取已发布笔记")
+
+	result, err := s.xiaohongshuService.ListCreatorNotes(ctx, xiaohongshu.PageOptions{Limit: args.Limit, Cursor: args.Cursor})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取已发布笔记失败: " + errorText(err)}}, IsError: true}
+	}
+	return jsonResult("获取已发布笔记成功", result)
+}
+
+// handleEditMyNote 编辑已发布笔记
+func (s *AppServer) handleEditMyNote(ctx context.Context, args EditMyNoteArgs) *MCPToolResult {
+	if args.NoteID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "编辑笔记失败: 缺少note_id参数"}}, IsError: true}
+	}
+	if args.Title == "" && args.Content == "" && len(args.Tags) == 0 {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "编辑笔记失败: title、content、tags 至少提供一个"}}, IsError: true}
+	}
+	logrus.Infof("MCP: 编辑笔记 - Note ID: %s", args.NoteID)
+
+	res, err := s.xiaohongshuService.EditCreatorNote(ctx, args.NoteID, &EditNoteRequest{Title: args.Title, Content: args.Content, Tags: args.Tags})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "编辑笔记失败: " + errorText(err)}}, IsError: true}
+	}
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - Note ID: %s", res.Message, res.NoteID)}}}
+}
+
+// handleDeleteMyNote 删除已发布笔记
+func (s *AppServer) handleDeleteMyNote(ctx context.Context, args MyNoteArgs) *MCPToolResult {
+	if args.NoteID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除笔记失败: 缺少note_id参数"}}, IsError: true}
+	}
+	logrus.Infof("MCP: 删除笔记 - Note ID: %s", args.NoteID)
+
+	res, err := s.xiaohongshuService.DeleteCreatorNote(ctx, args.NoteID)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "删除笔记失败: " + errorText(err)}}, IsError: true}
+	}
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - Note ID: %s", res.Message, res.NoteID)}}}
+}
+
+// handleSetNoteVisibility 修改笔记可见范围
+func (s *AppServer) handleSetNoteVisibility(ctx context.Context, args NoteVisibilityArgs) *MCPToolResult {
+	if args.NoteID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "修改可见范围失败: 缺少note_id参数"}}, IsError: true}
+	}
+	if args.Visibility != xiaohongshu.VisibilityPublic && args.Visibility != xiaohongshu.VisibilityPrivate {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "修改可见范围失败: visibility 只能是 public 或 private"}}, IsError: true}
+	}
+	logrus.Infof("MCP: 修改可见范围 - Note ID: %s, visibility: %s", args.NoteID, args.Visibility)
+
+	res, err := s.xiaohongshuService.SetNoteVisibility(ctx, args.NoteID, args.Visibility)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "修改可见范围失败: " + errorText(err)}}, IsError: true}
+	}
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - Note ID: %s", res.Message, res.NoteID)}}}
+}
+
+// handleListDrafts 列出草稿箱
+func (s *AppServer) handleListDrafts(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 获取草稿列表")
+
+	result, err := s.xiaohongshuService.ListDrafts(ctx)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取草稿列表失败: " + errorText(err)}}, IsError: true}
+	}
+	return jsonResult("获取草稿列表成功", result)
+}
+
+// handlePublishDraft 提交草稿发布任务
+func (s *AppServer) handlePublishDraft(ctx context.Context, args PublishDraftArgs) *MCPToolResult {
+	if args.DraftID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "发布草稿失败: 缺少draft_id参数"}}, IsError: true}
+	}
+	logrus.Infof("MCP: 发布草稿 - Draft ID: %s, publish_at: %s", args.DraftID, args.PublishAt)
+
+	job, err := s.xiaohongshuService.EnqueuePublishDraft(ctx, &PublishDraftRequest{
+		DraftID:   args.DraftID,
+		Title:     args.Title,
+		Content:   args.Content,
+		Tags:      args.Tags,
+		PublishAt: args.PublishAt,
+	})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "发布草稿失败: " + errorText(err)}}, IsError: true}
+	}
+
+	resultText := fmt.Sprintf("草稿发布任务已提交，job_id: %s\n\n发布在后台执行，可使用 get_job_status 查询进度。", job.ID)
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: resultText}}}
+}
+
+// handleGetNoteAnalytics 获取笔记数据
+func (s *AppServer) handleGetNoteAnalytics(ctx context.Context, args NoteAnalyticsArgs) *MCPToolResult {
+	if args.NoteID != "" {
+		logrus.Infof("MCP: 获取笔记数据 - Note ID: %s", args.NoteID)
+		result, err := s.xiaohongshuService.GetNoteAnalytics(ctx, args.NoteID)
+		if err != nil {
+			return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取笔记数据失败: " + errorText(err)}}, IsError: true}
+		}
+		return jsonResult("获取笔记数据成功", result)
+	}
+
+	logrus.Info("MCP: 获取笔记数据列表")
+	result, err := s.xiaohongshuService.ListNoteAnalytics(ctx, xiaohongshu.PageOptions{Limit: args.Limit, Cursor: args.Cursor})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取笔记数据失败: " + errorText(err)}}, IsError: true}
+	}
+	return jsonResult("获取笔记数据成功", result)
+}
+
+// handleGetAccountAnalytics 获取账号每日趋势
+func (s *AppServer) handleGetAccountAnalytics(ctx context.Context, args AccountAnalyticsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取账号数据 - from: %s, to: %s", args.From, args.To)
+
+	result, err := s.xiaohongshuService.GetAccountAnalytics(ctx, args.From, args.To)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取账号数据失败: " + errorText(err)}}, IsError: true}
+	}
+	return jsonResult("获取账号数据成功", result)
+}
+
+// handleGetNotifications 读取通知列表
+func (s *AppServer) handleGetNotifications(ctx context.Context, args NotificationsArgs) *MCPToolResult {
+	if args.Kind == "" {
+		args.Kind = string(xiaohongshu.NotificationMentions)
+	}
+	kind, err := xiaohongshu.ParseNotificationKind(args.Kind)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取通知失败: " + err.Error()}}, IsError: true}
+	}
+	logrus.Infof("MCP: 获取通知 - kind: %s, only_new: %v, mark_seen: %v", kind, args.OnlyNew, args.MarkSeen)
+
+	result, err := s.xiaohongshuService.ListNotifications(ctx, NotificationQuery{
+		Kind:        kind,
+		PageOptions: xiaohongshu.PageOptions{Limit: args.Limit, Cursor: args.Cursor},
+		OnlyNew:     args.OnlyNew,
+		MarkSeen:    args.MarkSeen,
+	})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取通知失败: " + errorText(err)}}, IsError: true}
+	}
+	return jsonResult("获取通知成功", result)
+}
+
+// handleListConversations 列出私信会话
+func (s *AppServer) handleListConversations(ctx context.Context, args ListConversationsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取私信会话 - limit: %d", args.Limit)
+
+	result, err := s.xiaohongshuService.ListConversations(ctx, xiaohongshu.PageOptions{Limit: args.Limit, Cursor: args.Cursor})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取私信会话失败: " + errorText(err)}}, IsError: true}
+	}
+	return jsonResult("获取私信会话成功", result)
+}
+
+// handleGetMessages 读取与某个用户的私信
+func (s *AppServer) handleGetMessages(ctx context.Context, args GetMessagesArgs) *MCPToolResult {
+	if args.UserID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取私信失败: 缺少user_id参数"}}, IsError: true}
+	}
+	logrus.Infof("MCP: 获取私信 - User ID: %s, limit: %d", args.UserID, args.Limit)
+
+	result, err := s.xiaohongshuService.GetMessages(ctx, args.UserID, xiaohongshu.PageOptions{Limit: args.Limit, Cursor: args.Cursor})
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "获取私信失败: " + errorText(err)}}, IsError: true}
+	}
+	return jsonResult("获取私信成功", result)
+}
+
+// handleSendMessage 发送文字私信
+func (s *AppServer) handleSendMessage(ctx context.Context, args SendMessageArgs) *MCPToolResult {
+	if args.UserID == "" || args.Content == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "私信发送失败: 缺少user_id或content参数"}}, IsError: true}
+	}
+	if utf8.RuneCountInString(args.Content) > xiaohongshu.MaxMessageLength {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("私信发送失败: content 最多 %d 字", xiaohongshu.MaxMessageLength)}}, IsError: true}
+	}
+	logrus.Infof("MCP: 发送私信 - User ID: %s", args.UserID)
+
+	res, err := s.xiaohongshuService.SendMessage(ctx, args.UserID, args.Content)
+	if err != nil {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: "私信发送失败: " + errorText(err)}}, IsError: true}
+	}
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s - User ID: %s, Message ID: %s", res.Message, res.UserID, res.MessageID)}}}
+}
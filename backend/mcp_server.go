@@ -8,9 +8,14 @@ import (
 	"runtime/debug"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
 	"github.com/xpzouying/xiaohongshu-mcp/session"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type AccountArgs struct {
@@ -30,6 +35,15 @@ type PublishContentArgs struct {
 	Tags      []string `json:"tags,omitempty"`
 }
 
+type SchedulePublishContentArgs struct {
+	AccountID int      `json:"account_id,omitempty"`
+	Title     string   `json:"title"`
+	Content   string   `json:"content"`
+	Images    []string `json:"images"`
+	Tags      []string `json:"tags,omitempty"`
+	PublishAt string   `json:"publish_at,omitempty" jsonschema:"定时发布时间，RFC3339 或 2006-01-02 15:04（北京时间），需在 1 小时后到 14 天内；为空时默认当前时间+3天"`
+}
+
 type PublishVideoArgs struct {
 	AccountID int      `json:"account_id,omitempty"`
 	Title     string   `json:"title"`
@@ -38,10 +52,68 @@ type PublishVideoArgs struct {
 	Tags      []string `json:"tags,omitempty"`
 }
 
+type JobStatusArgs struct {
+	JobID string `json:"job_id"`
+}
+
+type SchedulePublishVideoArgs struct {
+	AccountID int      `json:"account_id,omitempty"`
+	Title     string   `json:"title"`
+	Content   string   `json:"content"`
+	Video     string   `json:"video"`
+	Tags      []string `json:"tags,omitempty"`
+	PublishAt string   `json:"publish_at,omitempty" jsonschema:"定时发布时间，RFC3339 或 2006-01-02 15:04（北京时间），需在 1 小时后到 14 天内；为空时默认当前时间+3天"`
+}
+
+type ListCalendarArgs struct {
+	AccountID int    `json:"account_id,omitempty" jsonschema:"只看该账号的条目，不传则列出全部账号"`
+	Status    string `json:"status,omitempty" jsonschema:"按状态筛选: pending|publishing|published|failed|cancelled"`
+	From      string `json:"from,omitempty" jsonschema:"发布时间下限，RFC3339 或 2006-01-02 15:04（北京时间）"`
+	To        string `json:"to,omitempty" jsonschema:"发布时间上限，格式同 from"`
+}
+
+type ActionHistoryArgs struct {
+	AccountID int    `json:"account_id,omitempty" jsonschema:"只看该账号的操作，不传则列出全部账号"`
+	Action    string `json:"action,omitempty" jsonschema:"按操作类型筛选: publish_content|save_draft_content|schedule_content|publish_video|save_draft_video|schedule_video|publish_draft|edit_note|delete_note|set_note_visibility|comment|reply|like_comment|unlike_comment|delete_comment|pin_comment|unpin_comment|like|unlike|favorite|unfavorite|follow|unfollow|send_message|delete_cookies"`
+	FeedID    string `json:"feed_id,omitempty" jsonschema:"只看针对该笔记的操作"`
+	Content   string `json:"content,omitempty" jsonschema:"按正文查找（比较 SHA-256），用于确认相同内容的评论或笔记是否已经发过"`
+	Outcome   string `json:"outcome,omitempty" jsonschema:"按结果筛选: success|failure"`
+	Since     string `json:"since,omitempty" jsonschema:"时间下限，RFC3339 或 2006-01-02 15:04（北京时间）"`
+	Until     string `json:"until,omitempty" jsonschema:"时间上限，格式同 since"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回条数，默认 100"`
+}
+
+type AddCalendarEntryArgs struct {
+	AccountID int      `json:"account_id,omitempty"`
+	Title     string   `json:"title"`
+	Content   string   `json:"content"`
+	Images    []string `json:"images,omitempty" jsonschema:"图文图片，与 video 二选一"`
+	Video     string   `json:"video,omitempty" jsonschema:"本地视频文件绝对路径，与 images 二选一"`
+	Tags      []string `json:"tags,omitempty"`
+	PublishAt string   `json:"publish_at" jsonschema:"发布时间，RFC3339 或 2006-01-02 15:04（北京时间），需晚于当前时间，不受平台 14 天限制"`
+}
+
+type MoveCalendarEntryArgs struct {
+	EntryID   string `json:"entry_id"`
+	PublishAt string `json:"publish_at" jsonschema:"新的发布时间，格式同 add_calendar_entry"`
+}
+
+type CalendarEntryArgs struct {
+	EntryID string `json:"entry_id"`
+}
+
 type SearchFeedsArgs struct {
 	AccountID int          `json:"account_id,omitempty"`
 	Keyword   string       `json:"keyword"`
 	Filters   FilterOption `json:"filters,omitempty"`
+	Limit     int          `json:"limit,omitempty" jsonschema:"最多返回条数（上限 100），会滚动页面加载更多；不传且无 cursor 时只返回首屏结果"`
+	Cursor    string       `json:"cursor,omitempty" jsonschema:"上一页返回的 next_cursor，关键词与筛选条件需保持不变"`
+}
+
+type ListFeedsArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回条数（上限 100），会滚动页面加载更多；不传且无 cursor 时只返回首屏推荐"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"上一页返回的 next_cursor，用于获取后续内容"`
 }
 
 type FilterOption struct {
@@ -67,6 +139,8 @@ type UserProfileArgs struct {
 	AccountID int    `json:"account_id,omitempty"`
 	UserID    string `json:"user_id"`
 	XsecToken string `json:"xsec_token"`
+	MaxNotes  int    `json:"max_notes,omitempty" jsonschema:"滚动主页抓取的笔记上限（最多 500），与 since 都不传时只返回首屏笔记"`
+	Since     string `json:"since,omitempty" jsonschema:"只返回该时间之后发布的笔记，RFC3339 或 2006-01-02 15:04（北京时间）"`
 }
 
 type PostCommentArgs struct {
@@ -85,6 +159,31 @@ type ReplyCommentArgs struct {
 	Content   string `json:"content"`
 }
 
+type CommentActionArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	FeedID    string `json:"feed_id"`
+	XsecToken string `json:"xsec_token"`
+	CommentID string `json:"comment_id,omitempty" jsonschema:"评论ID，与 user_id 至少提供一个"`
+	UserID    string `json:"user_id,omitempty" jsonschema:"评论者的用户ID，匹配该用户在笔记下的第一条评论"`
+}
+
+type LikeCommentArgs struct {
+	CommentActionArgs
+	Unlike bool `json:"unlike,omitempty" jsonschema:"为 true 时取消点赞"`
+}
+
+type DeleteCommentArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	FeedID    string `json:"feed_id"`
+	XsecToken string `json:"xsec_token"`
+	CommentID string `json:"comment_id" jsonschema:"要删除的评论或回复ID"`
+}
+
+type PinCommentArgs struct {
+	CommentActionArgs
+	Unpin bool `json:"unpin,omitempty" jsonschema:"为 true 时取消置顶"`
+}
+
 type LikeFeedArgs struct {
 	AccountID int    `json:"account_id,omitempty"`
 	FeedID    string `json:"feed_id"`
@@ -92,6 +191,97 @@ type LikeFeedArgs struct {
 	Unlike    bool   `json:"unlike,omitempty"`
 }
 
+type FollowUserArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	UserID    string `json:"user_id" jsonschema:"目标用户ID"`
+	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，取自笔记或用户列表中的 xsecToken"`
+}
+
+type ListFollowsArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回人数（上限 100），会滚动列表加载更多；不传且无 cursor 时只返回首屏"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"上一页返回的 next_cursor，用于获取后续内容"`
+}
+
+type ListMyNotesArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回篇数（上限 100），会滚动笔记管理页加载更多；不传且无 cursor 时只返回首屏"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"上一页返回的 next_cursor，用于获取后续内容"`
+}
+
+type EditMyNoteArgs struct {
+	AccountID int      `json:"account_id,omitempty"`
+	NoteID    string   `json:"note_id" jsonschema:"已发布笔记的ID"`
+	Title     string   `json:"title,omitempty" jsonschema:"新标题，不传则不修改"`
+	Content   string   `json:"content,omitempty" jsonschema:"新正文，不传则不修改"`
+	Tags      []string `json:"tags,omitempty" jsonschema:"话题标签，替换正文中原有的标签，不传则不修改"`
+}
+
+type ListDraftsArgs struct {
+	AccountID int `json:"account_id,omitempty"`
+}
+
+type PublishDraftArgs struct {
+	AccountID int      `json:"account_id,omitempty"`
+	DraftID   string   `json:"draft_id" jsonschema:"list_drafts 返回的草稿ID；带 -2 等后缀的 ID 只表示相同草稿中的位置，前面的草稿发布或删除后会指向另一篇，请在发布前重新获取"`
+	Title     string   `json:"title,omitempty" jsonschema:"新标题，不传则使用草稿原标题"`
+	Content   string   `json:"content,omitempty" jsonschema:"新正文，不传则使用草稿原正文"`
+	Tags      []string `json:"tags,omitempty" jsonschema:"话题标签，替换正文中原有的标签，不传则不修改"`
+	PublishAt string   `json:"publish_at,omitempty" jsonschema:"定时发布时间，RFC3339 或 2006-01-02 15:04（北京时间），需在 1 小时后到 14 天内；为空表示立即发布"`
+}
+
+type NoteAnalyticsArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	NoteID    string `json:"note_id,omitempty" jsonschema:"只获取该笔记的数据，不传则分页列出全部笔记"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回篇数（上限 100），会翻页加载更多；不传且无 cursor 时只返回第一页"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"上一页返回的 next_cursor，用于获取后续内容"`
+}
+
+type AccountAnalyticsArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	From      string `json:"from,omitempty" jsonschema:"开始日期 YYYY-MM-DD（北京时间），最早为 30 天前"`
+	To        string `json:"to,omitempty" jsonschema:"结束日期 YYYY-MM-DD（北京时间），最晚为昨天；from 与 to 都不传时为最近 7 天"`
+}
+
+type NotificationsArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	Kind      string `json:"kind,omitempty" jsonschema:"通知类型: mentions 评论和@（默认）| likes 赞和收藏 | follows 新增关注"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回条数（上限 100），会滚动加载更多；不传且无 cursor 时只返回首屏，only_new 或 mark_seen 时默认 20"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"上一页返回的 next_cursor，kind 需保持不变"`
+	OnlyNew   bool   `json:"only_new,omitempty" jsonschema:"只返回上次标记已读之后的新通知"`
+	MarkSeen  bool   `json:"mark_seen,omitempty" jsonschema:"本次已返回全部通知（没有 next_cursor）时，把已读位置推进到最新一条"`
+}
+
+type ListConversationsArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回会话数（上限 100），会滚动加载更多；不传且无 cursor 时只返回首屏"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"上一页返回的 next_cursor"`
+}
+
+type GetMessagesArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	UserID    string `json:"user_id" jsonschema:"对方用户ID，从 list_conversations 结果中获取"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"最多返回消息数（上限 100），从新到旧，会向上滚动加载更早的消息"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"上一页返回的 next_cursor，user_id 需保持不变"`
+}
+
+type SendMessageArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	UserID    string `json:"user_id" jsonschema:"对方用户ID，从 list_conversations 结果中获取；只能回复已有会话"`
+	Content   string `json:"content" jsonschema:"文字内容，最多 500 字"`
+}
+
+type MyNoteArgs struct {
+	AccountID int    `json:"account_id,omitempty"`
+	NoteID    string `json:"note_id" jsonschema:"已发布笔记的ID"`
+}
+
+type NoteVisibilityArgs struct {
+	AccountID  int    `json:"account_id,omitempty"`
+	NoteID     string `json:"note_id" jsonschema:"已发布笔记的ID"`
+	Visibility string `json:"visibility" jsonschema:"public 公开可见，private 仅自己可见"`
+}
+
 type FavoriteFeedArgs struct {
 	AccountID  int    `json:"account_id,omitempty"`
 	FeedID     string `json:"feed_id"`
@@ -104,6 +294,9 @@ func ensureAccountCtx(ctx context.Context, app *AppServer, accountID int) (conte
 	if id == 0 {
 		id = 1
 	}
+	if err := apikey.CheckAccount(ctx, id); err != nil {
+		return ctx, nil, err
+	}
 	acc, err := app.accounts.Get(id)
 	if err != nil && id == 1 {
 		acc, err = app.accounts.Create("", "")
@@ -116,6 +309,11 @@ func ensureAccountCtx(ctx context.Context, app *AppServer, accountID int) (conte
 }
 
 func ensureAccountForLogin(ctx context.Context, app *AppServer, accountID int, proxy *string) (context.Context, *accounts.Account, error) {
+	// Only keys without an account restriction may create accounts (account_id 0).
+	if err := apikey.CheckAccount(ctx, accountID); err != nil {
+		return ctx, nil, err
+	}
+
 	// If account_id is 0, create a brand new account (with proxy if provided).
 	if accountID == 0 {
 		proxyVal := ""
@@ -164,6 +362,7 @@ func InitMCPServer(appServer *AppServer) *mcp.Server {
 		nil,
 	)
 
+	server.AddReceivingMiddleware(appServer.mcpAuthMiddleware)
 	registerTools(server, appServer)
 
 	logrus.Info("MCP Server initialized with official SDK")
@@ -177,6 +376,19 @@ func withPanicRecovery[T any](
 ) func(context.Context, *mcp.CallToolRequest, T) (*mcp.CallToolResult, any, error) {
 
 	return func(ctx context.Context, req *mcp.CallToolRequest, args T) (result *mcp.CallToolResult, resp any, err error) {
+		// 每次工具调用一个 span，请求头带有 traceparent 时接在调用方的链路下
+		if req != nil && req.Extra != nil {
+			ctx = tracing.Extract(ctx, req.Extra.Header)
+		}
+		ctx, span := tracing.Start(ctx, "tools/call "+toolName, attribute.String("mcp.tool.name", toolName))
+		defer func() {
+			spanErr := err
+			if spanErr == nil && result != nil && result.IsError {
+				spanErr = toolResultError(result)
+			}
+			tracing.End(span, &spanErr)
+		}()
+
 		defer func() {
 			if r := recover(); r != nil {
 				logrus.WithFields(logrus.Fields{
@@ -203,6 +415,16 @@ func withPanicRecovery[T any](
 	}
 }
 
+// toolResultError 把工具返回的错误结果转为 error，用于记录到 span
+func toolResultError(result *mcp.CallToolResult) error {
+	for _, c := range result.Content {
+		if t, ok := c.(*mcp.TextContent); ok {
+			return errors.New(t.Text)
+		}
+	}
+	return errors.New("tool returned an error result")
+}
+
 func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
@@ -210,7 +432,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 			Description: "列出已创建的账号及其登录状态、代理、指纹",
 		},
 		withPanicRecovery("list_accounts", func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
-			accounts := appServer.accounts.List()
+			accounts := visibleAccounts(ctx, appServer.accounts.List())
 			data, _ := json.MarshalIndent(accounts, "", "  ")
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{&mcp.TextContent{Text: string(data)}},
@@ -267,7 +489,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "publish_content",
-			Description: "发布小红书图文内容",
+			Description: "发布小红书图文内容（后台异步执行，返回 job_id）",
 		},
 		withPanicRecovery("publish_content", func(ctx context.Context, req *mcp.CallToolRequest, args PublishContentArgs) (*mcp.CallToolResult, any, error) {
 			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
@@ -309,18 +531,19 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "schedule_publish_content",
-			Description: "定时发布小红书图文内容（自动选择当前时间+3天）",
+			Description: "定时发布小红书图文内容（publish_at 指定发布时间，需在 1 小时后到 14 天内；不指定时为当前时间+3天）",
 		},
-		withPanicRecovery("schedule_publish_content", func(ctx context.Context, req *mcp.CallToolRequest, args PublishContentArgs) (*mcp.CallToolResult, any, error) {
+		withPanicRecovery("schedule_publish_content", func(ctx context.Context, req *mcp.CallToolRequest, args SchedulePublishContentArgs) (*mcp.CallToolResult, any, error) {
 			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
 			if err != nil {
 				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
 			}
 			argsMap := map[string]interface{}{
-				"title":   args.Title,
-				"content": args.Content,
-				"images":  convertStringsToInterfaces(args.Images),
-				"tags":    convertStringsToInterfaces(args.Tags),
+				"title":      args.Title,
+				"content":    args.Content,
+				"images":     convertStringsToInterfaces(args.Images),
+				"tags":       convertStringsToInterfaces(args.Tags),
+				"publish_at": args.PublishAt,
 			}
 			result := appServer.handlePublishContentScheduled(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -330,14 +553,14 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "list_feeds",
-			Description: "获取首页 Feeds 列表",
+			Description: "获取首页 Feeds 列表，可通过 limit 与 next_cursor 翻页",
 		},
-		withPanicRecovery("list_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+		withPanicRecovery("list_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args ListFeedsArgs) (*mcp.CallToolResult, any, error) {
 			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
 			if err != nil {
 				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
 			}
-			result := appServer.handleListFeeds(ctx)
+			result := appServer.handleListFeeds(ctx, args)
 			return convertToMCPResult(result), nil, nil
 		}),
 	)
@@ -345,7 +568,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "search_feeds",
-			Description: "搜索小红书内容（需要已登录）",
+			Description: "搜索小红书内容（需要已登录），可通过 limit 与 next_cursor 翻页",
 		},
 		withPanicRecovery("search_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args SearchFeedsArgs) (*mcp.CallToolResult, any, error) {
 			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
@@ -400,7 +623,7 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "user_profile",
-			Description: "获取指定小红书用户主页，返回用户信息及笔记内容",
+			Description: "获取指定小红书用户主页，返回用户信息及笔记内容；传 max_notes 或 since 时滚动主页按顺序抓取更多笔记（含 xsec_token）",
 		},
 		withPanicRecovery("user_profile", func(ctx context.Context, req *mcp.CallToolRequest, args UserProfileArgs) (*mcp.CallToolResult, any, error) {
 			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
@@ -410,6 +633,8 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 			argsMap := map[string]interface{}{
 				"user_id":    args.UserID,
 				"xsec_token": args.XsecToken,
+				"max_notes":  args.MaxNotes,
+				"since":      args.Since,
 			}
 			result := appServer.handleUserProfile(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -465,10 +690,55 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		}),
 	)
 
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "like_comment",
+			Description: "点赞或取消点赞小红书笔记下的指定评论",
+		},
+		withPanicRecovery("like_comment", func(ctx context.Context, req *mcp.CallToolRequest, args LikeCommentArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleLikeComment(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_comment",
+			Description: "删除自己的评论或回复，或自己笔记下他人的评论（需要 comment_id）",
+		},
+		withPanicRecovery("delete_comment", func(ctx context.Context, req *mcp.CallToolRequest, args DeleteCommentArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleDeleteComment(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "pin_comment",
+			Description: "在自己的笔记下置顶或取消置顶评论",
+		},
+		withPanicRecovery("pin_comment", func(ctx context.Context, req *mcp.CallToolRequest, args PinCommentArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handlePinComment(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "publish_with_video",
-			Description: "发布小红书视频内容（仅支持本地单个视频文件）",
+			Description: "发布小红书视频内容（仅支持本地单个视频文件，后台异步执行，返回 job_id）",
 		},
 		withPanicRecovery("publish_with_video", func(ctx context.Context, req *mcp.CallToolRequest, args PublishVideoArgs) (*mcp.CallToolResult, any, error) {
 			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
@@ -510,18 +780,19 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "schedule_publish_video",
-			Description: "定时发布小红书视频内容（自动选择当前时间+3天）",
+			Description: "定时发布小红书视频内容（publish_at 指定发布时间，需在 1 小时后到 14 天内；不指定时为当前时间+3天）",
 		},
-		withPanicRecovery("schedule_publish_video", func(ctx context.Context, req *mcp.CallToolRequest, args PublishVideoArgs) (*mcp.CallToolResult, any, error) {
+		withPanicRecovery("schedule_publish_video", func(ctx context.Context, req *mcp.CallToolRequest, args SchedulePublishVideoArgs) (*mcp.CallToolResult, any, error) {
 			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
 			if err != nil {
 				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
 			}
 			argsMap := map[string]interface{}{
-				"title":   args.Title,
-				"content": args.Content,
-				"video":   args.Video,
-				"tags":    convertStringsToInterfaces(args.Tags),
+				"title":      args.Title,
+				"content":    args.Content,
+				"video":      args.Video,
+				"tags":       convertStringsToInterfaces(args.Tags),
+				"publish_at": args.PublishAt,
 			}
 			result := appServer.handlePublishVideoScheduled(ctx, argsMap)
 			return convertToMCPResult(result), nil, nil
@@ -568,7 +839,352 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 		}),
 	)
 
-	logrus.Infof("Registered %d MCP tools", 18)
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "follow_user",
+			Description: "关注指定小红书用户，已关注时不重复操作",
+		},
+		withPanicRecovery("follow_user", func(ctx context.Context, req *mcp.CallToolRequest, args FollowUserArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleFollowUser(ctx, args, false)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "unfollow_user",
+			Description: "取消关注指定小红书用户，未关注时不做操作",
+		},
+		withPanicRecovery("unfollow_user", func(ctx context.Context, req *mcp.CallToolRequest, args FollowUserArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleFollowUser(ctx, args, true)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_following",
+			Description: "获取当前账号关注的用户列表，可通过 limit 与 next_cursor 翻页",
+		},
+		withPanicRecovery("list_following", func(ctx context.Context, req *mcp.CallToolRequest, args ListFollowsArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleListFollows(ctx, xiaohongshu.FollowListFollowing, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_followers",
+			Description: "获取当前账号的粉丝列表，可通过 limit 与 next_cursor 翻页",
+		},
+		withPanicRecovery("list_followers", func(ctx context.Context, req *mcp.CallToolRequest, args ListFollowsArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleListFollows(ctx, xiaohongshu.FollowListFollowers, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_my_notes",
+			Description: "从创作者中心列出当前账号已发布的笔记（审核状态、可见范围、浏览/点赞等数据），可通过 limit 与 next_cursor 翻页",
+		},
+		withPanicRecovery("list_my_notes", func(ctx context.Context, req *mcp.CallToolRequest, args ListMyNotesArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleListMyNotes(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "edit_my_note",
+			Description: "修改已发布笔记的标题、正文或标签，修改后笔记会重新审核",
+		},
+		withPanicRecovery("edit_my_note", func(ctx context.Context, req *mcp.CallToolRequest, args EditMyNoteArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleEditMyNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "delete_my_note",
+			Description: "删除当前账号已发布的笔记，删除后无法恢复",
+		},
+		withPanicRecovery("delete_my_note", func(ctx context.Context, req *mcp.CallToolRequest, args MyNoteArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleDeleteMyNote(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "set_note_visibility",
+			Description: "修改已发布笔记的可见范围（公开可见或仅自己可见）",
+		},
+		withPanicRecovery("set_note_visibility", func(ctx context.Context, req *mcp.CallToolRequest, args NoteVisibilityArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleSetNoteVisibility(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_drafts",
+			Description: "列出创作者中心草稿箱中的草稿（图文与视频），返回 draft_id 供 publish_draft 使用",
+		},
+		withPanicRecovery("list_drafts", func(ctx context.Context, req *mcp.CallToolRequest, args ListDraftsArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleListDrafts(ctx)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "publish_draft",
+			Description: "发布草稿箱中的草稿，可先修改标题、正文或标签，publish_at 非空时定时发布（后台异步执行，返回 job_id）",
+		},
+		withPanicRecovery("publish_draft", func(ctx context.Context, req *mcp.CallToolRequest, args PublishDraftArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handlePublishDraft(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_note_analytics",
+			Description: "从创作者中心数据中心获取笔记的累计数据（曝光、观看、点赞、收藏、评论、分享、涨粉），可指定 note_id 或通过 limit 与 next_cursor 翻页",
+		},
+		withPanicRecovery("get_note_analytics", func(ctx context.Context, req *mcp.CallToolRequest, args NoteAnalyticsArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleGetNoteAnalytics(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_account_analytics",
+			Description: "从创作者中心数据中心获取账号在日期范围内的每日趋势与汇总（曝光、观看、互动、涨粉），最多最近 30 天",
+		},
+		withPanicRecovery("get_account_analytics", func(ctx context.Context, req *mcp.CallToolRequest, args AccountAnalyticsArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleGetAccountAnalytics(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_notifications",
+			Description: "读取通知（评论和@、赞和收藏、新增关注），评论通知包含回复所需的 feed_id、xsec_token、comment_id、user_id；配合 only_new 与 mark_seen 轮询新通知",
+		},
+		withPanicRecovery("get_notifications", func(ctx context.Context, req *mcp.CallToolRequest, args NotificationsArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleGetNotifications(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_conversations",
+			Description: "列出私信会话（最近的在前），包含对方用户ID、昵称、最后一条消息和未读数",
+		},
+		withPanicRecovery("list_conversations", func(ctx context.Context, req *mcp.CallToolRequest, args ListConversationsArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleListConversations(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_messages",
+			Description: "读取与某个用户的私信（从新到旧），支持 limit/cursor 分页加载更早的消息",
+		},
+		withPanicRecovery("get_messages", func(ctx context.Context, req *mcp.CallToolRequest, args GetMessagesArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleGetMessages(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "send_message",
+			Description: "在已有私信会话中给对方发送一条文字消息，按账号限频（message 额度）",
+		},
+		withPanicRecovery("send_message", func(ctx context.Context, req *mcp.CallToolRequest, args SendMessageArgs) (*mcp.CallToolResult, any, error) {
+			ctx, _, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			result := appServer.handleSendMessage(ctx, args)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_job_status",
+			Description: "查询发布任务的执行状态（publish_content / publish_with_video / publish_draft 返回的 job_id）",
+		},
+		withPanicRecovery("get_job_status", func(ctx context.Context, req *mcp.CallToolRequest, args JobStatusArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"job_id": args.JobID,
+			}
+			result := appServer.handleGetJobStatus(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "list_calendar",
+			Description: "列出内容日历中的条目（按发布时间升序）",
+		},
+		withPanicRecovery("list_calendar", func(ctx context.Context, req *mcp.CallToolRequest, args ListCalendarArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"account_id": args.AccountID,
+				"status":     args.Status,
+				"from":       args.From,
+				"to":         args.To,
+			}
+			result := appServer.handleListCalendar(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "add_calendar_entry",
+			Description: "向内容日历添加一条图文或视频，到点后由服务端自动发布",
+		},
+		withPanicRecovery("add_calendar_entry", func(ctx context.Context, req *mcp.CallToolRequest, args AddCalendarEntryArgs) (*mcp.CallToolResult, any, error) {
+			ctx, acc, err := ensureAccountCtx(ctx, appServer, args.AccountID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}, nil, nil
+			}
+			argsMap := map[string]interface{}{
+				"account_id": acc.ID,
+				"title":      args.Title,
+				"content":    args.Content,
+				"images":     convertStringsToInterfaces(args.Images),
+				"video":      args.Video,
+				"tags":       convertStringsToInterfaces(args.Tags),
+				"publish_at": args.PublishAt,
+			}
+			result := appServer.handleAddCalendarEntry(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "move_calendar_entry",
+			Description: "调整内容日历中待发布条目的发布时间",
+		},
+		withPanicRecovery("move_calendar_entry", func(ctx context.Context, req *mcp.CallToolRequest, args MoveCalendarEntryArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"entry_id":   args.EntryID,
+				"publish_at": args.PublishAt,
+			}
+			result := appServer.handleMoveCalendarEntry(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "cancel_calendar_entry",
+			Description: "取消内容日历中待发布的条目",
+		},
+		withPanicRecovery("cancel_calendar_entry", func(ctx context.Context, req *mcp.CallToolRequest, args CalendarEntryArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"entry_id": args.EntryID,
+			}
+			result := appServer.handleCancelCalendarEntry(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	mcp.AddTool(server,
+		&mcp.Tool{
+			Name:        "get_action_history",
+			Description: "查询账号的操作记录（发布、评论、回复、点赞、收藏等，最新的在前），评论或发布前可先查询避免重复",
+		},
+		withPanicRecovery("get_action_history", func(ctx context.Context, req *mcp.CallToolRequest, args ActionHistoryArgs) (*mcp.CallToolResult, any, error) {
+			argsMap := map[string]interface{}{
+				"account_id": args.AccountID,
+				"action":     args.Action,
+				"feed_id":    args.FeedID,
+				"content":    args.Content,
+				"outcome":    args.Outcome,
+				"since":      args.Since,
+				"until":      args.Until,
+				"limit":      args.Limit,
+			}
+			result := appServer.handleGetActionHistory(ctx, argsMap)
+			return convertToMCPResult(result), nil, nil
+		}),
+	)
+
+	logrus.Infof("Registered %d MCP tools", 43)
 }
 
 // convertToMCPResult 将自定义的 MCPToolResult 转换为官方 SDK 的格式
@@ -0,0 +1,156 @@
+// Package metrics 以 Prometheus 格式导出运行指标：各操作按账号与结果的次数和耗时、浏览器启动耗时、
+// 常驻浏览器数量、代理桥接的连接数与错误数，以及各账号的登录状态。
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/proxybridge"
+)
+
+const namespace = "xhs"
+
+// 操作结果，与审计记录的 outcome 一致
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+var (
+	actionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "actions_total",
+		Help:      "Browser actions by action, account and outcome.",
+	}, []string{"action", "account", "outcome"})
+
+	actionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "action_duration_seconds",
+		Help:      "Duration of browser actions, including time spent waiting for the account.",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"action", "account", "outcome"})
+
+	browserLaunchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "browser_launch_duration_seconds",
+		Help:      "Time to launch a browser process.",
+		Buckets:   []float64{0.25, 0.5, 1, 2, 5, 10, 20, 30},
+	}, []string{"outcome"})
+)
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeFailure
+	}
+	return OutcomeSuccess
+}
+
+// ObserveAction 记录一次操作，err 非空时计为失败
+func ObserveAction(action, account string, err error, d time.Duration) {
+	o := outcome(err)
+	actionsTotal.WithLabelValues(action, account, o).Inc()
+	actionDuration.WithLabelValues(action, account, o).Observe(d.Seconds())
+}
+
+// ObserveBrowserLaunch 记录一次浏览器启动
+func ObserveBrowserLaunch(err error, d time.Duration) {
+	browserLaunchDuration.WithLabelValues(outcome(err)).Observe(d.Seconds())
+}
+
+// AccountStatus 账号的登录状态
+type AccountStatus struct {
+	Key      string
+	LoggedIn bool
+}
+
+// State 抓取时读取的实时状态，字段为 nil 时不导出对应指标
+type State struct {
+	LiveBrowsers func() int
+	Pool         func() browser.PoolStats
+	Accounts     func() []AccountStatus
+}
+
+// Handler 返回 /metrics 的处理器，包含操作指标、state 中的实时状态、代理桥接统计以及 Go 运行时指标
+func Handler(state State) http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		actionsTotal,
+		actionDuration,
+		browserLaunchDuration,
+		&stateCollector{state: state},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
+
+var (
+	liveBrowsersDesc = prometheus.NewDesc(namespace+"_live_browsers",
+		"Visible browser windows opened for accounts.", nil, nil)
+	poolBrowsersDesc = prometheus.NewDesc(namespace+"_pool_browsers",
+		"Pooled browsers by state.", []string{"state"}, nil)
+	poolLaunchesDesc = prometheus.NewDesc(namespace+"_pool_launches_total",
+		"Browsers launched by the pool.", nil, nil)
+	poolReusesDesc = prometheus.NewDesc(namespace+"_pool_reuses_total",
+		"Pooled browser reuses.", nil, nil)
+	poolEvictionsDesc = prometheus.NewDesc(namespace+"_pool_evictions_total",
+		"Pooled browsers closed by idle timeout, capacity or eviction.", nil, nil)
+	loggedInDesc = prometheus.NewDesc(namespace+"_account_logged_in",
+		"Whether the account is logged in (1) or not (0), as of the last login check.", []string{"account"}, nil)
+	bridgesDesc = prometheus.NewDesc(namespace+"_proxy_bridges",
+		"Running SOCKS5 proxy bridges.", nil, nil)
+	bridgeConnsDesc = prometheus.NewDesc(namespace+"_proxy_bridge_connections_total",
+		"Connections accepted by proxy bridges by request kind.", []string{"kind"}, nil)
+	bridgeErrorsDesc = prometheus.NewDesc(namespace+"_proxy_bridge_errors_total",
+		"Proxy bridge failures by stage.", []string{"stage"}, nil)
+)
+
+// stateCollector 抓取时读取实时状态
+type stateCollector struct {
+	state State
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		liveBrowsersDesc, poolBrowsersDesc, poolLaunchesDesc, poolReusesDesc, poolEvictionsDesc,
+		loggedInDesc, bridgesDesc, bridgeConnsDesc, bridgeErrorsDesc,
+	} {
+		ch <- d
+	}
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.state.LiveBrowsers != nil {
+		ch <- prometheus.MustNewConstMetric(liveBrowsersDesc, prometheus.GaugeValue, float64(c.state.LiveBrowsers()))
+	}
+	if c.state.Pool != nil {
+		st := c.state.Pool()
+		ch <- prometheus.MustNewConstMetric(poolBrowsersDesc, prometheus.GaugeValue, float64(st.InUse), "in_use")
+		ch <- prometheus.MustNewConstMetric(poolBrowsersDesc, prometheus.GaugeValue, float64(st.Idle), "idle")
+		ch <- prometheus.MustNewConstMetric(poolLaunchesDesc, prometheus.CounterValue, float64(st.Launches))
+		ch <- prometheus.MustNewConstMetric(poolReusesDesc, prometheus.CounterValue, float64(st.Reuses))
+		ch <- prometheus.MustNewConstMetric(poolEvictionsDesc, prometheus.CounterValue, float64(st.Evictions))
+	}
+	if c.state.Accounts != nil {
+		for _, a := range c.state.Accounts() {
+			v := 0.0
+			if a.LoggedIn {
+				v = 1
+			}
+			ch <- prometheus.MustNewConstMetric(loggedInDesc, prometheus.GaugeValue, v, a.Key)
+		}
+	}
+
+	bs := proxybridge.CurrentStats()
+	ch <- prometheus.MustNewConstMetric(bridgesDesc, prometheus.GaugeValue, float64(bs.Bridges))
+	ch <- prometheus.MustNewConstMetric(bridgeConnsDesc, prometheus.CounterValue, float64(bs.ConnectTunnels), "connect")
+	ch <- prometheus.MustNewConstMetric(bridgeConnsDesc, prometheus.CounterValue, float64(bs.HTTPRequests), "http")
+	ch <- prometheus.MustNewConstMetric(bridgeErrorsDesc, prometheus.CounterValue, float64(bs.DialErrors), "dial")
+	ch <- prometheus.MustNewConstMetric(bridgeErrorsDesc, prometheus.CounterValue, float64(bs.HijackErrors), "hijack")
+	ch <- prometheus.MustNewConstMetric(bridgeErrorsDesc, prometheus.CounterValue, float64(bs.RoundTripErrors), "round_trip")
+}
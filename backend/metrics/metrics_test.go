@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+)
+
+func TestHandler(t *testing.T) {
+	ObserveAction("search_feeds", "acc_test", nil, 3*time.Second)
+	ObserveAction("search_feeds", "acc_test", errors.New("timeout"), 30*time.Second)
+	ObserveBrowserLaunch(nil, 2*time.Second)
+
+	h := Handler(State{
+		LiveBrowsers: func() int { return 2 },
+		Pool:         func() browser.PoolStats { return browser.PoolStats{InUse: 1, Idle: 3, Launches: 7} },
+		Accounts: func() []AccountStatus {
+			return []AccountStatus{{Key: "acc_test", LoggedIn: true}, {Key: "acc_new"}}
+		},
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+	out := string(body)
+
+	for _, line := range []string{
+		`xhs_actions_total{account="acc_test",action="search_feeds",outcome="success"} 1`,
+		`xhs_actions_total{account="acc_test",action="search_feeds",outcome="failure"} 1`,
+		`xhs_action_duration_seconds_bucket{account="acc_test",action="search_feeds",outcome="success",le="5"} 1`,
+		`xhs_browser_launch_duration_seconds_count{outcome="success"} 1`,
+		`xhs_live_browsers 2`,
+		`xhs_pool_browsers{state="idle"} 3`,
+		`xhs_pool_launches_total 7`,
+		`xhs_account_logged_in{account="acc_test"} 1`,
+		`xhs_account_logged_in{account="acc_new"} 0`,
+		`xhs_proxy_bridge_errors_total{stage="dial"} 0`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, out, line)
+	}
+
+	// 未提供的实时状态不导出
+	w = httptest.NewRecorder()
+	Handler(State{}).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ = io.ReadAll(w.Body)
+	assert.NotContains(t, string(body), "xhs_live_browsers")
+	assert.Contains(t, string(body), "xhs_proxy_bridges 0")
+}
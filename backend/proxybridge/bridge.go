@@ -9,11 +9,40 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/proxy"
 )
 
+// Stats counts activity of all bridges in the process since start.
+type Stats struct {
+	Bridges         int64  // bridges currently running
+	ConnectTunnels  uint64 // CONNECT tunnels established
+	HTTPRequests    uint64 // plain HTTP requests forwarded
+	DialErrors      uint64 // CONNECT targets that could not be dialed through the SOCKS5 proxy
+	HijackErrors    uint64 // CONNECT requests whose client connection could not be taken over
+	RoundTripErrors uint64 // plain HTTP requests that failed upstream
+}
+
+var stats struct {
+	bridges                               atomic.Int64
+	tunnels, requests                     atomic.Uint64
+	dialErrors, hijackErrors, roundErrors atomic.Uint64
+}
+
+// CurrentStats returns the bridge counters.
+func CurrentStats() Stats {
+	return Stats{
+		Bridges:         stats.bridges.Load(),
+		ConnectTunnels:  stats.tunnels.Load(),
+		HTTPRequests:    stats.requests.Load(),
+		DialErrors:      stats.dialErrors.Load(),
+		HijackErrors:    stats.hijackErrors.Load(),
+		RoundTripErrors: stats.roundErrors.Load(),
+	}
+}
+
 // StartSocksBridge starts a lightweight HTTP CONNECT proxy that forwards via the given socks5 URL.
 // Returns local HTTP proxy URL and a stop function.
 func StartSocksBridge(rawurl string) (string, func(), error) {
@@ -68,9 +97,12 @@ func StartSocksBridge(rawurl string) (string, func(), error) {
 			defer cancel()
 			_ = server.Shutdown(ctx)
 			_ = ln.Close()
+			stats.bridges.Add(-1)
 		})
 	}
 
+	stats.bridges.Add(1)
+
 	go func() { _ = server.Serve(ln) }()
 
 	localURL := fmt.Sprintf("http://%s", ln.Addr().String())
@@ -97,6 +129,7 @@ func (h *bridgeHandler) handleConnect(w http.ResponseWriter, r *http.Request) {
 	}
 	clientConn, _, err := hj.Hijack()
 	if err != nil {
+		stats.hijackErrors.Add(1)
 		return
 	}
 	defer func() {
@@ -109,8 +142,10 @@ func (h *bridgeHandler) handleConnect(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 	targetConn, err := h.dial(ctx, "tcp", r.Host)
 	if err != nil {
+		stats.dialErrors.Add(1)
 		return
 	}
+	stats.tunnels.Add(1)
 
 	_, _ = clientConn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
 
@@ -132,8 +167,10 @@ func (h *bridgeHandler) handleHTTP(w http.ResponseWriter, r *http.Request) {
 		r.URL.Host = r.Host
 	}
 	r.RequestURI = ""
+	stats.requests.Add(1)
 	resp, err := transport.RoundTrip(r)
 	if err != nil {
+		stats.roundErrors.Add(1)
 		http.Error(w, err.Error(), http.StatusBadGateway)
 		return
 	}
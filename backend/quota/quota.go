@@ -0,0 +1,363 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Action 受频率限制的互动类型
+type Action string
+
+const (
+	ActionLike     Action = "like"
+	ActionFavorite Action = "favorite"
+	ActionComment  Action = "comment"
+	ActionReply    Action = "reply"
+	ActionFollow   Action = "follow"
+	ActionMessage  Action = "message"
+)
+
+// ErrQuotaExceeded 操作频率或每日额度超限
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// ExceededError 超限详情，RetryAfter 为最早可重试的等待时间
+type ExceededError struct {
+	Action     Action
+	Rule       string
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded for %s (%s), retry after %s", e.Action, e.Rule, e.RetryAfter.Round(time.Second))
+}
+
+// Is 使 errors.Is(err, ErrQuotaExceeded) 成立
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// Policy 单个互动类型的限制，0 表示不限制
+type Policy struct {
+	PerMinute int `json:"per_minute"`
+	PerHour   int `json:"per_hour"`
+	PerDay    int `json:"per_day"`
+	// MinGap 两次操作之间的最小间隔，实际间隔再随机增加 0~Jitter
+	MinGap time.Duration `json:"-"`
+	Jitter time.Duration `json:"-"`
+}
+
+type policyJSON struct {
+	PerMinute int    `json:"per_minute"`
+	PerHour   int    `json:"per_hour"`
+	PerDay    int    `json:"per_day"`
+	MinGap    string `json:"min_gap,omitempty"`
+	Jitter    string `json:"jitter,omitempty"`
+}
+
+// MarshalJSON 间隔以 "30s" 形式输出
+func (p Policy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(policyJSON{
+		PerMinute: p.PerMinute,
+		PerHour:   p.PerHour,
+		PerDay:    p.PerDay,
+		MinGap:    p.MinGap.String(),
+		Jitter:    p.Jitter.String(),
+	})
+}
+
+// UnmarshalJSON 间隔使用 time.ParseDuration 格式
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	var raw policyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = Policy{PerMinute: raw.PerMinute, PerHour: raw.PerHour, PerDay: raw.PerDay}
+	var err error
+	if raw.MinGap != "" {
+		if p.MinGap, err = time.ParseDuration(raw.MinGap); err != nil {
+			return errors.Wrap(err, "min_gap")
+		}
+	}
+	if raw.Jitter != "" {
+		if p.Jitter, err = time.ParseDuration(raw.Jitter); err != nil {
+			return errors.Wrap(err, "jitter")
+		}
+	}
+	return nil
+}
+
+// DefaultPolicies 默认限制，偏保守以降低风控概率
+func DefaultPolicies() map[Action]Policy {
+	return map[Action]Policy{
+		ActionLike:     {PerMinute: 6, PerHour: 60, PerDay: 300, MinGap: 5 * time.Second, Jitter: 5 * time.Second},
+		ActionFavorite: {PerMinute: 5, PerHour: 50, PerDay: 200, MinGap: 5 * time.Second, Jitter: 5 * time.Second},
+		ActionComment:  {PerMinute: 2, PerHour: 15, PerDay: 60, MinGap: 30 * time.Second, Jitter: 30 * time.Second},
+		ActionReply:    {PerMinute: 2, PerHour: 15, PerDay: 60, MinGap: 30 * time.Second, Jitter: 30 * time.Second},
+		ActionFollow:   {PerMinute: 3, PerHour: 30, PerDay: 100, MinGap: 10 * time.Second, Jitter: 10 * time.Second},
+		ActionMessage:  {PerMinute: 2, PerHour: 20, PerDay: 100, MinGap: 20 * time.Second, Jitter: 20 * time.Second},
+	}
+}
+
+// LoadPolicies 读取 JSON 策略文件并覆盖默认值；文件中未出现的互动类型保持默认
+func LoadPolicies(path string) (map[Action]Policy, error) {
+	policies := DefaultPolicies()
+	if path == "" {
+		return policies, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read quota policy %s", path)
+	}
+	var overrides map[Action]Policy
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, errors.Wrapf(err, "parse quota policy %s", path)
+	}
+	for action, p := range overrides {
+		policies[action] = p
+	}
+	return policies, nil
+}
+
+// ActionStatus 某账号某互动类型的额度使用情况
+type ActionStatus struct {
+	Action          Action     `json:"action"`
+	Policy          Policy     `json:"policy"`
+	UsedMinute      int        `json:"used_minute"`
+	UsedHour        int        `json:"used_hour"`
+	UsedDay         int        `json:"used_day"`
+	RemainingMinute *int       `json:"remaining_minute,omitempty"`
+	RemainingHour   *int       `json:"remaining_hour,omitempty"`
+	RemainingDay    *int       `json:"remaining_day,omitempty"`
+	NextAllowedAt   *time.Time `json:"next_allowed_at,omitempty"`
+}
+
+type actionState struct {
+	// Times 最近 24 小时内的操作时间
+	Times []time.Time `json:"times"`
+	// NextAt 含随机间隔的下一次最早可操作时间
+	NextAt time.Time `json:"next_at"`
+}
+
+// Manager 按账号统计互动次数，计数持久化到本地文件
+type Manager struct {
+	mu        sync.Mutex
+	policies  map[Action]Policy
+	state     map[string]map[Action]*actionState
+	storePath string
+	jitter    func(time.Duration) time.Duration
+}
+
+// NewManager 创建额度管理器并从 storePath 恢复计数
+func NewManager(storePath string, policies map[Action]Policy) (*Manager, error) {
+	m := &Manager{
+		policies:  policies,
+		state:     map[string]map[Action]*actionState{},
+		storePath: storePath,
+		jitter: func(max time.Duration) time.Duration {
+			if max <= 0 {
+				return 0
+			}
+			return rand.N(max)
+		},
+	}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Consume 检查并占用一次额度；超限时返回 *ExceededError 且不计数
+func (m *Manager) Consume(accountKey string, action Action, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.policies[action]
+	if !ok {
+		return nil
+	}
+	st := m.stateLocked(accountKey, action, now)
+
+	if now.Before(st.NextAt) {
+		return &ExceededError{Action: action, Rule: "min gap", RetryAfter: st.NextAt.Sub(now)}
+	}
+	for _, w := range windows(p) {
+		if w.limit <= 0 {
+			continue
+		}
+		in := since(st.Times, now.Add(-w.span))
+		if len(in) >= w.limit {
+			// 窗口内最早一次操作滑出窗口后即可重试
+			retry := in[len(in)-w.limit].Add(w.span).Sub(now)
+			return &ExceededError{Action: action, Rule: fmt.Sprintf("%d/%s", w.limit, w.name), RetryAfter: retry}
+		}
+	}
+
+	st.Times = append(st.Times, now)
+	st.NextAt = now.Add(p.MinGap + m.jitter(p.Jitter))
+	if err := m.saveLocked(); err != nil {
+		// 写盘失败不影响本次操作，计数仍在内存中生效
+		logrus.Warnf("persist quota failed: %v", err)
+	}
+	return nil
+}
+
+// Refund 撤销 Consume 在 at 时刻占用的额度，用于操作没有真正执行的情况（如页面打开失败）；
+// 撤销的是最近一次操作时同时取消它带来的最小间隔
+func (m *Manager) Refund(accountKey string, action Action, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.policies[action]; !ok {
+		return
+	}
+	st := m.stateLocked(accountKey, action, at)
+	for i := len(st.Times) - 1; i >= 0; i-- {
+		if !st.Times[i].Equal(at) {
+			continue
+		}
+		if i == len(st.Times)-1 {
+			st.NextAt = at
+		}
+		st.Times = append(st.Times[:i:i], st.Times[i+1:]...)
+		if err := m.saveLocked(); err != nil {
+			logrus.Warnf("persist quota failed: %v", err)
+		}
+		return
+	}
+}
+
+// Status 返回账号各互动类型的额度使用情况
+func (m *Manager) Status(accountKey string, now time.Time) []ActionStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	actions := make([]Action, 0, len(m.policies))
+	for a := range m.policies {
+		actions = append(actions, a)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+
+	out := make([]ActionStatus, 0, len(actions))
+	for _, a := range actions {
+		p := m.policies[a]
+		st := m.stateLocked(accountKey, a, now)
+		as := ActionStatus{
+			Action:     a,
+			Policy:     p,
+			UsedMinute: len(since(st.Times, now.Add(-time.Minute))),
+			UsedHour:   len(since(st.Times, now.Add(-time.Hour))),
+			UsedDay:    len(since(st.Times, now.Add(-24*time.Hour))),
+		}
+		as.RemainingMinute = remaining(p.PerMinute, as.UsedMinute)
+		as.RemainingHour = remaining(p.PerHour, as.UsedHour)
+		as.RemainingDay = remaining(p.PerDay, as.UsedDay)
+		if now.Before(st.NextAt) {
+			next := st.NextAt
+			as.NextAllowedAt = &next
+		}
+		out = append(out, as)
+	}
+	return out
+}
+
+type window struct {
+	name  string
+	span  time.Duration
+	limit int
+}
+
+func windows(p Policy) []window {
+	return []window{
+		{"day", 24 * time.Hour, p.PerDay},
+		{"hour", time.Hour, p.PerHour},
+		{"minute", time.Minute, p.PerMinute},
+	}
+}
+
+// since 返回 times 中晚于 from 的部分，times 按时间升序
+func since(times []time.Time, from time.Time) []time.Time {
+	i := sort.Search(len(times), func(i int) bool { return times[i].After(from) })
+	return times[i:]
+}
+
+func remaining(limit, used int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	n := limit - used
+	if n < 0 {
+		n = 0
+	}
+	return &n
+}
+
+// stateLocked 返回账号某互动类型的状态，并清理 24 小时之前的记录
+func (m *Manager) stateLocked(accountKey string, action Action, now time.Time) *actionState {
+	byAction, ok := m.state[accountKey]
+	if !ok {
+		byAction = map[Action]*actionState{}
+		m.state[accountKey] = byAction
+	}
+	st, ok := byAction[action]
+	if !ok {
+		st = &actionState{}
+		byAction[action] = st
+	}
+	st.Times = since(st.Times, now.Add(-24*time.Hour))
+	return st
+}
+
+func (m *Manager) saveLocked() error {
+	if m.storePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(m.storePath), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(struct {
+		Accounts map[string]map[Action]*actionState `json:"accounts"`
+	}{Accounts: m.state}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := m.storePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.storePath)
+}
+
+func (m *Manager) load() error {
+	if m.storePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(m.storePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var payload struct {
+		Accounts map[string]map[Action]*actionState `json:"accounts"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return errors.Wrapf(err, "parse quota store %s", m.storePath)
+	}
+	for key, byAction := range payload.Accounts {
+		for _, st := range byAction {
+			sort.Slice(st.Times, func(i, j int) bool { return st.Times[i].Before(st.Times[j]) })
+		}
+		m.state[key] = byAction
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T, storePath string, p Policy) *Manager {
+	t.Helper()
+	m, err := NewManager(storePath, map[Action]Policy{ActionLike: p})
+	require.NoError(t, err)
+	m.jitter = func(time.Duration) time.Duration { return 0 }
+	return m
+}
+
+func TestConsumeWindows(t *testing.T) {
+	m := newTestManager(t, "", Policy{PerMinute: 2, PerHour: 3})
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, m.Consume("acc_1", ActionLike, now))
+	require.NoError(t, m.Consume("acc_1", ActionLike, now.Add(10*time.Second)))
+
+	err := m.Consume("acc_1", ActionLike, now.Add(20*time.Second))
+	var exceeded *ExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+	assert.Equal(t, "2/minute", exceeded.Rule)
+	assert.Equal(t, 40*time.Second, exceeded.RetryAfter)
+
+	// 其他账号、未配置的互动类型不受影响
+	require.NoError(t, m.Consume("acc_2", ActionLike, now))
+	require.NoError(t, m.Consume("acc_1", ActionComment, now))
+
+	require.NoError(t, m.Consume("acc_1", ActionLike, now.Add(time.Minute+time.Second)))
+	err = m.Consume("acc_1", ActionLike, now.Add(2*time.Minute+time.Second))
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, "3/hour", exceeded.Rule)
+	assert.Equal(t, 58*time.Minute-time.Second, exceeded.RetryAfter)
+}
+
+func TestConsumeMinGap(t *testing.T) {
+	m := newTestManager(t, "", Policy{MinGap: 30 * time.Second, Jitter: 10 * time.Second})
+	m.jitter = func(max time.Duration) time.Duration { return max }
+	now := time.Now()
+
+	require.NoError(t, m.Consume("acc_1", ActionLike, now))
+	err := m.Consume("acc_1", ActionLike, now.Add(35*time.Second))
+	var exceeded *ExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, 5*time.Second, exceeded.RetryAfter)
+	require.NoError(t, m.Consume("acc_1", ActionLike, now.Add(40*time.Second)))
+}
+
+func TestRefund(t *testing.T) {
+	m := newTestManager(t, "", Policy{PerMinute: 1, MinGap: 30 * time.Second})
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, m.Consume("acc_1", ActionLike, now))
+	require.Error(t, m.Consume("acc_1", ActionLike, now.Add(time.Second)))
+
+	// 撤销后额度与最小间隔都恢复
+	m.Refund("acc_1", ActionLike, now)
+	require.NoError(t, m.Consume("acc_1", ActionLike, now.Add(time.Second)))
+
+	// 没有对应记录或未配置的互动类型不受影响
+	m.Refund("acc_1", ActionLike, now)
+	m.Refund("acc_1", ActionComment, now)
+	assert.Error(t, m.Consume("acc_1", ActionLike, now.Add(2*time.Second)))
+}
+
+func TestStatusAndPersistence(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "quota.json")
+	p := Policy{PerMinute: 5, PerDay: 10, MinGap: time.Minute}
+	m := newTestManager(t, storePath, p)
+	now := time.Now()
+	require.NoError(t, m.Consume("acc_1", ActionLike, now))
+
+	reloaded := newTestManager(t, storePath, p)
+	st := reloaded.Status("acc_1", now.Add(time.Second))
+	require.Len(t, st, 1)
+	assert.Equal(t, 1, st[0].UsedDay)
+	assert.Equal(t, 4, *st[0].RemainingMinute)
+	assert.Nil(t, st[0].RemainingHour)
+	assert.Equal(t, 9, *st[0].RemainingDay)
+	require.NotNil(t, st[0].NextAllowedAt)
+
+	// 超过 24 小时的记录不再计入
+	st = reloaded.Status("acc_1", now.Add(25*time.Hour))
+	assert.Equal(t, 0, st[0].UsedDay)
+	assert.Nil(t, st[0].NextAllowedAt)
+}
+
+func TestLoadPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"like": {"per_hour": 10, "min_gap": "1m", "jitter": "20s"}}`), 0o644))
+
+	policies, err := LoadPolicies(path)
+	require.NoError(t, err)
+	assert.Equal(t, Policy{PerHour: 10, MinGap: time.Minute, Jitter: 20 * time.Second}, policies[ActionLike])
+	assert.Equal(t, DefaultPolicies()[ActionComment], policies[ActionComment])
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"like": {"min_gap": "soon"}}`), 0o644))
+	_, err = LoadPolicies(path)
+	assert.Error(t, err)
+}
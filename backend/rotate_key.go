@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
+)
+
+// runRotateKey 实现 rotate-key 子命令：用旧密钥解密所有 cookies 与账号数据，再用新密钥重新加密。
+// 旧密钥默认取自 XHS_ENCRYPTION_KEY / XHS_ENCRYPTION_KEY_FILE，未配置时视为明文。
+func runRotateKey(args []string) error {
+	fs := flag.NewFlagSet("rotate-key", flag.ContinueOnError)
+	var (
+		oldKeyFile string
+		newKeyFile string
+		decrypt    bool
+	)
+	fs.StringVar(&oldKeyFile, "old-key-file", "", "旧密钥文件，默认使用环境变量中的密钥")
+	fs.StringVar(&newKeyFile, "new-key-file", "", "新密钥文件，不存在时自动生成")
+	fs.BoolVar(&decrypt, "decrypt", false, "解密为明文并关闭加密")
+	storage := fs.String("storage", storageBackend(), "存储后端：json 或 sqlite")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (newKeyFile == "") == !decrypt {
+		return errors.New("必须指定 -new-key-file 或 -decrypt 之一")
+	}
+
+	oldBox, err := secret.FromEnv()
+	if oldKeyFile != "" {
+		oldBox, err = secret.LoadKeyFile(oldKeyFile)
+	}
+	if err != nil {
+		return errors.Wrap(err, "load old key")
+	}
+
+	var newBox *secret.Box
+	if !decrypt {
+		if newBox, err = loadOrCreateKeyFile(newKeyFile); err != nil {
+			return errors.Wrap(err, "load new key")
+		}
+		if oldBox != nil && oldBox.ID() == newBox.ID() {
+			return errors.New("新旧密钥相同")
+		}
+	}
+
+	// 先用旧密钥读出全部数据，确认都能解密后再写入，避免中途失败留下混合密钥的文件
+	secret.SetDefault(oldBox)
+	am, where, err := openAccountManager(*storage)
+	if err != nil {
+		return errors.Wrap(err, "load accounts")
+	}
+	defer am.Close()
+
+	cookieFiles := map[string][]byte{}
+	for _, acc := range am.List() {
+		if acc.CookiePath == "" {
+			continue
+		}
+		data, err := cookies.NewRawCookie(acc.CookiePath).LoadCookies()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return errors.Wrapf(err, "read cookies of %s", acc.Key)
+		}
+		plain, err := oldBox.Open(data)
+		if err != nil {
+			return errors.Wrapf(err, "decrypt cookies of %s", acc.Key)
+		}
+		cookieFiles[acc.CookiePath] = plain
+	}
+
+	for path, plain := range cookieFiles {
+		sealed, err := newBox.Seal(plain)
+		if err != nil {
+			return err
+		}
+		if err := cookies.NewRawCookie(path).SaveCookies(sealed); err != nil {
+			return errors.Wrapf(err, "write %s", path)
+		}
+	}
+	secret.SetDefault(newBox)
+	if err := am.Save(); err != nil {
+		return errors.Wrap(err, "write accounts")
+	}
+
+	if newBox == nil {
+		logrus.Infof("已解密 %d 份 cookies 与账号数据 %s，请移除 %s / %s 环境变量",
+			len(cookieFiles), where, secret.EnvKey, secret.EnvKeyFile)
+		return nil
+	}
+	logrus.Infof("已用新密钥（指纹 %s）重新加密 %d 份 cookies 与账号数据 %s", newBox.ID(), len(cookieFiles), where)
+	logrus.Infof("请将 %s 设置为 %s 后重启服务", secret.EnvKeyFile, newKeyFile)
+	return nil
+}
+
+// loadOrCreateKeyFile 读取密钥文件，不存在时生成新密钥并以 0600 权限写入
+func loadOrCreateKeyFile(path string) (*secret.Box, error) {
+	if _, err := os.Stat(path); err == nil {
+		return secret.LoadKeyFile(path)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := secret.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(key+"\n"), 0o600); err != nil {
+		return nil, err
+	}
+	logrus.Infof("已生成新密钥: %s", path)
+	return secret.LoadKeyFile(path)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
+)
+
+func TestRotateKey(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "accounts.json")
+	t.Setenv("ACCOUNTS_STORE", storePath)
+	t.Setenv("USER_DATA_BASE_DIR", filepath.Join(dir, "profiles"))
+	t.Setenv("COOKIES_BASE_DIR", filepath.Join(dir, "cookies"))
+	t.Setenv(secret.EnvKey, "")
+	t.Setenv(secret.EnvKeyFile, "")
+	t.Cleanup(func() { secret.SetDefault(nil) })
+
+	// 旧的明文数据
+	secret.SetDefault(nil)
+	am, err := accounts.NewManager(storePath, filepath.Join(dir, "profiles"))
+	require.NoError(t, err)
+	acc, err := am.Create("", "")
+	require.NoError(t, err)
+	_, err = am.ApplyProxyConfig(acc.ID, accounts.ProxyConfig{Type: "http", Host: "127.0.0.1", Port: 8080, User: "u", Pass: "p@ss"})
+	require.NoError(t, err)
+	require.NoError(t, cookies.NewLoadCookie(acc.CookiePath).SaveCookies([]byte(`[{"name":"web_session"}]`)))
+
+	// 明文 -> 第一把密钥
+	keyA := filepath.Join(dir, "a.key")
+	require.NoError(t, runRotateKey([]string{"-new-key-file", keyA}))
+	assertSealed(t, storePath, acc.CookiePath, "p@ss", "web_session")
+
+	// 第一把 -> 第二把
+	t.Setenv(secret.EnvKeyFile, keyA)
+	keyB := filepath.Join(dir, "b.key")
+	require.NoError(t, runRotateKey([]string{"-new-key-file", keyB}))
+	assertSealed(t, storePath, acc.CookiePath, "p@ss", "web_session")
+
+	boxB, err := secret.LoadKeyFile(keyB)
+	require.NoError(t, err)
+	secret.SetDefault(boxB)
+	reloaded, err := accounts.NewManager(storePath, filepath.Join(dir, "profiles"))
+	require.NoError(t, err)
+	got, err := reloaded.Get(acc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "p@ss", got.ProxyPass)
+	assert.Equal(t, "http://u:p@ss@127.0.0.1:8080", got.Proxy)
+	data, err := cookies.NewLoadCookie(acc.CookiePath).LoadCookies()
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"web_session"}]`, string(data))
+
+	// 旧密钥已无法解密
+	boxA, err := secret.LoadKeyFile(keyA)
+	require.NoError(t, err)
+	secret.SetDefault(boxA)
+	_, err = accounts.NewManager(storePath, filepath.Join(dir, "profiles"))
+	assert.Error(t, err)
+}
+
+func assertSealed(t *testing.T, storePath, cookiePath string, plaintexts ...string) {
+	t.Helper()
+	for _, path := range []string{storePath, cookiePath} {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		for _, p := range plaintexts {
+			assert.False(t, strings.Contains(string(data), p), "%s still contains %q", path, p)
+		}
+	}
+}
@@ -5,6 +5,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // setupRoutes 设置路由配置
@@ -13,6 +16,10 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
+	// 链路追踪：每个请求一个 span，沿用请求头 traceparent 中的上游链路；健康检查与指标抓取不记录
+	router.Use(otelgin.Middleware(tracing.ServiceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		return c.FullPath() != "/health" && c.FullPath() != "/metrics"
+	})))
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
@@ -21,7 +28,11 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 	router.Use(corsMiddleware())
 
 	// 健康检查
-	router.GET("/health", healthHandler)
+	router.GET("/health", appServer.healthHandler)
+
+	// Prometheus 指标，包含所有账号的数据，需要 admin 权限
+	auth := authMiddleware(appServer.apiKeys)
+	router.GET("/metrics", auth, requireScope(apikey.ScopeAdmin), gin.WrapH(appServer.xiaohongshuService.MetricsHandler()))
 
 	// MCP 端点 - 使用官方 SDK 的 Streamable HTTP Handler
 	mcpHandler := mcp.NewStreamableHTTPHandler(
@@ -32,34 +43,75 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 			JSONResponse: true, // 支持 JSON 响应
 		},
 	)
-	router.Any("/mcp", gin.WrapH(mcpHandler))
-	router.Any("/mcp/*path", gin.WrapH(mcpHandler))
+	router.Any("/mcp", auth, gin.WrapH(mcpHandler))
+	router.Any("/mcp/*path", auth, gin.WrapH(mcpHandler))
+
+	// API 路由组，按权限分组：read 只读、publish 发布与互动、admin 账号管理
+	api := router.Group("/api/v1", auth)
 
-	// API 路由组
-	api := router.Group("/api/v1")
+	read := api.Group("", requireScope(apikey.ScopeRead))
 	{
-		api.POST("/login/start", appServer.startLoginHandler)
-		api.GET("/login/status", appServer.checkLoginStatusHandler)
-		api.GET("/login/qrcode", appServer.getLoginQrcodeHandler)
-		api.DELETE("/login/cookies", appServer.deleteCookiesHandler)
+		read.GET("/login/status", appServer.checkLoginStatusHandler)
+		read.GET("/quota", appServer.quotaHandler)
+		read.GET("/accounts/:id/logins", appServer.accountLoginsHandler)
+		read.GET("/jobs", appServer.listJobsHandler)
+		read.GET("/jobs/:id", appServer.getJobHandler)
+		read.GET("/calendar", appServer.listCalendarHandler)
+		read.GET("/calendar/:id", appServer.getCalendarEntryHandler)
+		read.GET("/audit", appServer.listAuditHandler)
+		read.GET("/feeds/list", appServer.listFeedsHandler)
+		read.GET("/feeds/search", appServer.searchFeedsHandler)
+		read.POST("/feeds/search", appServer.searchFeedsHandler)
+		read.POST("/feeds/detail", appServer.getFeedDetailHandler)
+		read.POST("/user/profile", appServer.userProfileHandler)
+		read.GET("/user/me", appServer.myProfileHandler)
+		read.GET("/user/following", appServer.listFollowingHandler)
+		read.GET("/user/followers", appServer.listFollowersHandler)
+		read.GET("/creator/notes", appServer.listCreatorNotesHandler)
+		read.GET("/drafts", appServer.listDraftsHandler)
+		read.GET("/analytics/notes", appServer.noteAnalyticsHandler)
+		read.GET("/analytics/account", appServer.accountAnalyticsHandler)
+		read.GET("/notifications", appServer.listNotificationsHandler)
+		read.GET("/messages", appServer.listConversationsHandler)
+		read.GET("/messages/:user_id", appServer.getMessagesHandler)
+	}
 
-		api.GET("/accounts", appServer.listAccountsHandler)
-		api.POST("/accounts/:id/start", appServer.startAccountWindowHandler)
-		api.POST("/raw/start", appServer.startRawWindowHandler)
-		api.POST("/accounts/:id/proxy", appServer.updateProxyHandler)
-		api.DELETE("/accounts/:id", appServer.deleteAccountHandler)
-		api.POST("/proxy/test", appServer.testProxyHandler)
+	publish := api.Group("", requireScope(apikey.ScopePublish))
+	{
+		publish.POST("/publish", appServer.publishHandler)
+		publish.POST("/publish_video", appServer.publishVideoHandler)
+		publish.POST("/drafts/:id/publish", appServer.publishDraftHandler)
+		publish.POST("/calendar", appServer.addCalendarEntryHandler)
+		publish.PUT("/calendar/:id", appServer.updateCalendarEntryHandler)
+		publish.DELETE("/calendar/:id", appServer.cancelCalendarEntryHandler)
+		publish.POST("/feeds/comment", appServer.postCommentHandler)
+		publish.POST("/feeds/comment/reply", appServer.replyCommentHandler)
+		publish.POST("/feeds/comment/like", appServer.likeCommentHandler)
+		publish.POST("/feeds/comment/delete", appServer.deleteCommentHandler)
+		publish.POST("/feeds/comment/pin", appServer.pinCommentHandler)
+		publish.POST("/messages/:user_id", appServer.sendMessageHandler)
+		publish.POST("/user/follow", appServer.followUserHandler)
+		publish.POST("/user/unfollow", appServer.unfollowUserHandler)
+		publish.PUT("/creator/notes/:id", appServer.editCreatorNoteHandler)
+		publish.DELETE("/creator/notes/:id", appServer.deleteCreatorNoteHandler)
+		publish.POST("/creator/notes/:id/visibility", appServer.setNoteVisibilityHandler)
+	}
 
-		api.POST("/publish", appServer.publishHandler)
-		api.POST("/publish_video", appServer.publishVideoHandler)
-		api.GET("/feeds/list", appServer.listFeedsHandler)
-		api.GET("/feeds/search", appServer.searchFeedsHandler)
-		api.POST("/feeds/search", appServer.searchFeedsHandler)
-		api.POST("/feeds/detail", appServer.getFeedDetailHandler)
-		api.POST("/user/profile", appServer.userProfileHandler)
-		api.POST("/feeds/comment", appServer.postCommentHandler)
-		api.POST("/feeds/comment/reply", appServer.replyCommentHandler)
-		api.GET("/user/me", appServer.myProfileHandler)
+	admin := api.Group("", requireScope(apikey.ScopeAdmin))
+	{
+		admin.POST("/login/start", appServer.startLoginHandler)
+		admin.GET("/login/qrcode", appServer.getLoginQrcodeHandler)
+		admin.DELETE("/login/cookies", appServer.deleteCookiesHandler)
+		admin.GET("/accounts", appServer.listAccountsHandler)
+		admin.GET("/status", appServer.statusHandler)
+		admin.POST("/accounts/:id/start", appServer.startAccountWindowHandler)
+		admin.POST("/raw/start", appServer.startRawWindowHandler)
+		admin.POST("/accounts/:id/proxy", appServer.updateProxyHandler)
+		admin.DELETE("/accounts/:id", appServer.deleteAccountHandler)
+		admin.POST("/proxy/test", appServer.testProxyHandler)
+		admin.GET("/selectors/check", appServer.checkSelectorsHandler)
+		admin.GET("/artifacts/:id", appServer.downloadArtifactHandler)
+		admin.GET("/artifacts/:id/:file", appServer.downloadArtifactHandler)
 	}
 
 	return router
@@ -0,0 +1,197 @@
+// Package secret 为落盘的敏感数据（cookies、代理凭据）提供可选的 AES-GCM 加密，
+// 未配置密钥时按明文存储。
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// 环境变量：密钥本身或密钥文件路径，二者都未设置时不加密
+const (
+	EnvKey     = "XHS_ENCRYPTION_KEY"
+	EnvKeyFile = "XHS_ENCRYPTION_KEY_FILE"
+)
+
+// prefix 标记加密数据，格式为 prefix + keyID + ":" + base64(nonce|ciphertext)
+const prefix = "xhsenc:v1:"
+
+var (
+	// ErrNoKey 数据已加密但没有配置密钥
+	ErrNoKey = errors.New("data is encrypted but no encryption key is configured")
+	// ErrWrongKey 数据由其他密钥加密
+	ErrWrongKey = errors.New("data is encrypted with a different key")
+	// ErrCorrupted 密文无法解析或校验失败
+	ErrCorrupted = errors.New("encrypted data is corrupted")
+)
+
+// Box 使用一个 256 位密钥加解密数据
+type Box struct {
+	aead cipher.AEAD
+	id   string
+}
+
+// NewBox 由 32 字节密钥创建 Box
+func NewBox(key []byte) (*Box, error) {
+	if len(key) != 32 {
+		return nil, errors.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &Box{aead: aead, id: hex.EncodeToString(sum[:4])}, nil
+}
+
+// ParseKey 解析 base64 或 hex 编码的 32 字节密钥
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := hex.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, errors.New("encryption key must be 32 bytes encoded as base64 or hex")
+}
+
+// GenerateKey 生成随机密钥，返回 base64 编码
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// LoadKeyFile 从文件读取密钥并创建 Box
+func LoadKeyFile(path string) (*Box, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read key file %s", path)
+	}
+	key, err := ParseKey(string(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "key file %s", path)
+	}
+	return NewBox(key)
+}
+
+// FromEnv 按环境变量创建 Box，未配置密钥时返回 nil
+func FromEnv() (*Box, error) {
+	if v := os.Getenv(EnvKey); v != "" {
+		key, err := ParseKey(v)
+		if err != nil {
+			return nil, errors.Wrap(err, EnvKey)
+		}
+		return NewBox(key)
+	}
+	if path := os.Getenv(EnvKeyFile); path != "" {
+		return LoadKeyFile(path)
+	}
+	return nil, nil
+}
+
+// ID 返回密钥指纹，写在密文中用于识别密钥是否匹配
+func (b *Box) ID() string {
+	return b.id
+}
+
+// Seal 加密数据。b 为 nil 时原样返回。
+func (b *Box) Seal(plaintext []byte) ([]byte, error) {
+	if b == nil {
+		return plaintext, nil
+	}
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := b.aead.Seal(nonce, nonce, plaintext, nil)
+	out := make([]byte, 0, len(prefix)+len(b.id)+1+base64.StdEncoding.EncodedLen(len(sealed)))
+	out = append(out, prefix...)
+	out = append(out, b.id...)
+	out = append(out, ':')
+	return base64.StdEncoding.AppendEncode(out, sealed), nil
+}
+
+// Open 解密数据；未加密的数据原样返回，以便兼容旧的明文文件。
+func (b *Box) Open(data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	if b == nil {
+		return nil, ErrNoKey
+	}
+	rest := data[len(prefix):]
+	i := bytes.IndexByte(rest, ':')
+	if i < 0 {
+		return nil, ErrCorrupted
+	}
+	if string(rest[:i]) != b.id {
+		return nil, errors.Wrapf(ErrWrongKey, "key %s, current key %s", rest[:i], b.id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(rest[i+1:])))
+	if err != nil || len(sealed) < b.aead.NonceSize() {
+		return nil, ErrCorrupted
+	}
+	n := b.aead.NonceSize()
+	plaintext, err := b.aead.Open(nil, sealed[:n], sealed[n:], nil)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	return plaintext, nil
+}
+
+// SealString 加密字符串，空字符串保持为空
+func (b *Box) SealString(s string) (string, error) {
+	if s == "" || b == nil {
+		return s, nil
+	}
+	out, err := b.Seal([]byte(s))
+	return string(out), err
+}
+
+// OpenString 解密 SealString 的结果
+func (b *Box) OpenString(s string) (string, error) {
+	out, err := b.Open([]byte(s))
+	return string(out), err
+}
+
+// IsSealed 判断数据是否为本包加密的格式
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(prefix))
+}
+
+var (
+	mu         sync.RWMutex
+	defaultBox *Box
+)
+
+// SetDefault 设置进程内使用的默认 Box，nil 表示关闭加密
+func SetDefault(b *Box) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultBox = b
+}
+
+// Default 返回默认 Box，未启用加密时为 nil
+func Default() *Box {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultBox
+}
@@ -0,0 +1,68 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBox(t *testing.T) *Box {
+	t.Helper()
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	raw, err := ParseKey(key)
+	require.NoError(t, err)
+	b, err := NewBox(raw)
+	require.NoError(t, err)
+	return b
+}
+
+func TestSealOpen(t *testing.T) {
+	b := newTestBox(t)
+
+	sealed, err := b.Seal([]byte(`[{"name":"web_session"}]`))
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "web_session")
+
+	plain, err := b.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"web_session"}]`, string(plain))
+
+	// 明文原样返回，兼容旧文件
+	plain, err = b.Open([]byte("plain"))
+	require.NoError(t, err)
+	assert.Equal(t, "plain", string(plain))
+}
+
+func TestOpenErrors(t *testing.T) {
+	b := newTestBox(t)
+	sealed, err := b.SealString("secret-pass")
+	require.NoError(t, err)
+
+	var nilBox *Box
+	_, err = nilBox.OpenString(sealed)
+	assert.True(t, errors.Is(err, ErrNoKey))
+
+	_, err = newTestBox(t).OpenString(sealed)
+	assert.True(t, errors.Is(err, ErrWrongKey))
+
+	tampered := sealed[:len(sealed)-4] + "AAAA"
+	_, err = b.OpenString(tampered)
+	assert.True(t, errors.Is(err, ErrCorrupted))
+
+	empty, err := b.SealString("")
+	require.NoError(t, err)
+	assert.Equal(t, "", empty)
+}
+
+func TestParseKey(t *testing.T) {
+	_, err := ParseKey("too-short")
+	assert.Error(t, err)
+
+	key, err := ParseKey("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f\n")
+	require.NoError(t, err)
+	assert.Len(t, key, 32)
+}
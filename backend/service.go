@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sync"
 	"time"
@@ -12,28 +13,92 @@ import (
 	"github.com/mattn/go-runewidth"
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/artifacts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/calendar"
+	"github.com/xpzouying/xiaohongshu-mcp/concurrency"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/inbox"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/metrics"
 	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
 	"github.com/xpzouying/xiaohongshu-mcp/session"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // XiaohongshuService 小红书业务服务
 type XiaohongshuService struct {
 	accounts     *accounts.Manager
+	jobs         *jobs.Manager
+	calendar     *calendar.Store
+	pool         *browser.Pool
+	limiter      *concurrency.Limiter
+	quota        *quota.Manager
+	audit        *audit.Log
+	inbox        *inbox.Store
+	artifacts    *artifacts.Store
 	liveBrowsers []*browser.Browser
 	liveByAccount map[string]*browser.Browser
 	liveMu       sync.Mutex
+
+	// 后台协程（内容日历调度）的生命周期
+	bgCancel context.CancelFunc
+	bgWG     sync.WaitGroup
 }
 
-// NewXiaohongshuService 创建小红书服务实例
-func NewXiaohongshuService(am *accounts.Manager) *XiaohongshuService {
-	return &XiaohongshuService{
+// NewXiaohongshuService 创建小红书服务实例，并启动后台发布任务与内容日历调度
+func NewXiaohongshuService(am *accounts.Manager, jm *jobs.Manager, cal *calendar.Store, pool *browser.Pool, limiter *concurrency.Limiter, qm *quota.Manager, auditLog *audit.Log, inboxStore *inbox.Store, artifactStore *artifacts.Store) *XiaohongshuService {
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	s := &XiaohongshuService{
 		accounts:     am,
+		jobs:         jm,
+		calendar:     cal,
+		pool:         pool,
+		limiter:      limiter,
+		quota:        qm,
+		audit:        auditLog,
+		inbox:        inboxStore,
+		artifacts:    artifactStore,
 		liveBrowsers: make([]*browser.Browser, 0),
 		liveByAccount: make(map[string]*browser.Browser),
+		bgCancel:     bgCancel,
+	}
+	s.registerJobHandlers()
+	jm.Start()
+
+	s.bgWG.Add(1)
+	go func() {
+		defer s.bgWG.Done()
+		s.runCalendarScheduler(bgCtx)
+	}()
+	return s
+}
+
+// Close 停止后台任务执行与日历调度并关闭浏览器池，最多等待到 ctx 结束
+func (s *XiaohongshuService) Close(ctx context.Context) error {
+	s.bgCancel()
+	defer s.pool.Close()
+	if err := s.jobs.Stop(ctx); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.bgWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		if err := s.audit.Close(); err != nil {
+			logrus.Warnf("关闭审计日志失败: %v", err)
+		}
+		return s.accounts.Close()
+	case <-ctx.Done():
+		return fmt.Errorf("等待日历调度退出超时: %w", ctx.Err())
 	}
 }
 
@@ -69,16 +134,186 @@ func (s *XiaohongshuService) clearLiveBrowser(accountKey string, b *browser.Brow
 	}
 }
 
-func (s *XiaohongshuService) getAccountBrowser(ctx context.Context) (*browser.Browser, func(), error) {
-	accountKey := session.Account(ctx)
-	if live := s.getLiveBrowser(accountKey); live != nil {
-		return live, func() {}, nil
+// acquirePage 为当前账号打开一个页面，返回的 release 必须以 defer release(&err) 的方式调用。
+// 同一账号的操作依次执行，并受全局并发上限约束；
+// 优先复用该账号的可视窗口，其次从浏览器池获取常驻浏览器；
+// 显式指定了与全局配置不同的 headless 模式时启动一次性浏览器。
+// 操作返回错误或 panic 时，release 在关闭页面前保存失败现场，并把现场 ID 附加到错误上。
+func (s *XiaohongshuService) acquirePage(ctx context.Context) (*rod.Page, func(*error), error) {
+	return s.acquireActionPage(ctx, "")
+}
+
+// acquireActionPage 与 acquirePage 相同，但在拿到账号执行权后、打开页面前先扣除 action 的互动额度；
+// 页面打开失败时操作没有执行，退还这次额度
+func (s *XiaohongshuService) acquireActionPage(ctx context.Context, action quota.Action) (_ *rod.Page, _ func(*error), err error) {
+	// 等待账号执行权、启动或复用浏览器的耗时单独记为一个 span
+	ctx, span := tracing.Start(ctx, "acquire_page")
+	defer tracing.End(span, &err)
+
+	acc, err := s.resolveAccount(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
-	b, err := s.newBrowser(ctx)
+	span.SetAttributes(tracing.AttrAccount.String(acc.Key))
+
+	unlock, err := s.limiter.Acquire(ctx, acc.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	consumedAt := time.Now()
+	if action != "" {
+		if err := s.quota.Consume(acc.Key, action, consumedAt); err != nil {
+			unlock()
+			return nil, nil, err
+		}
+	}
+	page, closePage, err := s.openPage(ctx, acc)
+	if err != nil {
+		if action != "" {
+			s.quota.Refund(acc.Key, action, consumedAt)
+		}
+		unlock()
+		return nil, nil, err
+	}
+	if s.artifacts == nil {
+		return page, func(*error) {
+			closePage()
+			unlock()
+		}, nil
+	}
+
+	rec := artifacts.Record(page)
+	return page, func(errp *error) {
+		defer unlock()
+		defer closePage()
+		defer rec.Stop()
+		// rod 的 Must* 方法失败时直接 panic，保存现场后带着现场 ID 继续 panic
+		if r := recover(); r != nil {
+			cause, ok := r.(error)
+			if !ok {
+				cause = fmt.Errorf("%v", r)
+			}
+			panic(s.saveArtifact(acc.Key, page, rec, cause))
+		}
+		if errp != nil && *errp != nil {
+			*errp = s.saveArtifact(acc.Key, page, rec, *errp)
+		}
+	}, nil
+}
+
+// saveArtifact 保存失败现场，返回带现场 ID 的错误；保存失败时只记录日志并返回原错误
+func (s *XiaohongshuService) saveArtifact(accountKey string, page *rod.Page, rec *artifacts.Recorder, cause error) error {
+	snap := artifacts.Capture(page, rec, cause)
+	snap.Account = accountKey
+	id, err := s.artifacts.Save(snap)
+	if err != nil {
+		logrus.Warnf("save failure artifact failed: %v", err)
+		return cause
+	}
+	logrus.Warnf("操作失败，现场已保存: %s (%v)", id, cause)
+	return &artifacts.Error{ID: id, Err: cause}
+}
+
+// GetArtifact 读取失败现场的概要
+func (s *XiaohongshuService) GetArtifact(id string) (*artifacts.Meta, error) {
+	if s.artifacts == nil {
+		return nil, artifacts.ErrNotFound
+	}
+	return s.artifacts.Get(id)
+}
+
+// OpenArtifact 打开失败现场中的单个文件
+func (s *XiaohongshuService) OpenArtifact(id, name string) (*os.File, error) {
+	if s.artifacts == nil {
+		return nil, artifacts.ErrNotFound
+	}
+	return s.artifacts.Open(id, name)
+}
+
+// WriteArtifactZip 把失败现场打包为 zip 写入 w
+func (s *XiaohongshuService) WriteArtifactZip(w io.Writer, id string) error {
+	if s.artifacts == nil {
+		return artifacts.ErrNotFound
+	}
+	return s.artifacts.WriteZip(w, id)
+}
+
+func (s *XiaohongshuService) openPage(ctx context.Context, acc *accounts.Account) (*rod.Page, func(), error) {
+	if live := s.getLiveBrowser(acc.Key); live != nil {
+		page, err := newPage(live)
+		if err == nil {
+			return page, func() { _ = page.Close() }, nil
+		}
+		logrus.Warnf("visible browser of %s unusable, falling back to pool: %v", acc.Key, err)
+	}
+
+	if h := session.HeadlessOverride(ctx); h != nil && *h != configs.IsHeadless() {
+		b, err := s.newBrowser(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		page, err := newPage(b)
+		if err != nil {
+			b.Close()
+			return nil, nil, err
+		}
+		return page, func() {
+			_ = page.Close()
+			b.Close()
+		}, nil
+	}
+
+	lease, err := s.pool.Acquire(ctx, acc.Key, func() (*browser.Browser, error) {
+		// 池中的浏览器生命周期独立于单次请求
+		return s.newBrowser(session.WithAccount(context.Background(), acc.Key))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	page, err := newPage(lease.Browser())
 	if err != nil {
+		lease.Discard()
 		return nil, nil, err
 	}
-	return b, func() { b.Close() }, nil
+	return page, func() {
+		_ = page.Close()
+		lease.Release()
+	}, nil
+}
+
+func newPage(b *browser.Browser) (page *rod.Page, err error) {
+	err = rod.Try(func() { page = b.NewPage() })
+	return page, err
+}
+
+// EvictAccountBrowser 关闭账号在浏览器池中的常驻浏览器，例如重新登录或删除账号后
+func (s *XiaohongshuService) EvictAccountBrowser(accountKey string) {
+	s.pool.Evict(accountKey)
+}
+
+// PoolStats 返回浏览器池状态
+func (s *XiaohongshuService) PoolStats() browser.PoolStats {
+	return s.pool.Stats()
+}
+
+// QuotaResponse 账号互动额度
+type QuotaResponse struct {
+	Account string               `json:"account"`
+	Actions []quota.ActionStatus `json:"actions"`
+}
+
+// GetQuota 查询当前账号各互动类型的剩余额度
+func (s *XiaohongshuService) GetQuota(ctx context.Context) (*QuotaResponse, error) {
+	acc, err := s.resolveAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &QuotaResponse{Account: acc.Key, Actions: s.quota.Status(acc.Key, time.Now())}, nil
+}
+
+// ConcurrencyStats 返回账号排队与全局并发状态
+func (s *XiaohongshuService) ConcurrencyStats() concurrency.Stats {
+	return s.limiter.Stats()
 }
 
 // PublishRequest 发布请求
@@ -88,6 +323,10 @@ type PublishRequest struct {
 	Content   string   `json:"content" binding:"required"`
 	Images    []string `json:"images" binding:"required,min=1"`
 	Tags      []string `json:"tags,omitempty"`
+	// PublishAt 定时发布时间，RFC3339 或 "2006-01-02 15:04"（北京时间），为空表示立即发布
+	PublishAt string `json:"publish_at,omitempty"`
+	// queued 由任务执行时设置，表示 PublishAt 已在入队时校验过
+	queued bool
 }
 
 // LoginStatusResponse 登录状态响应
@@ -119,6 +358,10 @@ type PublishVideoRequest struct {
 	Content   string   `json:"content" binding:"required"`
 	Video     string   `json:"video" binding:"required"`
 	Tags      []string `json:"tags,omitempty"`
+	// PublishAt 定时发布时间，格式同 PublishRequest.PublishAt
+	PublishAt string `json:"publish_at,omitempty"`
+	// queued 由任务执行时设置，表示 PublishAt 已在入队时校验过
+	queued bool
 }
 
 // PublishVideoResponse 发布视频响应
@@ -134,6 +377,8 @@ type PublishVideoResponse struct {
 type FeedsListResponse struct {
 	Feeds []xiaohongshu.Feed `json:"feeds"`
 	Count int                `json:"count"`
+	// NextCursor 传给下一次请求的 cursor 以获取后续内容，为空表示没有更多
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // UserProfileResponse 用户主页响应
@@ -144,22 +389,32 @@ type UserProfileResponse struct {
 }
 
 // DeleteCookies 删除 cookies 文件，用于登录重置
-func (s *XiaohongshuService) DeleteCookies(ctx context.Context) error {
+func (s *XiaohongshuService) DeleteCookies(ctx context.Context) (err error) {
+	ctx, span := s.startSpan(ctx, "DeleteCookies")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionDeleteCookies}, time.Now(), &err)
+
+	// 常驻浏览器仍持有旧的登录态
+	if acc, err := s.resolveAccount(ctx); err == nil {
+		s.pool.Evict(acc.Key)
+		s.accounts.MarkLoggedOut(acc.Key)
+	}
 	cookiePath := cookies.GetCookiesFilePathForAccount(session.Account(ctx))
 	cookieLoader := cookies.NewLoadCookie(cookiePath)
 	return cookieLoader.DeleteCookies()
 }
 
 // CheckLoginStatus 检查登录状态
-func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatusResponse, error) {
-	b, closeBrowser, err := s.getAccountBrowser(ctx)
+func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (_ *LoginStatusResponse, err error) {
+	ctx, span := s.startSpan(ctx, "CheckLoginStatus")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "check_login_status", time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer closeBrowser()
-
-	page := b.NewPage()
-	defer func() { _ = page.Close() }()
+	defer release(&err)
 
 	loginAction := xiaohongshu.NewLogin(page)
 
@@ -171,6 +426,8 @@ func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatus
 		if err := s.saveCookies(ctx, page); err != nil {
 			logrus.Warnf("failed to save cookies after login status ok: %v", err)
 		}
+	} else {
+		s.accounts.MarkLoggedOut(s.auditAccountKey(ctx))
 	}
 
 	response := &LoginStatusResponse{
@@ -184,14 +441,34 @@ func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatus
 // GetLoginQrcode 获取登录的扫码二维码
 func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeResponse, error) {
 	logrus.Info("GetLoginQrcode: resolve account")
-	if _, err := s.resolveAccount(ctx); err != nil {
+	acc, err := s.resolveAccount(ctx)
+	if err != nil {
 		return nil, err
 	}
+	// 扫码等待期间一直占用账号，避免其他操作在同一用户目录上再启动浏览器
+	unlock, err := s.limiter.Acquire(ctx, acc.Key)
+	if err != nil {
+		return nil, err
+	}
+	// 交给等待扫码的 goroutine 之前返回或 panic（如 Must* 调用）时，在这里关闭浏览器并释放账号
+	var b *browser.Browser
+	handedOff := false
+	defer func() {
+		if handedOff {
+			return
+		}
+		if b != nil {
+			b.Close()
+		}
+		unlock()
+	}()
+	// 登录浏览器与池中浏览器共用同一个用户目录，先关闭池中的
+	s.pool.Evict(acc.Key)
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	logrus.Info("GetLoginQrcode: creating browser")
-	b, err := s.newBrowser(ctx)
+	b, err = s.newBrowser(ctx)
 	if err != nil {
 		logrus.Errorf("GetLoginQrcode: newBrowser failed: %v", err)
 		return nil, err
@@ -203,14 +480,12 @@ func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeRe
 	deferFunc := func() {
 		_ = page.Close()
 		b.Close()
+		unlock()
 	}
 
 	loginAction := xiaohongshu.NewLogin(page)
 	logrus.Info("GetLoginQrcode: fetching QR code")
 	img, loggedIn, err := loginAction.FetchQrcodeImage(ctx)
-	if err != nil || loggedIn {
-		defer deferFunc()
-	}
 	if err != nil {
 		logrus.Errorf("GetLoginQrcode: FetchQrcodeImage error: %v", err)
 		return nil, err
@@ -219,6 +494,7 @@ func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeRe
 	timeout := 4 * time.Minute
 
 	if !loggedIn {
+		handedOff = true
 		go func() {
 			ctxWithAccount := session.WithAccount(context.Background(), session.Account(ctx))
 			ctxTimeout, cancel := context.WithTimeout(ctxWithAccount, timeout)
@@ -254,6 +530,17 @@ func (s *XiaohongshuService) LoginAndWait(ctx context.Context, timeout time.Dura
 		defer cancel()
 	}
 
+	acc, err := s.resolveAccount(ctx)
+	if err != nil {
+		return err
+	}
+	unlock, err := s.limiter.Acquire(ctx, acc.Key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	s.pool.Evict(acc.Key)
+
 	b, err := s.newBrowser(ctx)
 	if err != nil {
 		return err
@@ -287,12 +574,24 @@ func (s *XiaohongshuService) StartVisibleWindow(ctx context.Context) error {
 	bg := session.WithAccount(context.Background(), accountKey)
 	bg = session.WithHeadless(bg, false)
 
+	acc, err := s.resolveAccount(bg)
+	if err != nil {
+		return err
+	}
+	// 只在启动阶段占用账号；窗口打开后其他操作直接在该窗口中新开页面
+	unlock, err := s.limiter.Acquire(ctx, acc.Key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if accountKey != "" {
 		if live := s.getLiveBrowser(accountKey); live != nil {
 			s.clearLiveBrowser(accountKey, live)
 			live.Close()
 		}
 	}
+	s.pool.Evict(acc.Key)
 
 	b, err := s.newBrowser(bg)
 	if err != nil {
@@ -363,7 +662,11 @@ func (s *XiaohongshuService) StartRawVisibleWindow(ctx context.Context) error {
 }
 
 // PublishContent 发布内容
-func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
+func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (_ *PublishResponse, err error) {
+	ctx, span := s.startSpan(ctx, "PublishContent")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionPublishContent, Title: req.Title, ContentHash: audit.HashContent(req.Content)}, time.Now(), &err)
+
 	// 验证标题长度
 	// 小红书限制：最大40个单位长度
 	// 中文/日文/韩文占2个单位，英文/数字占1个单位
@@ -402,7 +705,11 @@ func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishReq
 }
 
 // SaveDraftContent 保存图文草稿（流程一致，最后点击“暂时离开”）
-func (s *XiaohongshuService) SaveDraftContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
+func (s *XiaohongshuService) SaveDraftContent(ctx context.Context, req *PublishRequest) (_ *PublishResponse, err error) {
+	ctx, span := s.startSpan(ctx, "SaveDraftContent")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionSaveDraft, Title: req.Title, ContentHash: audit.HashContent(req.Content)}, time.Now(), &err)
+
 	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
 		return nil, fmt.Errorf("标题长度超过限制")
 	}
@@ -439,15 +746,12 @@ func (s *XiaohongshuService) processImages(images []string) ([]string, error) {
 }
 
 // publishContent 执行内容发布
-func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) error {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) (err error) {
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action, err := xiaohongshu.NewPublishImageAction(page)
 	if err != nil {
@@ -458,15 +762,12 @@ func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohon
 	return action.Publish(ctx, content)
 }
 
-func (s *XiaohongshuService) saveDraftContent(ctx context.Context, content xiaohongshu.PublishImageContent) error {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) saveDraftContent(ctx context.Context, content xiaohongshu.PublishImageContent) (err error) {
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action, err := xiaohongshu.NewPublishImageAction(page)
 	if err != nil {
@@ -476,12 +777,40 @@ func (s *XiaohongshuService) saveDraftContent(ctx context.Context, content xiaoh
 	return action.SaveDraft(ctx, content)
 }
 
-// PublishContentScheduled 定时发布图文（默认当前时间+3天，精确到分钟）
-func (s *XiaohongshuService) PublishContentScheduled(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
+// defaultScheduleLead 未指定 publish_at 时的默认定时发布提前量
+const defaultScheduleLead = 72 * time.Hour
+
+// resolvePublishAt 解析并校验定时发布时间，为空时默认当前时间+3天（精确到分钟）
+func resolvePublishAt(raw string) (time.Time, error) {
+	now := time.Now()
+	if raw == "" {
+		return now.Add(defaultScheduleLead).Truncate(time.Minute), nil
+	}
+	when, err := xiaohongshu.ParseScheduleTime(raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := xiaohongshu.ValidateScheduleTime(when, now); err != nil {
+		return time.Time{}, err
+	}
+	return when, nil
+}
+
+// PublishContentScheduled 定时发布图文（publish_at 为空时默认当前时间+3天，精确到分钟）
+func (s *XiaohongshuService) PublishContentScheduled(ctx context.Context, req *PublishRequest) (_ *PublishResponse, err error) {
+	ctx, span := s.startSpan(ctx, "PublishContentScheduled")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionScheduleContent, Title: req.Title, ContentHash: audit.HashContent(req.Content)}, time.Now(), &err)
+
 	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
 		return nil, fmt.Errorf("标题长度超过限制")
 	}
 
+	when, err := schedulePublishAt(req.PublishAt, req.queued)
+	if err != nil {
+		return nil, err
+	}
+
 	imagePaths, err := s.processImages(req.Images)
 	if err != nil {
 		return nil, err
@@ -494,7 +823,6 @@ func (s *XiaohongshuService) PublishContentScheduled(ctx context.Context, req *P
 		ImagePaths: imagePaths,
 	}
 
-	when := time.Now().Add(72 * time.Hour).Truncate(time.Minute)
 	if err := s.publishContentScheduled(ctx, content, when); err != nil {
 		logrus.Errorf("定时发布失败: title=%s %v", content.Title, err)
 		return nil, err
@@ -505,19 +833,16 @@ func (s *XiaohongshuService) PublishContentScheduled(ctx context.Context, req *P
 		Content: req.Content,
 		Images:  len(imagePaths),
 		Status:  "定时发布已设置",
-		PostID:  when.Format("2006-01-02 15:04"),
+		PostID:  when.In(xiaohongshu.ScheduleLocation).Format("2006-01-02 15:04"),
 	}, nil
 }
 
-func (s *XiaohongshuService) publishContentScheduled(ctx context.Context, content xiaohongshu.PublishImageContent, when time.Time) error {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) publishContentScheduled(ctx context.Context, content xiaohongshu.PublishImageContent, when time.Time) (err error) {
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action, err := xiaohongshu.NewPublishImageAction(page)
 	if err != nil {
@@ -528,7 +853,11 @@ func (s *XiaohongshuService) publishContentScheduled(ctx context.Context, conten
 }
 
 // PublishVideo 发布视频（本地文件）
-func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
+func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideoRequest) (_ *PublishVideoResponse, err error) {
+	ctx, span := s.startSpan(ctx, "PublishVideo")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionPublishVideo, Title: req.Title, ContentHash: audit.HashContent(req.Content)}, time.Now(), &err)
+
 	// 标题长度校验
 	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
 		return nil, fmt.Errorf("标题长度超过限制")
@@ -565,7 +894,11 @@ func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideo
 }
 
 // SaveDraftVideo 保存视频草稿（流程一致，最后点击“暂时离开”）
-func (s *XiaohongshuService) SaveDraftVideo(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
+func (s *XiaohongshuService) SaveDraftVideo(ctx context.Context, req *PublishVideoRequest) (_ *PublishVideoResponse, err error) {
+	ctx, span := s.startSpan(ctx, "SaveDraftVideo")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionSaveDraftVideo, Title: req.Title, ContentHash: audit.HashContent(req.Content)}, time.Now(), &err)
+
 	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
 		return nil, fmt.Errorf("标题长度超过限制")
 	}
@@ -598,15 +931,12 @@ func (s *XiaohongshuService) SaveDraftVideo(ctx context.Context, req *PublishVid
 }
 
 // publishVideo 执行视频发布
-func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) error {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) (err error) {
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action, err := xiaohongshu.NewPublishVideoAction(page)
 	if err != nil {
@@ -616,15 +946,12 @@ func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongs
 	return action.PublishVideo(ctx, content)
 }
 
-func (s *XiaohongshuService) saveDraftVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) error {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) saveDraftVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) (err error) {
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action, err := xiaohongshu.NewPublishVideoAction(page)
 	if err != nil {
@@ -663,8 +990,12 @@ func toJSON(v any) string {
 	return string(b)
 }
 
-// PublishVideoScheduled 定时发布视频（默认当前时间+3天）
-func (s *XiaohongshuService) PublishVideoScheduled(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
+// PublishVideoScheduled 定时发布视频（publish_at 为空时默认当前时间+3天）
+func (s *XiaohongshuService) PublishVideoScheduled(ctx context.Context, req *PublishVideoRequest) (_ *PublishVideoResponse, err error) {
+	ctx, span := s.startSpan(ctx, "PublishVideoScheduled")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionScheduleVideo, Title: req.Title, ContentHash: audit.HashContent(req.Content)}, time.Now(), &err)
+
 	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
 		return nil, fmt.Errorf("标题长度超过限制")
 	}
@@ -676,6 +1007,11 @@ func (s *XiaohongshuService) PublishVideoScheduled(ctx context.Context, req *Pub
 		return nil, fmt.Errorf("视频文件不存在或不可访问: %v", err)
 	}
 
+	when, err := schedulePublishAt(req.PublishAt, req.queued)
+	if err != nil {
+		return nil, err
+	}
+
 	content := xiaohongshu.PublishVideoContent{
 		Title:     req.Title,
 		Content:   req.Content,
@@ -683,7 +1019,6 @@ func (s *XiaohongshuService) PublishVideoScheduled(ctx context.Context, req *Pub
 		VideoPath: req.Video,
 	}
 
-	when := time.Now().Add(72 * time.Hour).Truncate(time.Minute)
 	if err := s.publishVideoScheduled(ctx, content, when); err != nil {
 		return nil, err
 	}
@@ -693,20 +1028,17 @@ func (s *XiaohongshuService) PublishVideoScheduled(ctx context.Context, req *Pub
 		Content: req.Content,
 		Video:   req.Video,
 		Status:  "定时发布已设置",
-		PostID:  when.Format("2006-01-02 15:04"),
+		PostID:  when.In(xiaohongshu.ScheduleLocation).Format("2006-01-02 15:04"),
 	}
 	return resp, nil
 }
 
-func (s *XiaohongshuService) publishVideoScheduled(ctx context.Context, content xiaohongshu.PublishVideoContent, when time.Time) error {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) publishVideoScheduled(ctx context.Context, content xiaohongshu.PublishVideoContent, when time.Time) (err error) {
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action, err := xiaohongshu.NewPublishVideoAction(page)
 	if err != nil {
@@ -716,55 +1048,66 @@ func (s *XiaohongshuService) publishVideoScheduled(ctx context.Context, content
 	return action.PublishVideoScheduled(ctx, content, when)
 }
 
-// ListFeeds 获取Feeds列表
-func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse, error) {
-	b, err := s.newBrowser(ctx)
+// ListFeeds 获取Feeds列表，opts 为空时只返回首页首次加载的内容
+func (s *XiaohongshuService) ListFeeds(ctx context.Context, opts xiaohongshu.PageOptions) (_ *FeedsListResponse, err error) {
+	ctx, span := s.startSpan(ctx, "ListFeeds")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "list_feeds", time.Now(), &err)
+
+	if err := xiaohongshu.CheckFeedsCursor(opts.Cursor); err != nil {
+		return nil, err
+	}
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	// 创建 Feeds 列表 action
 	action := xiaohongshu.NewFeedsListAction(page)
 
 	// 获取 Feeds 列表
-	feeds, err := action.GetFeedsList(ctx)
+	result, err := action.GetFeedsPage(ctx, opts)
 	if err != nil {
 		logrus.Errorf("获取 Feeds 列表失败: %v", err)
 		return nil, err
 	}
 
 	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+		Feeds:      result.Feeds,
+		Count:      len(result.Feeds),
+		NextCursor: result.NextCursor,
 	}
 
 	return response, nil
 }
 
-func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, filters ...xiaohongshu.FilterOption) (*FeedsListResponse, error) {
-	b, err := s.newBrowser(ctx)
+// SearchFeeds 搜索Feeds，翻页时需使用相同的关键词与筛选条件
+func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, opts xiaohongshu.PageOptions, filters ...xiaohongshu.FilterOption) (_ *FeedsListResponse, err error) {
+	ctx, span := s.startSpan(ctx, "SearchFeeds")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "search_feeds", time.Now(), &err)
+
+	if err := xiaohongshu.CheckSearchCursor(opts.Cursor, keyword, filters...); err != nil {
+		return nil, err
+	}
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action := xiaohongshu.NewSearchAction(page)
 
-	feeds, err := action.Search(ctx, keyword, filters...)
+	result, err := action.SearchPage(ctx, keyword, opts, filters...)
 	if err != nil {
 		return nil, err
 	}
 
 	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+		Feeds:      result.Feeds,
+		Count:      len(result.Feeds),
+		NextCursor: result.NextCursor,
 	}
 
 	return response, nil
@@ -776,15 +1119,16 @@ func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToke
 }
 
 // GetFeedDetailWithConfig 使用配置获取Feed详情
-func (s *XiaohongshuService) GetFeedDetailWithConfig(ctx context.Context, feedID, xsecToken string, loadAllComments bool, config xiaohongshu.CommentLoadConfig) (*FeedDetailResponse, error) {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) GetFeedDetailWithConfig(ctx context.Context, feedID, xsecToken string, loadAllComments bool, config xiaohongshu.CommentLoadConfig) (_ *FeedDetailResponse, err error) {
+	ctx, span := s.startSpan(ctx, "GetFeedDetailWithConfig", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "get_feed_detail", time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	// 创建 Feed 详情 action
 	action := xiaohongshu.NewFeedDetailAction(page)
@@ -803,20 +1147,21 @@ func (s *XiaohongshuService) GetFeedDetailWithConfig(ctx context.Context, feedID
 	return response, nil
 }
 
-// UserProfile 获取用户信息
-func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
-	b, err := s.newBrowser(ctx)
+// UserProfile 获取用户信息，opts 控制滚动抓取主页笔记的数量与时间范围
+func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string, opts xiaohongshu.ProfileNotesOptions) (_ *UserProfileResponse, err error) {
+	ctx, span := s.startSpan(ctx, "UserProfile")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "user_profile", time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action := xiaohongshu.NewUserProfileAction(page)
 
-	result, err := action.UserProfile(ctx, userID, xsecToken)
+	result, err := action.UserProfile(ctx, userID, xsecToken, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -831,15 +1176,16 @@ func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken
 }
 
 // PostCommentToFeed 发表评论到Feed
-func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string) (*PostCommentResponse, error) {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string) (_ *PostCommentResponse, err error) {
+	ctx, span := s.startSpan(ctx, "PostCommentToFeed", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionComment, FeedID: feedID, ContentHash: audit.HashContent(content)}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionComment)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action := xiaohongshu.NewCommentFeedAction(page)
 
@@ -851,15 +1197,16 @@ func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsec
 }
 
 // LikeFeed 点赞笔记
-func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken string) (_ *ActionResult, err error) {
+	ctx, span := s.startSpan(ctx, "LikeFeed", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionLike, FeedID: feedID}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionLike)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action := xiaohongshu.NewLikeAction(page)
 	if err := action.Like(ctx, feedID, xsecToken); err != nil {
@@ -869,15 +1216,16 @@ func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken str
 }
 
 // UnlikeFeed 取消点赞笔记
-func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken string) (_ *ActionResult, err error) {
+	ctx, span := s.startSpan(ctx, "UnlikeFeed", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionUnlike, FeedID: feedID}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionLike)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action := xiaohongshu.NewLikeAction(page)
 	if err := action.Unlike(ctx, feedID, xsecToken); err != nil {
@@ -887,15 +1235,16 @@ func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken s
 }
 
 // FavoriteFeed 收藏笔记
-func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken string) (_ *ActionResult, err error) {
+	ctx, span := s.startSpan(ctx, "FavoriteFeed", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionFavorite, FeedID: feedID}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionFavorite)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action := xiaohongshu.NewFavoriteAction(page)
 	if err := action.Favorite(ctx, feedID, xsecToken); err != nil {
@@ -905,15 +1254,16 @@ func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken
 }
 
 // UnfavoriteFeed 取消收藏笔记
-func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecToken string) (_ *ActionResult, err error) {
+	ctx, span := s.startSpan(ctx, "UnfavoriteFeed", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionUnfavorite, FeedID: feedID}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionFavorite)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action := xiaohongshu.NewFavoriteAction(page)
 	if err := action.Unfavorite(ctx, feedID, xsecToken); err != nil {
@@ -923,15 +1273,16 @@ func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecTok
 }
 
 // ReplyCommentToFeed 回复指定评论
-func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xsecToken, commentID, userID, content string) (*ReplyCommentResponse, error) {
-	b, err := s.newBrowser(ctx)
+func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xsecToken, commentID, userID, content string) (_ *ReplyCommentResponse, err error) {
+	ctx, span := s.startSpan(ctx, "ReplyCommentToFeed", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionReply, FeedID: feedID, CommentID: commentID, UserID: userID, ContentHash: audit.HashContent(content)}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionReply)
 	if err != nil {
 		return nil, err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	action := xiaohongshu.NewCommentFeedAction(page)
 
@@ -980,7 +1331,10 @@ func (s *XiaohongshuService) newBrowser(ctx context.Context) (*browser.Browser,
 		Fingerprint: acc.Fingerprint,
 	}
 
-	return browser.New(cfg)
+	start := time.Now()
+	b, err := browser.New(cfg)
+	metrics.ObserveBrowserLaunch(err, time.Since(start))
+	return b, err
 }
 
 func (s *XiaohongshuService) resolveAccount(ctx context.Context) (*accounts.Account, error) {
@@ -1021,24 +1375,24 @@ func (s *XiaohongshuService) saveCookies(ctx context.Context, page *rod.Page) er
 	return nil
 }
 
-// withBrowserPage 执行需要浏览器页面的操作的通用函数
-func (s *XiaohongshuService) withBrowserPage(ctx context.Context, fn func(*rod.Page) error) error {
-	b, err := s.newBrowser(ctx)
+// withBrowserPage 执行需要浏览器页面的操作的通用函数，失败时保存现场
+func (s *XiaohongshuService) withBrowserPage(ctx context.Context, fn func(*rod.Page) error) (err error) {
+	page, release, err := s.acquirePage(ctx)
 	if err != nil {
 		return err
 	}
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	defer release(&err)
 
 	return fn(page)
 }
 
 // GetMyProfile 获取当前登录用户的个人信息
-func (s *XiaohongshuService) GetMyProfile(ctx context.Context) (*UserProfileResponse, error) {
+func (s *XiaohongshuService) GetMyProfile(ctx context.Context) (_ *UserProfileResponse, err error) {
+	ctx, span := s.startSpan(ctx, "GetMyProfile")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "my_profile", time.Now(), &err)
+
 	var result *xiaohongshu.UserProfileResponse
-	var err error
 
 	err = s.withBrowserPage(ctx, func(page *rod.Page) error {
 		action := xiaohongshu.NewUserProfileAction(page)
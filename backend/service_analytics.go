@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// NoteAnalyticsResponse 笔记数据列表响应
+type NoteAnalyticsResponse struct {
+	Notes []xiaohongshu.NoteMetrics `json:"notes"`
+	Count int                       `json:"count"`
+	// NextCursor 传给下一次请求的 cursor 以获取后续内容，为空表示没有更多
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListNoteAnalytics 分页列出当前账号各篇笔记的累计数据
+func (s *XiaohongshuService) ListNoteAnalytics(ctx context.Context, opts xiaohongshu.PageOptions) (_ *NoteAnalyticsResponse, err error) {
+	ctx, span := s.startSpan(ctx, "ListNoteAnalytics")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "list_note_analytics", time.Now(), &err)
+
+	if err := xiaohongshu.CheckNoteAnalyticsCursor(opts.Cursor); err != nil {
+		return nil, err
+	}
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	result, err := xiaohongshu.NewCreatorAnalyticsAction(page).Notes(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &NoteAnalyticsResponse{
+		Notes:      result.Notes,
+		Count:      len(result.Notes),
+		NextCursor: result.NextCursor,
+	}, nil
+}
+
+// GetNoteAnalytics 获取单篇笔记的累计数据
+func (s *XiaohongshuService) GetNoteAnalytics(ctx context.Context, noteID string) (_ *xiaohongshu.NoteMetrics, err error) {
+	ctx, span := s.startSpan(ctx, "GetNoteAnalytics", tracing.AttrFeedID.String(noteID))
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "get_note_analytics", time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	return xiaohongshu.NewCreatorAnalyticsAction(page).Note(ctx, noteID)
+}
+
+// GetAccountAnalytics 获取账号在 from~to（YYYY-MM-DD，北京时间）之间的每日趋势，为空时默认最近 7 天
+func (s *XiaohongshuService) GetAccountAnalytics(ctx context.Context, from, to string) (_ *xiaohongshu.AccountAnalytics, err error) {
+	ctx, span := s.startSpan(ctx, "GetAccountAnalytics")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "get_account_analytics", time.Now(), &err)
+
+	r, err := xiaohongshu.ParseDateRange(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	return xiaohongshu.NewCreatorAnalyticsAction(page).Account(ctx, r)
+}
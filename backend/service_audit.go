@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/apikey"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/metrics"
+	"github.com/xpzouying/xiaohongshu-mcp/session"
+)
+
+// AuditListResponse 审计记录查询响应
+type AuditListResponse struct {
+	Entries []audit.Entry `json:"entries"`
+	Count   int           `json:"count"`
+}
+
+// recordAudit 在操作结束时写入审计记录并记录操作指标，用法：defer s.recordAudit(ctx, entry, time.Now(), &err)。
+// 操作 panic 时记为失败后继续 panic，因此必须直接 defer 调用。
+func (s *XiaohongshuService) recordAudit(ctx context.Context, e audit.Entry, start time.Time, errp *error) {
+	r := recover()
+	err := actionError(r, errp)
+
+	e.Time = start
+	e.DurationMS = time.Since(start).Milliseconds()
+	e.AccountKey = s.auditAccountKey(ctx)
+	e.Caller = auditCaller(ctx)
+	e.Outcome = audit.OutcomeSuccess
+	if err != nil {
+		e.Outcome = audit.OutcomeFailure
+		e.Error = err.Error()
+	}
+	s.audit.Record(e)
+	metrics.ObserveAction(string(e.Action), e.AccountKey, err, time.Since(start))
+
+	if r != nil {
+		panic(r)
+	}
+}
+
+// actionError 操作的结果：panic 时为 panic 的值（rod 的 Must* 方法失败时 panic，此时 *errp 仍为空），否则为 *errp
+func actionError(recovered any, errp *error) error {
+	if recovered != nil {
+		return &jobs.PanicError{Value: recovered}
+	}
+	if errp != nil {
+		return *errp
+	}
+	return nil
+}
+
+// auditAccountKey 返回 ctx 对应的账号 key；与 resolveAccount 一致，未指定账号时为 1 号账号
+func (s *XiaohongshuService) auditAccountKey(ctx context.Context) string {
+	key := session.Account(ctx)
+	if key == "" || key == "default" {
+		if acc, err := s.accounts.Get(1); err == nil {
+			return acc.Key
+		}
+	}
+	return key
+}
+
+// auditCaller 返回操作发起方：API Key 名称，或后台任务、内容日历的标记
+func auditCaller(ctx context.Context) string {
+	if k := apikey.FromContext(ctx); k != nil {
+		return k.Name
+	}
+	return audit.Caller(ctx)
+}
+
+// QueryAudit 查询审计记录，最新的在前
+func (s *XiaohongshuService) QueryAudit(filter audit.Filter) (*AuditListResponse, error) {
+	entries, err := s.audit.Query(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditListResponse{Entries: entries, Count: len(entries)}, nil
+}
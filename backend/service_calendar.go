@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/calendar"
+	"github.com/xpzouying/xiaohongshu-mcp/concurrency"
+	"github.com/xpzouying/xiaohongshu-mcp/session"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// errPublishAtInPast 日历条目的发布时间早于当前时间
+var errPublishAtInPast = errors.New("publish_at 必须晚于当前时间")
+
+// CalendarEntryRequest 新增日历条目请求，images 与 video 二选一
+type CalendarEntryRequest struct {
+	AccountID int      `json:"account_id,omitempty"`
+	Title     string   `json:"title" binding:"required"`
+	Content   string   `json:"content" binding:"required"`
+	Images    []string `json:"images,omitempty"`
+	Video     string   `json:"video,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	PublishAt string   `json:"publish_at" binding:"required"`
+}
+
+// CalendarUpdateRequest 修改日历条目请求，未传的字段保持不变
+type CalendarUpdateRequest struct {
+	Title     *string  `json:"title,omitempty"`
+	Content   *string  `json:"content,omitempty"`
+	Images    []string `json:"images,omitempty"`
+	Video     *string  `json:"video,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	PublishAt *string  `json:"publish_at,omitempty"`
+}
+
+// CalendarListResponse 日历条目列表响应
+type CalendarListResponse struct {
+	Entries []calendar.Entry `json:"entries"`
+	Count   int              `json:"count"`
+}
+
+// parseCalendarTime 解析日历发布时间，不受平台定时发布窗口限制，只要求晚于当前时间
+func parseCalendarTime(raw string) (time.Time, error) {
+	when, err := xiaohongshu.ParseScheduleTime(raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !when.After(time.Now()) {
+		return time.Time{}, errors.Wrapf(errPublishAtInPast, "publish_at=%s", when.In(xiaohongshu.ScheduleLocation).Format("2006-01-02 15:04"))
+	}
+	return when, nil
+}
+
+func validateCalendarContent(title string, images []string, video string) (calendar.Kind, error) {
+	if titleWidth := runewidth.StringWidth(title); titleWidth > 40 {
+		return "", fmt.Errorf("标题长度超过限制")
+	}
+	switch {
+	case len(images) > 0 && video != "":
+		return "", fmt.Errorf("images 与 video 只能二选一")
+	case len(images) > 0:
+		return calendar.KindImage, nil
+	case video != "":
+		if _, err := os.Stat(video); err != nil {
+			return "", fmt.Errorf("视频文件不存在或不可访问: %v", err)
+		}
+		return calendar.KindVideo, nil
+	}
+	return "", fmt.Errorf("必须提供图片或本地视频文件")
+}
+
+// AddCalendarEntry 新增日历条目，到点后由服务端发布
+func (s *XiaohongshuService) AddCalendarEntry(ctx context.Context, accountID int, req *CalendarEntryRequest) (*calendar.Entry, error) {
+	kind, err := validateCalendarContent(req.Title, req.Images, req.Video)
+	if err != nil {
+		return nil, err
+	}
+	when, err := parseCalendarTime(req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.calendar.Add(calendar.Entry{
+		AccountID:  accountID,
+		AccountKey: session.Account(ctx),
+		Kind:       kind,
+		Title:      req.Title,
+		Content:    req.Content,
+		Images:     req.Images,
+		Video:      req.Video,
+		Tags:       req.Tags,
+		PublishAt:  when,
+	})
+}
+
+// UpdateCalendarEntry 修改待发布的日历条目
+func (s *XiaohongshuService) UpdateCalendarEntry(id string, req *CalendarUpdateRequest) (*calendar.Entry, error) {
+	current, err := s.calendar.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := calendar.Update{
+		Title:   req.Title,
+		Content: req.Content,
+		Images:  req.Images,
+		Video:   req.Video,
+		Tags:    req.Tags,
+	}
+
+	// 按修改后的内容重新校验
+	title, images, video := current.Title, current.Images, current.Video
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Images != nil {
+		images = req.Images
+	}
+	if req.Video != nil {
+		video = *req.Video
+	}
+	kind, err := validateCalendarContent(title, images, video)
+	if err != nil {
+		return nil, err
+	}
+	if kind != current.Kind {
+		return nil, fmt.Errorf("不能修改条目类型（%s）", current.Kind)
+	}
+
+	if req.PublishAt != nil {
+		when, err := parseCalendarTime(*req.PublishAt)
+		if err != nil {
+			return nil, err
+		}
+		update.PublishAt = &when
+	}
+
+	return s.calendar.Modify(id, update)
+}
+
+// MoveCalendarEntry 调整日历条目的发布时间
+func (s *XiaohongshuService) MoveCalendarEntry(id, publishAt string) (*calendar.Entry, error) {
+	when, err := parseCalendarTime(publishAt)
+	if err != nil {
+		return nil, err
+	}
+	return s.calendar.Move(id, when)
+}
+
+// CancelCalendarEntry 取消日历条目
+func (s *XiaohongshuService) CancelCalendarEntry(id string) (*calendar.Entry, error) {
+	return s.calendar.Cancel(id)
+}
+
+// GetCalendarEntry 查询日历条目
+func (s *XiaohongshuService) GetCalendarEntry(id string) (*calendar.Entry, error) {
+	return s.calendar.Get(id)
+}
+
+// ListCalendar 按条件列出日历条目
+func (s *XiaohongshuService) ListCalendar(filter calendar.Filter) *CalendarListResponse {
+	list := s.calendar.List(filter)
+	return &CalendarListResponse{Entries: list, Count: len(list)}
+}
+
+// runCalendarScheduler 在条目到达发布时间时发布内容，直到 ctx 结束。
+// 每次只领取一条条目；不同账号的条目并行发布，总并发由 limiter 控制，同一账号的条目依次发布。
+func (s *XiaohongshuService) runCalendarScheduler(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for ctx.Err() == nil {
+		for {
+			entry, ok := s.calendar.ClaimNext(time.Now())
+			if !ok {
+				break
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.publishCalendarEntry(ctx, entry)
+			}()
+		}
+
+		var t *time.Timer
+		var fire <-chan time.Time
+		if next, ok := s.calendar.NextDue(); ok {
+			t = time.NewTimer(time.Until(next))
+			fire = t.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.calendar.Changed():
+		case <-fire:
+		}
+		if t != nil {
+			t.Stop()
+		}
+	}
+}
+
+func (s *XiaohongshuService) publishCalendarEntry(ctx context.Context, entry calendar.Entry) {
+	logrus.Infof("calendar: publishing %s (%s) for %s, scheduled at %s", entry.ID, entry.Kind, entry.AccountKey, entry.PublishAt.Format(time.RFC3339))
+
+	// 后台发布不设排队超时，等同账号的其他操作结束
+	ctx = concurrency.WithWait(session.WithAccount(ctx, entry.AccountKey), concurrency.WaitForever)
+	ctx = audit.WithCaller(ctx, "calendar:"+entry.ID)
+
+	var (
+		result any
+		err    error
+	)
+	switch entry.Kind {
+	case calendar.KindVideo:
+		result, err = s.PublishVideo(ctx, &PublishVideoRequest{
+			AccountID: entry.AccountID,
+			Title:     entry.Title,
+			Content:   entry.Content,
+			Video:     entry.Video,
+			Tags:      entry.Tags,
+		})
+	default:
+		result, err = s.PublishContent(ctx, &PublishRequest{
+			AccountID: entry.AccountID,
+			Title:     entry.Title,
+			Content:   entry.Content,
+			Images:    entry.Images,
+			Tags:      entry.Tags,
+		})
+	}
+
+	if err != nil && ctx.Err() != nil && !errors.Is(err, xiaohongshu.ErrSubmitUncertain) {
+		// 服务退出时还没有点击发布，放回 pending，重启后重新发布
+		logrus.Warnf("calendar: publish %s interrupted before submit: %v", entry.ID, err)
+		if rerr := s.calendar.Release(entry.ID); rerr != nil {
+			logrus.Warnf("calendar: release %s failed: %v", entry.ID, rerr)
+		}
+		return
+	}
+	if err != nil {
+		logrus.Errorf("calendar: publish %s failed: %v", entry.ID, err)
+	}
+	if ferr := s.calendar.Finish(entry.ID, result, err); ferr != nil {
+		logrus.Warnf("calendar: persist result of %s failed: %v", entry.ID, ferr)
+	}
+}
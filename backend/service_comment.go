@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// LikeComment 点赞或取消点赞评论，计入点赞额度
+func (s *XiaohongshuService) LikeComment(ctx context.Context, feedID, xsecToken, commentID, userID string, unlike bool) (_ *CommentActionResult, err error) {
+	action, msg := audit.ActionLikeComment, "评论点赞成功或已点赞"
+	if unlike {
+		action, msg = audit.ActionUnlikeComment, "取消评论点赞成功或未点赞"
+	}
+	ctx, span := s.startSpan(ctx, "LikeComment", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: action, FeedID: feedID, CommentID: commentID, UserID: userID}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionLike)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	if err := xiaohongshu.NewCommentFeedAction(page).LikeComment(ctx, feedID, xsecToken, commentID, userID, unlike); err != nil {
+		return nil, err
+	}
+	return &CommentActionResult{FeedID: feedID, CommentID: commentID, UserID: userID, Success: true, Message: msg}, nil
+}
+
+// DeleteComment 删除自己的评论/回复，或自己笔记下他人的评论
+func (s *XiaohongshuService) DeleteComment(ctx context.Context, feedID, xsecToken, commentID string) (_ *CommentActionResult, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteComment", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionDeleteComment, FeedID: feedID, CommentID: commentID}, time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	if err := xiaohongshu.NewCommentFeedAction(page).DeleteComment(ctx, feedID, xsecToken, commentID); err != nil {
+		return nil, err
+	}
+	return &CommentActionResult{FeedID: feedID, CommentID: commentID, Success: true, Message: "评论删除成功"}, nil
+}
+
+// PinComment 在自己的笔记下置顶或取消置顶评论
+func (s *XiaohongshuService) PinComment(ctx context.Context, feedID, xsecToken, commentID, userID string, unpin bool) (_ *CommentActionResult, err error) {
+	action, msg := audit.ActionPinComment, "评论置顶成功或已置顶"
+	if unpin {
+		action, msg = audit.ActionUnpinComment, "取消评论置顶成功或未置顶"
+	}
+	ctx, span := s.startSpan(ctx, "PinComment", tracing.AttrFeedID.String(feedID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: action, FeedID: feedID, CommentID: commentID, UserID: userID}, time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	if err := xiaohongshu.NewCommentFeedAction(page).PinComment(ctx, feedID, xsecToken, commentID, userID, unpin); err != nil {
+		return nil, err
+	}
+	return &CommentActionResult{FeedID: feedID, CommentID: commentID, UserID: userID, Success: true, Message: msg}, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// CreatorNotesResponse 已发布笔记列表响应
+type CreatorNotesResponse struct {
+	Notes []xiaohongshu.CreatorNote `json:"notes"`
+	Count int                       `json:"count"`
+	// NextCursor 传给下一次请求的 cursor 以获取后续内容，为空表示没有更多
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListCreatorNotes 分页列出当前账号在创作者中心的已发布笔记
+func (s *XiaohongshuService) ListCreatorNotes(ctx context.Context, opts xiaohongshu.PageOptions) (_ *CreatorNotesResponse, err error) {
+	ctx, span := s.startSpan(ctx, "ListCreatorNotes")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "list_my_notes", time.Now(), &err)
+
+	if err := xiaohongshu.CheckCreatorNotesCursor(opts.Cursor); err != nil {
+		return nil, err
+	}
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	result, err := xiaohongshu.NewCreatorNotesAction(page).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &CreatorNotesResponse{
+		Notes:      result.Notes,
+		Count:      len(result.Notes),
+		NextCursor: result.NextCursor,
+	}, nil
+}
+
+// EditCreatorNote 修改已发布笔记的标题、正文或标签，修改后笔记会重新审核
+func (s *XiaohongshuService) EditCreatorNote(ctx context.Context, noteID string, req *EditNoteRequest) (_ *CreatorNoteResult, err error) {
+	ctx, span := s.startSpan(ctx, "EditCreatorNote", tracing.AttrFeedID.String(noteID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionEditNote, FeedID: noteID, Title: req.Title, ContentHash: audit.HashContent(req.Content)}, time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	content := xiaohongshu.EditNoteContent{Title: req.Title, Content: req.Content, Tags: req.Tags}
+	if err := xiaohongshu.NewCreatorNotesAction(page).Edit(ctx, noteID, content); err != nil {
+		return nil, err
+	}
+	return &CreatorNoteResult{NoteID: noteID, Success: true, Message: "笔记已更新"}, nil
+}
+
+// DeleteCreatorNote 删除已发布的笔记
+func (s *XiaohongshuService) DeleteCreatorNote(ctx context.Context, noteID string) (_ *CreatorNoteResult, err error) {
+	ctx, span := s.startSpan(ctx, "DeleteCreatorNote", tracing.AttrFeedID.String(noteID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionDeleteNote, FeedID: noteID}, time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	if err := xiaohongshu.NewCreatorNotesAction(page).Delete(ctx, noteID); err != nil {
+		return nil, err
+	}
+	return &CreatorNoteResult{NoteID: noteID, Success: true, Message: "笔记已删除"}, nil
+}
+
+// SetNoteVisibility 修改笔记可见范围：public 或 private
+func (s *XiaohongshuService) SetNoteVisibility(ctx context.Context, noteID, visibility string) (_ *CreatorNoteResult, err error) {
+	ctx, span := s.startSpan(ctx, "SetNoteVisibility", tracing.AttrFeedID.String(noteID))
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionNoteVisibility, FeedID: noteID}, time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	if err := xiaohongshu.NewCreatorNotesAction(page).SetVisibility(ctx, noteID, visibility); err != nil {
+		return nil, err
+	}
+	return &CreatorNoteResult{NoteID: noteID, Success: true, Message: "可见范围已修改为 " + visibility}, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/session"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// DraftListResponse 草稿箱列表响应
+type DraftListResponse struct {
+	Drafts []xiaohongshu.Draft `json:"drafts"`
+	Count  int                 `json:"count"`
+}
+
+// ListDrafts 列出当前账号草稿箱中的草稿
+func (s *XiaohongshuService) ListDrafts(ctx context.Context) (_ *DraftListResponse, err error) {
+	ctx, span := s.startSpan(ctx, "ListDrafts")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "list_drafts", time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	drafts, err := xiaohongshu.NewDraftsAction(page).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &DraftListResponse{Drafts: drafts, Count: len(drafts)}, nil
+}
+
+// EnqueuePublishDraft 校验参数后提交草稿发布任务，立即返回任务信息
+func (s *XiaohongshuService) EnqueuePublishDraft(ctx context.Context, req *PublishDraftRequest) (*jobs.Job, error) {
+	if req.DraftID == "" {
+		return nil, fmt.Errorf("缺少 draft_id")
+	}
+	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
+		return nil, fmt.Errorf("标题长度超过限制")
+	}
+	if err := normalizePublishAt(&req.PublishAt); err != nil {
+		return nil, err
+	}
+
+	return s.jobs.Enqueue(session.Account(ctx), jobTypePublishDraft, req)
+}
+
+// PublishDraft 打开草稿，按请求修改标题、正文或标签后发布；publish_at 非空时定时发布
+func (s *XiaohongshuService) PublishDraft(ctx context.Context, req *PublishDraftRequest) (_ *PublishDraftResult, err error) {
+	ctx, span := s.startSpan(ctx, "PublishDraft")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionPublishDraft, Title: req.Title, ContentHash: audit.HashContent(req.Content)}, time.Now(), &err)
+
+	var when time.Time
+	if req.PublishAt != "" {
+		if when, err = schedulePublishAt(req.PublishAt, req.queued); err != nil {
+			return nil, err
+		}
+	}
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	edits := xiaohongshu.EditNoteContent{Title: req.Title, Content: req.Content, Tags: req.Tags}
+	if err := xiaohongshu.NewDraftsAction(page).Publish(ctx, req.DraftID, edits, when); err != nil {
+		return nil, err
+	}
+
+	result := &PublishDraftResult{DraftID: req.DraftID, Success: true, Message: "草稿已发布"}
+	if !when.IsZero() {
+		result.Message = "草稿已设置定时发布"
+		result.PublishAt = when.In(xiaohongshu.ScheduleLocation).Format("2006-01-02 15:04")
+	}
+	return result, nil
+}
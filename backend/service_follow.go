@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// FollowListResponse 关注/粉丝列表响应
+type FollowListResponse struct {
+	Users []xiaohongshu.FollowUser `json:"users"`
+	Count int                      `json:"count"`
+	// NextCursor 传给下一次请求的 cursor 以获取后续内容，为空表示没有更多
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// FollowUser 关注用户，已关注时直接返回
+func (s *XiaohongshuService) FollowUser(ctx context.Context, userID, xsecToken string) (_ *FollowResult, err error) {
+	ctx, span := s.startSpan(ctx, "FollowUser")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionFollow, UserID: userID}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionFollow)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	status, err := xiaohongshu.NewFollowAction(page).Follow(ctx, userID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+	return &FollowResult{UserID: userID, Status: status, Success: true, Message: "关注成功或已关注"}, nil
+}
+
+// UnfollowUser 取消关注用户，未关注时直接返回
+func (s *XiaohongshuService) UnfollowUser(ctx context.Context, userID, xsecToken string) (_ *FollowResult, err error) {
+	ctx, span := s.startSpan(ctx, "UnfollowUser")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionUnfollow, UserID: userID}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionFollow)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	status, err := xiaohongshu.NewFollowAction(page).Unfollow(ctx, userID, xsecToken)
+	if err != nil {
+		return nil, err
+	}
+	return &FollowResult{UserID: userID, Status: status, Success: true, Message: "取消关注成功或未关注"}, nil
+}
+
+// ListFollows 分页获取当前账号的关注或粉丝列表
+func (s *XiaohongshuService) ListFollows(ctx context.Context, kind xiaohongshu.FollowListKind, opts xiaohongshu.PageOptions) (_ *FollowListResponse, err error) {
+	ctx, span := s.startSpan(ctx, "ListFollows")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "list_"+string(kind), time.Now(), &err)
+
+	if err := xiaohongshu.CheckFollowListCursor(opts.Cursor, kind); err != nil {
+		return nil, err
+	}
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	result, err := xiaohongshu.NewFollowAction(page).ListMine(ctx, kind, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &FollowListResponse{
+		Users:      result.Users,
+		Count:      len(result.Users),
+		NextCursor: result.NextCursor,
+	}, nil
+}
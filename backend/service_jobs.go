@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/concurrency"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/session"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 后台任务类型
+const (
+	jobTypePublishContent = "publish_content"
+	jobTypePublishVideo   = "publish_video"
+	jobTypePublishDraft   = "publish_draft"
+)
+
+// JobListResponse 任务列表响应
+type JobListResponse struct {
+	Jobs  []jobs.Job `json:"jobs"`
+	Count int        `json:"count"`
+}
+
+// registerJobHandlers 注册发布类任务的执行函数
+func (s *XiaohongshuService) registerJobHandlers() {
+	s.jobs.Register(jobTypePublishContent, func(ctx context.Context, job *jobs.Job) (any, error) {
+		var req PublishRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return nil, fmt.Errorf("解析任务参数失败: %w", err)
+		}
+		ctx = concurrency.WithWait(session.WithAccount(ctx, job.AccountKey), concurrency.WaitForever)
+		ctx = audit.WithCaller(ctx, "job:"+job.ID)
+		req.queued = true
+		if req.PublishAt != "" {
+			return s.PublishContentScheduled(ctx, &req)
+		}
+		return s.PublishContent(ctx, &req)
+	})
+
+	s.jobs.Register(jobTypePublishVideo, func(ctx context.Context, job *jobs.Job) (any, error) {
+		var req PublishVideoRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return nil, fmt.Errorf("解析任务参数失败: %w", err)
+		}
+		ctx = concurrency.WithWait(session.WithAccount(ctx, job.AccountKey), concurrency.WaitForever)
+		ctx = audit.WithCaller(ctx, "job:"+job.ID)
+		req.queued = true
+		if req.PublishAt != "" {
+			return s.PublishVideoScheduled(ctx, &req)
+		}
+		return s.PublishVideo(ctx, &req)
+	})
+
+	s.jobs.Register(jobTypePublishDraft, func(ctx context.Context, job *jobs.Job) (any, error) {
+		var req PublishDraftRequest
+		if err := json.Unmarshal(job.Payload, &req); err != nil {
+			return nil, fmt.Errorf("解析任务参数失败: %w", err)
+		}
+		ctx = concurrency.WithWait(session.WithAccount(ctx, job.AccountKey), concurrency.WaitForever)
+		ctx = audit.WithCaller(ctx, "job:"+job.ID)
+		req.queued = true
+		return s.PublishDraft(ctx, &req)
+	})
+}
+
+// EnqueuePublishContent 校验参数后提交图文发布任务，立即返回任务信息
+func (s *XiaohongshuService) EnqueuePublishContent(ctx context.Context, req *PublishRequest) (*jobs.Job, error) {
+	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
+		return nil, fmt.Errorf("标题长度超过限制")
+	}
+	if len(req.Images) == 0 {
+		return nil, fmt.Errorf("图片不能为空")
+	}
+	if err := normalizePublishAt(&req.PublishAt); err != nil {
+		return nil, err
+	}
+
+	return s.jobs.Enqueue(session.Account(ctx), jobTypePublishContent, req)
+}
+
+// EnqueuePublishVideo 校验参数后提交视频发布任务，立即返回任务信息
+func (s *XiaohongshuService) EnqueuePublishVideo(ctx context.Context, req *PublishVideoRequest) (*jobs.Job, error) {
+	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
+		return nil, fmt.Errorf("标题长度超过限制")
+	}
+	if req.Video == "" {
+		return nil, fmt.Errorf("必须提供本地视频文件")
+	}
+	if _, err := os.Stat(req.Video); err != nil {
+		return nil, fmt.Errorf("视频文件不存在或不可访问: %v", err)
+	}
+	if err := normalizePublishAt(&req.PublishAt); err != nil {
+		return nil, err
+	}
+
+	return s.jobs.Enqueue(session.Account(ctx), jobTypePublishVideo, req)
+}
+
+// normalizePublishAt 提交任务前校验定时发布时间，并统一保存为 RFC3339，避免执行时因时区产生歧义
+func normalizePublishAt(publishAt *string) error {
+	if *publishAt == "" {
+		return nil
+	}
+	when, err := resolvePublishAt(*publishAt)
+	if err != nil {
+		return err
+	}
+	*publishAt = when.Format(time.RFC3339)
+	return nil
+}
+
+// schedulePublishAt 返回定时发布时间。任务中的时间已在入队时按平台窗口校验并规范化，
+// 执行时只需确认仍满足最少提前量（排队或重试期间可能已不足，平台会拒绝）；否则按 resolvePublishAt 完整校验
+func schedulePublishAt(raw string, queued bool) (time.Time, error) {
+	if !queued {
+		return resolvePublishAt(raw)
+	}
+	when, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(xiaohongshu.ErrInvalidScheduleTime, "publish_at=%q", raw)
+	}
+	if !when.After(time.Now()) {
+		return time.Time{}, errors.Wrapf(errPublishAtInPast, "publish_at=%s", when.In(xiaohongshu.ScheduleLocation).Format("2006-01-02 15:04"))
+	}
+	if earliest := time.Now().Add(xiaohongshu.ScheduleMinLead); when.Before(earliest) {
+		return time.Time{}, errors.Wrapf(xiaohongshu.ErrScheduleOutOfRange, "%s 距现在已不足 %s",
+			when.In(xiaohongshu.ScheduleLocation).Format("2006-01-02 15:04"), xiaohongshu.ScheduleMinLead)
+	}
+	return when, nil
+}
+
+// isPublishAtError 判断是否为 publish_at 参数错误
+func isPublishAtError(err error) bool {
+	return errors.Is(err, xiaohongshu.ErrInvalidScheduleTime) ||
+		errors.Is(err, xiaohongshu.ErrScheduleOutOfRange) ||
+		errors.Is(err, errPublishAtInPast)
+}
+
+// GetJob 查询任务状态
+func (s *XiaohongshuService) GetJob(id string) (*jobs.Job, error) {
+	return s.jobs.Get(id)
+}
+
+// ListJobs 按条件列出任务
+func (s *XiaohongshuService) ListJobs(filter jobs.Filter) *JobListResponse {
+	list := s.jobs.List(filter)
+	return &JobListResponse{Jobs: list, Count: len(list)}
+}
+
+// isResumableError 判断服务退出时中断的任务能否在重启后重新执行：已经点击发布的不能
+func isResumableError(err error) bool {
+	return !errors.Is(err, xiaohongshu.ErrSubmitUncertain)
+}
+
+// isTransientError 判断发布失败是否由可恢复的浏览器/网络问题导致，用于任务重试。
+// 点击发布之后的失败一律不重试，避免同一篇笔记发出两次
+func isTransientError(err error) bool {
+	if err == nil || errors.Is(err, xiaohongshu.ErrSubmitUncertain) {
+		return false
+	}
+
+	var (
+		panicErr    *jobs.PanicError
+		navErr      *rod.NavigationError
+		notFoundErr *rod.ElementNotFoundError
+		objErr      *rod.ObjectNotFoundError
+		netErr      net.Error
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), concurrency.IsBusy(err):
+		return true
+	case errors.As(err, &navErr), errors.As(err, &notFoundErr), errors.As(err, &objErr):
+		return true
+	case errors.As(err, &netErr):
+		return true
+	case errors.As(err, &panicErr):
+		// rod 的 Must* 方法在超时、元素缺失等情况下直接 panic
+		return true
+	}
+	return false
+}
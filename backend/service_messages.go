@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/quota"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// ConversationListResponse 私信会话列表响应
+type ConversationListResponse struct {
+	Conversations []xiaohongshu.Conversation `json:"conversations"`
+	Count         int                        `json:"count"`
+	// NextCursor 传给下一次请求的 cursor 以获取后续内容，为空表示没有更多
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// MessageListResponse 会话消息列表响应
+type MessageListResponse struct {
+	UserID   string                `json:"user_id"`
+	Messages []xiaohongshu.Message `json:"messages"`
+	Count    int                   `json:"count"`
+	// NextCursor 传给下一次请求的 cursor 以获取更早的消息，为空表示没有更多
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListConversations 分页列出当前账号的私信会话
+func (s *XiaohongshuService) ListConversations(ctx context.Context, opts xiaohongshu.PageOptions) (_ *ConversationListResponse, err error) {
+	ctx, span := s.startSpan(ctx, "ListConversations")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "list_conversations", time.Now(), &err)
+
+	if err := xiaohongshu.CheckConversationsCursor(opts.Cursor); err != nil {
+		return nil, err
+	}
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	result, err := xiaohongshu.NewMessagesAction(page).Conversations(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ConversationListResponse{
+		Conversations: result.Conversations,
+		Count:         len(result.Conversations),
+		NextCursor:    result.NextCursor,
+	}, nil
+}
+
+// GetMessages 分页读取与某个用户的私信，从新到旧
+func (s *XiaohongshuService) GetMessages(ctx context.Context, userID string, opts xiaohongshu.PageOptions) (_ *MessageListResponse, err error) {
+	ctx, span := s.startSpan(ctx, "GetMessages")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "get_messages", time.Now(), &err)
+
+	if err := xiaohongshu.CheckMessagesCursor(opts.Cursor, userID); err != nil {
+		return nil, err
+	}
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	result, err := xiaohongshu.NewMessagesAction(page).Messages(ctx, userID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageListResponse{
+		UserID:     userID,
+		Messages:   result.Messages,
+		Count:      len(result.Messages),
+		NextCursor: result.NextCursor,
+	}, nil
+}
+
+// SendMessage 在已有会话中给对方发送一条文字私信
+func (s *XiaohongshuService) SendMessage(ctx context.Context, userID, content string) (_ *SendMessageResult, err error) {
+	ctx, span := s.startSpan(ctx, "SendMessage")
+	defer tracing.End(span, &err)
+	defer s.recordAudit(ctx, audit.Entry{Action: audit.ActionSendMessage, UserID: userID, ContentHash: audit.HashContent(content)}, time.Now(), &err)
+
+	page, release, err := s.acquireActionPage(ctx, quota.ActionMessage)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	msg, err := xiaohongshu.NewMessagesAction(page).Send(ctx, userID, content)
+	if err != nil {
+		return nil, err
+	}
+	return &SendMessageResult{UserID: userID, MessageID: msg.MessageID, Success: true, Message: "私信发送成功"}, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/metrics"
+)
+
+// observeAction 在只读操作结束时记录指标，用法：defer s.observeAction(ctx, "search_feeds", time.Now(), &err)。
+// 写操作的指标随审计记录一起记录，见 recordAudit。操作 panic 时记为失败后继续 panic，因此必须直接 defer 调用。
+func (s *XiaohongshuService) observeAction(ctx context.Context, action string, start time.Time, errp *error) {
+	r := recover()
+	metrics.ObserveAction(action, s.auditAccountKey(ctx), actionError(r, errp), time.Since(start))
+	if r != nil {
+		panic(r)
+	}
+}
+
+// MetricsHandler 返回 /metrics 的处理器，抓取时读取常驻浏览器、浏览器池与账号登录状态
+func (s *XiaohongshuService) MetricsHandler() http.Handler {
+	return metrics.Handler(metrics.State{
+		LiveBrowsers: func() int {
+			s.liveMu.Lock()
+			defer s.liveMu.Unlock()
+			return len(s.liveByAccount)
+		},
+		Pool: s.pool.Stats,
+		Accounts: func() []metrics.AccountStatus {
+			var out []metrics.AccountStatus
+			for _, acc := range s.accounts.List() {
+				out = append(out, metrics.AccountStatus{Key: acc.Key, LoggedIn: acc.LoggedIn})
+			}
+			return out
+		},
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/inbox"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// NotificationQuery 通知列表参数
+type NotificationQuery struct {
+	Kind xiaohongshu.NotificationKind
+	xiaohongshu.PageOptions
+	// OnlyNew 只返回已读位置之后的通知
+	OnlyNew bool
+	// MarkSeen 本次已返回全部新通知（没有下一页）时，把已读位置推进到最新一条
+	MarkSeen bool
+}
+
+// pageOptions 只取首屏时总会返回下一页游标，已读位置永远不会推进；
+// 轮询新通知（OnlyNew 或 MarkSeen）未指定 limit 与 cursor 时按默认条数滚动加载，到底后即可标记已读
+func (q NotificationQuery) pageOptions() xiaohongshu.PageOptions {
+	opts := q.PageOptions
+	if (q.OnlyNew || q.MarkSeen) && opts.Limit <= 0 && opts.Cursor == "" {
+		opts.Limit = xiaohongshu.DefaultPageLimit
+	}
+	return opts
+}
+
+// NotificationListResponse 通知列表响应
+type NotificationListResponse struct {
+	Notifications []xiaohongshu.Notification `json:"notifications"`
+	Count         int                        `json:"count"`
+	// NextCursor 传给下一次请求的 cursor 以获取后续内容，为空表示没有更多
+	NextCursor string `json:"next_cursor,omitempty"`
+	// LastSeen 请求结束时的已读位置，从未标记时为空
+	LastSeen *inbox.Watermark `json:"last_seen,omitempty"`
+}
+
+// ListNotifications 读取当前账号某类通知，可只取已读位置之后的新通知并推进已读位置
+func (s *XiaohongshuService) ListNotifications(ctx context.Context, q NotificationQuery) (_ *NotificationListResponse, err error) {
+	ctx, span := s.startSpan(ctx, "ListNotifications")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "get_notifications", time.Now(), &err)
+
+	if err := xiaohongshu.CheckNotificationsCursor(q.Cursor, q.Kind); err != nil {
+		return nil, err
+	}
+	acc, err := s.resolveAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mark := s.inbox.Get(acc.Key, string(q.Kind))
+	var skip func(xiaohongshu.Notification) bool
+	if q.OnlyNew {
+		skip = func(n xiaohongshu.Notification) bool { return mark.Covers(time.Unix(n.Timestamp, 0), n.ID) }
+	}
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	result, err := xiaohongshu.NewNotificationsAction(page).List(ctx, q.Kind, q.pageOptions(), skip)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.MarkSeen && result.NextCursor == "" {
+		if mark, err = s.inbox.Advance(acc.Key, string(q.Kind), newestWatermark(result.Loaded)); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &NotificationListResponse{
+		Notifications: result.Notifications,
+		Count:         len(result.Notifications),
+		NextCursor:    result.NextCursor,
+	}
+	if !mark.IsZero() {
+		resp.LastSeen = &mark
+	}
+	return resp, nil
+}
+
+// newestWatermark 取通知中最新一秒的时间与该秒内所有通知的 ID
+func newestWatermark(items []xiaohongshu.Notification) inbox.Watermark {
+	var w inbox.Watermark
+	for _, n := range items {
+		t := time.Unix(n.Timestamp, 0)
+		switch {
+		case t.After(w.Time):
+			w = inbox.Watermark{Time: t, IDs: []string{n.ID}}
+		case t.Equal(w.Time):
+			w.IDs = append(w.IDs, n.ID)
+		}
+	}
+	return w
+}
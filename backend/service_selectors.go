@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// CheckSelectors 用当前账号依次打开 pages，报告哪些选择器已经匹配不到
+func (s *XiaohongshuService) CheckSelectors(ctx context.Context, pages []string) (_ *xiaohongshu.SelectorReport, err error) {
+	ctx, span := s.startSpan(ctx, "CheckSelectors")
+	defer tracing.End(span, &err)
+	defer s.observeAction(ctx, "check_selectors", time.Now(), &err)
+
+	page, release, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release(&err)
+
+	return xiaohongshu.NewSelectorCheckAction(page).Check(ctx, pages), nil
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan 为服务方法开启 span 并带上账号，用法：
+//
+//	ctx, span := s.startSpan(ctx, "SearchFeeds")
+//	defer tracing.End(span, &err)
+func (s *XiaohongshuService) startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, tracing.AttrAccount.String(s.auditAccountKey(ctx)))
+	return tracing.Start(ctx, "XiaohongshuService."+method, attrs...)
+}
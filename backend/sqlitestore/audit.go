@@ -0,0 +1,117 @@
+package sqlitestore
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+)
+
+// AuditStore 将审计记录保存在 audit_log 表中，实现 audit.Store
+type AuditStore struct {
+	store   *Store
+	maxRows int
+}
+
+var _ audit.Store = (*AuditStore)(nil)
+
+// OpenAudit 打开数据库用于保存审计记录；maxRows > 0 时只保留最新的 maxRows 条
+func OpenAudit(path string, maxRows int) (*AuditStore, error) {
+	s, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditStore{store: s, maxRows: maxRows}, nil
+}
+
+// Append 写入一条记录，并删除超出保留条数的旧记录
+func (a *AuditStore) Append(e audit.Entry) error {
+	return a.store.tx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`INSERT INTO audit_log (id, at, account_key, action, feed_id, comment_id, user_id,
+			title, content_hash, caller, outcome, error, duration_ms)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			e.ID, e.Time.UnixNano(), e.AccountKey, string(e.Action), e.FeedID, e.CommentID, e.UserID,
+			e.Title, e.ContentHash, e.Caller, string(e.Outcome), e.Error, e.DurationMS,
+		); err != nil {
+			return errors.Wrapf(err, "append audit %s", e.ID)
+		}
+		if a.maxRows > 0 {
+			if _, err := tx.Exec(`DELETE FROM audit_log WHERE seq <= (SELECT MAX(seq) FROM audit_log) - ?`, a.maxRows); err != nil {
+				return errors.Wrap(err, "prune audit log")
+			}
+		}
+		return nil
+	})
+}
+
+// Query 按条件查询，最新的记录在前
+func (a *AuditStore) Query(f audit.Filter) ([]audit.Entry, error) {
+	var (
+		where []string
+		args  []any
+	)
+	for _, c := range []struct {
+		column string
+		value  string
+	}{
+		{"account_key", f.AccountKey},
+		{"action", string(f.Action)},
+		{"feed_id", f.FeedID},
+		{"content_hash", f.ContentHash},
+		{"caller", f.Caller},
+		{"outcome", string(f.Outcome)},
+	} {
+		if c.value != "" {
+			where = append(where, c.column+" = ?")
+			args = append(args, c.value)
+		}
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "at >= ?")
+		args = append(args, f.Since.UnixNano())
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "at < ?")
+		args = append(args, f.Until.UnixNano())
+	}
+	query := `SELECT id, at, account_key, action, feed_id, comment_id, user_id, title, content_hash,
+		caller, outcome, error, duration_ms FROM audit_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	query += " ORDER BY at DESC, seq DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := a.store.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []audit.Entry{}
+	for rows.Next() {
+		var (
+			e               audit.Entry
+			at              int64
+			action, outcome string
+		)
+		if err := rows.Scan(&e.ID, &at, &e.AccountKey, &action, &e.FeedID, &e.CommentID, &e.UserID,
+			&e.Title, &e.ContentHash, &e.Caller, &outcome, &e.Error, &e.DurationMS); err != nil {
+			return nil, err
+		}
+		e.Time = time.Unix(0, at)
+		e.Action, e.Outcome = audit.Action(action), audit.Outcome(outcome)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Close 关闭数据库
+func (a *AuditStore) Close() error {
+	return a.store.Close()
+}
@@ -0,0 +1,4 @@
+package sqlitestore
+
+// 纯 Go 的 SQLite 驱动，不依赖 cgo
+import _ "modernc.org/sqlite"
@@ -0,0 +1,76 @@
+package sqlitestore
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+)
+
+// ImportResult 导入统计
+type ImportResult struct {
+	Accounts int `json:"accounts"`
+	Cookies  int `json:"cookies"`
+	Logins   int `json:"logins"`
+}
+
+// ImportJSON 将 accounts.json 与各账号的 cookies 文件导入数据库。
+// 同 ID 的账号会被覆盖，重复执行结果相同；cookies 按原始字节复制，已加密的保持加密。
+func (s *Store) ImportJSON(storePath string) (*ImportResult, error) {
+	src := accounts.NewJSONStore(storePath)
+	defer src.Close()
+	nextID, list, err := src.Load()
+	if err != nil {
+		return nil, errors.Wrapf(err, "load %s", storePath)
+	}
+
+	res := &ImportResult{}
+	cookiePaths := []string{cookies.GetCookiesFilePathForAccount("default")}
+	for _, acc := range list {
+		if err := s.SaveAccount(acc, nextID); err != nil {
+			return nil, err
+		}
+		res.Accounts++
+
+		history, err := src.LoginHistory(acc.Key, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(history) == 0 && !acc.LastLogin.IsZero() {
+			history = append(history, acc.LastLogin)
+		}
+		if _, err := s.db.Exec(`DELETE FROM login_history WHERE account_key = ?`, acc.Key); err != nil {
+			return nil, err
+		}
+		for _, at := range history {
+			if err := s.RecordLogin(acc.Key, at); err != nil {
+				return nil, err
+			}
+			res.Logins++
+		}
+		if acc.CookiePath != "" {
+			cookiePaths = append(cookiePaths, acc.CookiePath)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, path := range cookiePaths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		data, err := cookies.NewFileCookie(path).LoadCookies()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if err := s.Cookie(path).SaveCookies(data); err != nil {
+			return nil, err
+		}
+		res.Cookies++
+	}
+	return res, nil
+}
@@ -0,0 +1,321 @@
+// Package sqlitestore 将账号、cookies、指纹与登录历史保存在单个 SQLite 数据库中，
+// 用于替代 accounts.json + 每账号 cookies 文件，适合大量账号的场景。
+//
+// 数据库驱动使用纯 Go 实现的 modernc.org/sqlite，不依赖 cgo。
+package sqlitestore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
+	"github.com/xpzouying/xiaohongshu-mcp/session"
+)
+
+// driverName 为 modernc.org/sqlite 注册的驱动名
+const driverName = "sqlite"
+
+const schema = `
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS accounts (
+	id           INTEGER PRIMARY KEY,
+	key          TEXT NOT NULL UNIQUE,
+	name         TEXT NOT NULL DEFAULT '',
+	proxy        TEXT NOT NULL DEFAULT '',
+	proxy_type   TEXT NOT NULL DEFAULT '',
+	proxy_host   TEXT NOT NULL DEFAULT '',
+	proxy_port   INTEGER NOT NULL DEFAULT 0,
+	proxy_user   TEXT NOT NULL DEFAULT '',
+	proxy_pass   TEXT NOT NULL DEFAULT '',
+	fingerprint  TEXT NOT NULL DEFAULT '',
+	cookie_path  TEXT NOT NULL DEFAULT '',
+	profile_path TEXT NOT NULL DEFAULT '',
+	logged_in    INTEGER NOT NULL DEFAULT 0,
+	last_login   INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS cookies (
+	path       TEXT PRIMARY KEY,
+	data       BLOB NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS login_history (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	account_key TEXT NOT NULL,
+	at          INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS login_history_account ON login_history (account_key, at);
+CREATE TABLE IF NOT EXISTS audit_log (
+	seq          INTEGER PRIMARY KEY AUTOINCREMENT,
+	id           TEXT NOT NULL UNIQUE,
+	at           INTEGER NOT NULL,
+	account_key  TEXT NOT NULL,
+	action       TEXT NOT NULL,
+	feed_id      TEXT NOT NULL DEFAULT '',
+	comment_id   TEXT NOT NULL DEFAULT '',
+	user_id      TEXT NOT NULL DEFAULT '',
+	title        TEXT NOT NULL DEFAULT '',
+	content_hash TEXT NOT NULL DEFAULT '',
+	caller       TEXT NOT NULL DEFAULT '',
+	outcome      TEXT NOT NULL,
+	error        TEXT NOT NULL DEFAULT '',
+	duration_ms  INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS audit_log_account ON audit_log (account_key, at);
+CREATE INDEX IF NOT EXISTS audit_log_feed ON audit_log (feed_id);
+CREATE INDEX IF NOT EXISTS audit_log_content ON audit_log (content_hash);
+`
+
+// Store 同时实现 accounts.Store 与 cookies 的存储后端
+type Store struct {
+	db *sql.DB
+}
+
+var _ accounts.Store = (*Store)(nil)
+
+// Open 打开（不存在时创建）数据库文件并初始化表结构
+func Open(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	db, err := sql.Open(driverName, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "open %s", path)
+	}
+	// SQLite 只允许一个写者，单连接可以避免 SQLITE_BUSY，也让 PRAGMA 对所有语句生效
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"PRAGMA journal_mode = WAL",
+		"PRAGMA busy_timeout = 5000",
+		"PRAGMA foreign_keys = ON",
+		schema,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, errors.Wrapf(err, "init %s", path)
+		}
+	}
+	return &Store{db: db}, nil
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Load 读取全部账号，代理凭据按 secret.Default() 解密；
+// 启用加密后遇到明文凭据会立即加密写回。
+func (s *Store) Load() (int, []*accounts.Account, error) {
+	nextID := 1
+	var v string
+	err := s.db.QueryRow(`SELECT value FROM meta WHERE key = 'next_id'`).Scan(&v)
+	switch {
+	case err == nil:
+		if nextID, err = strconv.Atoi(v); err != nil {
+			return 0, nil, errors.Wrap(err, "parse next_id")
+		}
+	case errors.Is(err, sql.ErrNoRows):
+	default:
+		return 0, nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT id, key, name, proxy, proxy_type, proxy_host, proxy_port,
+		proxy_user, proxy_pass, fingerprint, cookie_path, profile_path, logged_in, last_login
+		FROM accounts ORDER BY id`)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	box := secret.Default()
+	var (
+		list      []*accounts.Account
+		plaintext []*accounts.Account
+	)
+	for rows.Next() {
+		var (
+			acc       accounts.Account
+			fp        string
+			loggedIn  bool
+			lastLogin int64
+		)
+		if err := rows.Scan(&acc.ID, &acc.Key, &acc.Name, &acc.Proxy, &acc.ProxyType, &acc.ProxyHost,
+			&acc.ProxyPort, &acc.ProxyUser, &acc.ProxyPass, &fp, &acc.CookiePath, &acc.ProfilePath,
+			&loggedIn, &lastLogin); err != nil {
+			return 0, nil, err
+		}
+		if fp != "" {
+			acc.Fingerprint = &session.Fingerprint{}
+			if err := json.Unmarshal([]byte(fp), acc.Fingerprint); err != nil {
+				return 0, nil, errors.Wrapf(err, "parse fingerprint of %s", acc.Key)
+			}
+		}
+		acc.LoggedIn = loggedIn
+		if lastLogin > 0 {
+			acc.LastLogin = time.Unix(0, lastLogin)
+		}
+		sealed := (acc.Proxy == "" || secret.IsSealed([]byte(acc.Proxy))) &&
+			(acc.ProxyPass == "" || secret.IsSealed([]byte(acc.ProxyPass)))
+		if err := accounts.OpenAccount(&acc, box); err != nil {
+			return 0, nil, err
+		}
+		list = append(list, &acc)
+		if !sealed {
+			plaintext = append(plaintext, &acc)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+	rows.Close()
+
+	if box != nil {
+		for _, acc := range plaintext {
+			if err := s.SaveAccount(acc, nextID); err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+	return nextID, list, nil
+}
+
+// SaveAccount 插入或更新账号，并更新下一个账号 ID
+func (s *Store) SaveAccount(acc *accounts.Account, nextID int) error {
+	sealed, err := accounts.SealAccount(acc, secret.Default())
+	if err != nil {
+		return err
+	}
+	var fp string
+	if acc.Fingerprint != nil {
+		data, err := json.Marshal(acc.Fingerprint)
+		if err != nil {
+			return err
+		}
+		fp = string(data)
+	}
+	var lastLogin int64
+	if !acc.LastLogin.IsZero() {
+		lastLogin = acc.LastLogin.UnixNano()
+	}
+
+	return s.tx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`INSERT INTO accounts (id, key, name, proxy, proxy_type, proxy_host, proxy_port,
+			proxy_user, proxy_pass, fingerprint, cookie_path, profile_path, logged_in, last_login)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET key = excluded.key, name = excluded.name, proxy = excluded.proxy,
+				proxy_type = excluded.proxy_type, proxy_host = excluded.proxy_host, proxy_port = excluded.proxy_port,
+				proxy_user = excluded.proxy_user, proxy_pass = excluded.proxy_pass, fingerprint = excluded.fingerprint,
+				cookie_path = excluded.cookie_path, profile_path = excluded.profile_path,
+				logged_in = excluded.logged_in, last_login = excluded.last_login`,
+			sealed.ID, sealed.Key, sealed.Name, sealed.Proxy, sealed.ProxyType, sealed.ProxyHost, sealed.ProxyPort,
+			sealed.ProxyUser, sealed.ProxyPass, fp, sealed.CookiePath, sealed.ProfilePath, sealed.LoggedIn, lastLogin,
+		); err != nil {
+			return errors.Wrapf(err, "save account %s", acc.Key)
+		}
+		return setNextID(tx, nextID)
+	})
+}
+
+// DeleteAccount 删除账号及其登录历史，cookies 由调用方通过 cookies 后端删除
+func (s *Store) DeleteAccount(id int, nextID int) error {
+	return s.tx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM login_history WHERE account_key = (SELECT key FROM accounts WHERE id = ?)`, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM accounts WHERE id = ?`, id); err != nil {
+			return err
+		}
+		return setNextID(tx, nextID)
+	})
+}
+
+// RecordLogin 记录一次成功登录
+func (s *Store) RecordLogin(key string, at time.Time) error {
+	_, err := s.db.Exec(`INSERT INTO login_history (account_key, at) VALUES (?, ?)`, key, at.UnixNano())
+	return err
+}
+
+// LoginHistory 返回最近的登录时间，按时间倒序；limit <= 0 表示不限制
+func (s *Store) LoginHistory(key string, limit int) ([]time.Time, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.Query(`SELECT at FROM login_history WHERE account_key = ? ORDER BY at DESC, id DESC LIMIT ?`, key, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []time.Time
+	for rows.Next() {
+		var at int64
+		if err := rows.Scan(&at); err != nil {
+			return nil, err
+		}
+		out = append(out, time.Unix(0, at))
+	}
+	return out, rows.Err()
+}
+
+// Cookie 返回以 path 为键存放在数据库中的 Cookier，可作为 cookies.SetBackend 的参数。
+// 读写的是原始字节，加密由 cookies.NewLoadCookie 外层负责。
+func (s *Store) Cookie(path string) cookies.Cookier {
+	return &dbCookie{db: s.db, path: path}
+}
+
+type dbCookie struct {
+	db   *sql.DB
+	path string
+}
+
+func (c *dbCookie) LoadCookies() ([]byte, error) {
+	var data []byte
+	err := c.db.QueryRow(`SELECT data FROM cookies WHERE path = ?`, c.path).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrapf(os.ErrNotExist, "cookies %s", c.path)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cookies from db")
+	}
+	return data, nil
+}
+
+func (c *dbCookie) SaveCookies(data []byte) error {
+	_, err := c.db.Exec(`INSERT INTO cookies (path, data, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (path) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`,
+		c.path, data, time.Now().UnixNano())
+	return errors.Wrap(err, "failed to save cookies to db")
+}
+
+func (c *dbCookie) DeleteCookies() error {
+	_, err := c.db.Exec(`DELETE FROM cookies WHERE path = ?`, c.path)
+	return err
+}
+
+func (s *Store) tx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func setNextID(tx *sql.Tx, nextID int) error {
+	_, err := tx.Exec(`INSERT INTO meta (key, value) VALUES ('next_id', ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, strconv.Itoa(nextID))
+	return err
+}
@@ -0,0 +1,147 @@
+package sqlitestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "xhs.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStoreAccounts(t *testing.T) {
+	s := openTestStore(t)
+	t.Cleanup(func() { secret.SetDefault(nil) })
+	key, err := secret.GenerateKey()
+	require.NoError(t, err)
+	raw, err := secret.ParseKey(key)
+	require.NoError(t, err)
+	box, err := secret.NewBox(raw)
+	require.NoError(t, err)
+	secret.SetDefault(box)
+
+	m, err := accounts.NewManagerWithStore(s, t.TempDir())
+	require.NoError(t, err)
+	acc, err := m.Create("", "a")
+	require.NoError(t, err)
+	_, err = m.ApplyProxyConfig(acc.ID, accounts.ProxyConfig{Type: "http", Host: "127.0.0.1", Port: 8080, User: "u", Pass: "p@ss"})
+	require.NoError(t, err)
+	m.MarkLoggedIn(acc.Key)
+	second, err := m.Create("", "b")
+	require.NoError(t, err)
+	require.NoError(t, m.Delete(second.ID))
+
+	var stored string
+	require.NoError(t, s.db.QueryRow(`SELECT proxy_pass FROM accounts WHERE id = ?`, acc.ID).Scan(&stored))
+	assert.True(t, secret.IsSealed([]byte(stored)))
+
+	reloaded, err := accounts.NewManagerWithStore(s, t.TempDir())
+	require.NoError(t, err)
+	got, err := reloaded.Get(acc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "p@ss", got.ProxyPass)
+	assert.Equal(t, acc.Fingerprint, got.Fingerprint)
+	assert.True(t, got.LoggedIn)
+	_, err = reloaded.Get(second.ID)
+	assert.Error(t, err)
+
+	// 已删除账号的 ID 不会被复用
+	third, err := reloaded.Create("", "c")
+	require.NoError(t, err)
+	assert.Equal(t, second.ID+1, third.ID)
+
+	logins, err := reloaded.LoginHistory(acc.Key, 10)
+	require.NoError(t, err)
+	assert.Len(t, logins, 1)
+}
+
+func TestStoreCookies(t *testing.T) {
+	s := openTestStore(t)
+	c := s.Cookie("accounts/acc_1/cookies.json")
+
+	_, err := c.LoadCookies()
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	require.NoError(t, c.SaveCookies([]byte(`[{"name":"a"}]`)))
+	require.NoError(t, c.SaveCookies([]byte(`[{"name":"b"}]`)))
+	data, err := c.LoadCookies()
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"b"}]`, string(data))
+
+	require.NoError(t, c.DeleteCookies())
+	_, err = c.LoadCookies()
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestImportJSON(t *testing.T) {
+	s := openTestStore(t)
+	dir := t.TempDir()
+	t.Setenv("COOKIES_BASE_DIR", filepath.Join(dir, "cookies"))
+	storePath := filepath.Join(dir, "accounts.json")
+
+	src, err := accounts.NewManager(storePath, filepath.Join(dir, "profiles"))
+	require.NoError(t, err)
+	acc, err := src.Create("http://127.0.0.1:8080", "a")
+	require.NoError(t, err)
+	src.MarkLoggedIn(acc.Key)
+	_, err = src.Create("", "b")
+	require.NoError(t, err)
+	require.NoError(t, cookies.NewFileCookie(acc.CookiePath).SaveCookies([]byte(`[{"name":"web_session"}]`)))
+
+	for i := 0; i < 2; i++ {
+		res, err := s.ImportJSON(storePath)
+		require.NoError(t, err)
+		assert.Equal(t, &ImportResult{Accounts: 2, Cookies: 1, Logins: 1}, res)
+	}
+
+	nextID, list, err := s.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 3, nextID)
+	assert.Len(t, list, 2)
+	data, err := s.Cookie(acc.CookiePath).LoadCookies()
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"web_session"}]`, string(data))
+	logins, err := s.LoginHistory(acc.Key, 0)
+	require.NoError(t, err)
+	require.Len(t, logins, 1)
+	assert.WithinDuration(t, time.Now(), logins[0], time.Minute)
+}
+
+func TestAuditStore(t *testing.T) {
+	a, err := OpenAudit(filepath.Join(t.TempDir(), "xhs.db"), 2)
+	require.NoError(t, err)
+	log := audit.New(a)
+	defer log.Close()
+
+	start := time.Now()
+	for i, feed := range []string{"f1", "f2", "f3"} {
+		log.Record(audit.Entry{Time: start.Add(time.Duration(i) * time.Second), AccountKey: "acc_1",
+			Action: audit.ActionComment, FeedID: feed, ContentHash: audit.HashContent(feed), Outcome: audit.OutcomeSuccess})
+	}
+
+	// 只保留最新的 2 条
+	all, err := log.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "f3", all[0].FeedID)
+	assert.Equal(t, audit.ActionComment, all[0].Action)
+
+	got, err := log.Query(audit.Filter{ContentHash: audit.HashContent("f2"), Since: start})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "f2", got[0].FeedID)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/secret"
+	"github.com/xpzouying/xiaohongshu-mcp/sqlitestore"
+)
+
+// 存储后端
+const (
+	storageJSON   = "json"
+	storageSQLite = "sqlite"
+)
+
+// storageBackend 返回 STORAGE_BACKEND 指定的存储后端，默认 json
+func storageBackend() string {
+	if v := os.Getenv("STORAGE_BACKEND"); v != "" {
+		return v
+	}
+	return storageJSON
+}
+
+// sqlitePath 返回 SQLite 数据库路径，默认与账号文件放在同一目录
+func sqlitePath() string {
+	if v := os.Getenv("SQLITE_PATH"); v != "" {
+		return v
+	}
+	storePath, _ := accountStorePaths()
+	return filepath.Join(filepath.Dir(storePath), "xhs.db")
+}
+
+// openAccountManager 按存储后端创建账号管理器，返回值 where 用于日志展示数据位置。
+// sqlite 后端同时接管 cookies 的存储。
+func openAccountManager(backend string) (am *accounts.Manager, where string, err error) {
+	storePath, profileBase := accountStorePaths()
+	switch backend {
+	case storageJSON, "":
+		cookies.SetBackend(nil)
+		am, err = accounts.NewManager(storePath, profileBase)
+		return am, storePath, err
+	case storageSQLite:
+		dbPath := sqlitePath()
+		db, err := sqlitestore.Open(dbPath)
+		if err != nil {
+			return nil, dbPath, err
+		}
+		am, err = accounts.NewManagerWithStore(db, profileBase)
+		if err != nil {
+			db.Close()
+			return nil, dbPath, err
+		}
+		cookies.SetBackend(db.Cookie)
+		return am, dbPath, nil
+	default:
+		return nil, "", errors.Errorf("unknown storage backend %q, expected %s or %s", backend, storageJSON, storageSQLite)
+	}
+}
+
+// openAuditLog 按存储后端打开审计日志：json 后端写入 JSONL 文件（AUDIT_LOG，默认与账号文件同目录的 audit.jsonl），
+// 按 AUDIT_MAX_SIZE_MB 轮转并保留 AUDIT_MAX_FILES 个历史文件；sqlite 后端写入同一个数据库，只保留最新的 AUDIT_MAX_ROWS 条。
+func openAuditLog(backend string) (al *audit.Log, where string, err error) {
+	maxSizeMB, err := envInt("AUDIT_MAX_SIZE_MB", 50)
+	if err != nil {
+		return nil, "", err
+	}
+	maxFiles, err := envInt("AUDIT_MAX_FILES", 10)
+	if err != nil {
+		return nil, "", err
+	}
+	maxRows, err := envInt("AUDIT_MAX_ROWS", 1000000)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch backend {
+	case storageJSON, "":
+		path := os.Getenv("AUDIT_LOG")
+		if path == "" {
+			storePath, _ := accountStorePaths()
+			path = filepath.Join(filepath.Dir(storePath), "audit.jsonl")
+		}
+		store, err := audit.NewFileStore(path, audit.FileOptions{MaxBytes: int64(maxSizeMB) << 20, MaxFiles: maxFiles})
+		if err != nil {
+			return nil, path, err
+		}
+		return audit.New(store), path, nil
+	case storageSQLite:
+		dbPath := sqlitePath()
+		store, err := sqlitestore.OpenAudit(dbPath, maxRows)
+		if err != nil {
+			return nil, dbPath, err
+		}
+		return audit.New(store), dbPath, nil
+	default:
+		return nil, "", errors.Errorf("unknown storage backend %q, expected %s or %s", backend, storageJSON, storageSQLite)
+	}
+}
+
+// envInt 读取整数环境变量，未设置时返回 def
+func envInt(name string, def int) (int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s %q", name, v)
+	}
+	return n, nil
+}
+
+// runImportSQLite 实现 import-sqlite 子命令：把 accounts.json 与各账号 cookies 文件导入 SQLite 数据库。
+// 原文件保持不变，确认无误后可将 STORAGE_BACKEND 设置为 sqlite。
+func runImportSQLite(args []string) error {
+	storePath, _ := accountStorePaths()
+	fs := flag.NewFlagSet("import-sqlite", flag.ContinueOnError)
+	from := fs.String("accounts", storePath, "要导入的账号文件")
+	to := fs.String("db", sqlitePath(), "SQLite 数据库路径")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// 代理凭据需要解密后再按当前密钥写入数据库
+	box, err := secret.FromEnv()
+	if err != nil {
+		return errors.Wrap(err, "load encryption key")
+	}
+	secret.SetDefault(box)
+
+	db, err := sqlitestore.Open(*to)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	res, err := db.ImportJSON(*from)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("已从 %s 导入 %d 个账号、%d 份 cookies、%d 条登录记录到 %s",
+		*from, res.Accounts, res.Cookies, res.Logins, *to)
+	logrus.Infof("设置 STORAGE_BACKEND=%s SQLITE_PATH=%s 后重启服务即可使用", storageSQLite, *to)
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package tracing 配置 OpenTelemetry 链路追踪：从 HTTP/MCP 入口、服务方法到浏览器操作的各个阶段
+// （打开页面、等待 __INITIAL_STATE__、滚动加载评论、上传、提交）都会产生 span，
+// 可导出到 OTLP collector 或标准输出。
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName 未设置 OTEL_SERVICE_NAME 时上报的服务名
+const ServiceName = "xiaohongshu-mcp"
+
+const tracerName = "github.com/xpzouying/xiaohongshu-mcp"
+
+// span 属性
+const (
+	AttrAccount = attribute.Key("xhs.account")
+	AttrFeedID  = attribute.Key("xhs.feed_id")
+)
+
+// OTEL_TRACES_EXPORTER 的取值
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Setup 按环境变量 OTEL_TRACES_EXPORTER 配置全局 TracerProvider：
+// otlp 以 OTLP/HTTP 导出（地址、请求头等由 OTEL_EXPORTER_OTLP_* 配置，默认 localhost:4318），
+// stdout 打印到标准输出，未设置或为 none 时不启用。
+// 返回的 shutdown 在退出前调用，导出尚未发送的 span。
+func Setup(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	exporter, err := newExporter(ctx, os.Getenv("OTEL_TRACES_EXPORTER"))
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// 后面的来源覆盖前面的：OTEL_SERVICE_NAME 与 OTEL_RESOURCE_ATTRIBUTES 优先于默认服务名
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "create tracing resource")
+	}
+
+	// 采样由 OTEL_TRACES_SAMPLER 配置，默认全部采样并沿用上游的采样决定
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, name string) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterOTLP:
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "create otlp exporter")
+		}
+		return exp, nil
+	case ExporterStdout:
+		exp, err := stdouttrace.New()
+		if err != nil {
+			return nil, errors.Wrap(err, "create stdout exporter")
+		}
+		return exp, nil
+	default:
+		return nil, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q, want %s, %s or %s", name, ExporterOTLP, ExporterStdout, ExporterNone)
+	}
+}
+
+// Start 在 ctx 中的 span 下开启子 span，未启用追踪时返回空操作的 span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Extract 从请求头的 traceparent 中读取上游链路，ctx 中已有 span 时原样返回
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if header == nil || trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// End 结束 span，*errp 非空时记录错误并标记失败，用法：defer tracing.End(span, &err)。
+// rod 的 Must* 方法失败时直接 panic，End 记录后继续 panic，因此必须直接 defer 调用。
+func End(span trace.Span, errp *error) {
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("%v", r)
+		}
+		fail(span, err)
+		span.End()
+		panic(r)
+	}
+	if errp != nil && *errp != nil {
+		fail(span, *errp)
+	}
+	span.End()
+}
+
+func fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
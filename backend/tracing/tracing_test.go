@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func useRecorder(t *testing.T) *tracetest.SpanRecorder {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return rec
+}
+
+func TestStartEnd(t *testing.T) {
+	rec := useRecorder(t)
+
+	run := func(ctx context.Context) (err error) {
+		ctx, span := Start(ctx, "XiaohongshuService.GetFeedDetail", AttrFeedID.String("feed_1"))
+		defer End(span, &err)
+
+		_, child := Start(ctx, "navigate")
+		End(child, nil)
+		return errors.New("timeout")
+	}
+	require.Error(t, run(context.Background()))
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	child, parent := spans[0], spans[1]
+	assert.Equal(t, "navigate", child.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+	assert.Equal(t, codes.Unset, child.Status().Code)
+
+	assert.Equal(t, codes.Error, parent.Status().Code)
+	assert.Equal(t, "timeout", parent.Status().Description)
+	assert.Contains(t, parent.Attributes(), AttrFeedID.String("feed_1"))
+	require.Len(t, parent.Events(), 1, "错误记录为 exception 事件")
+}
+
+func TestEndPanic(t *testing.T) {
+	rec := useRecorder(t)
+
+	assert.PanicsWithValue(t, "element not found", func() {
+		_, span := Start(context.Background(), "submit")
+		defer End(span, nil)
+		panic("element not found")
+	})
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "element not found", spans[0].Status().Description)
+}
+
+func TestExtract(t *testing.T) {
+	rec := useRecorder(t)
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := Extract(context.Background(), header)
+	_, span := Start(ctx, "tools/call search_feeds")
+	End(span, nil)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+
+	// 已有 span 时沿用当前链路
+	ctx, parent := Start(context.Background(), "POST /mcp")
+	assert.Equal(t, ctx, Extract(ctx, header))
+	End(parent, nil)
+	assert.Equal(t, ctx, Extract(ctx, nil))
+}
+
+func TestSetup(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	shutdown, err := Setup(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "jaeger")
+	_, err = Setup(context.Background())
+	assert.ErrorContains(t, err, "unsupported OTEL_TRACES_EXPORTER")
+}
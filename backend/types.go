@@ -9,6 +9,8 @@ type ErrorResponse struct {
 	Error   string `json:"error"`
 	Code    string `json:"code"`
 	Details any    `json:"details,omitempty"`
+	// ArtifactID 浏览器操作失败时保存的现场，可通过 /api/v1/artifacts/:id 下载
+	ArtifactID string `json:"artifact_id,omitempty"`
 }
 
 // SuccessResponse 成功响应
@@ -59,6 +61,8 @@ type SearchFeedsRequest struct {
 	AccountID int                      `json:"account_id,omitempty"`
 	Keyword   string                   `json:"keyword" binding:"required"`
 	Filters   xiaohongshu.FilterOption `json:"filters,omitempty"`
+	Limit     int                      `json:"limit,omitempty" binding:"min=0"`
+	Cursor    string                   `json:"cursor,omitempty"`
 }
 
 // FeedDetailResponse Feed详情响应
@@ -106,6 +110,10 @@ type UserProfileRequest struct {
 	AccountID int    `json:"account_id,omitempty"`
 	UserID    string `json:"user_id" binding:"required"`
 	XsecToken string `json:"xsec_token" binding:"required"`
+	// MaxNotes 滚动抓取的笔记上限，与 Since 都为空时只返回首屏笔记
+	MaxNotes int `json:"max_notes,omitempty"`
+	// Since 只返回该时间之后发布的笔记，RFC3339 或 2006-01-02 15:04（北京时间）
+	Since string `json:"since,omitempty"`
 }
 
 // Account management payloads
@@ -146,3 +154,112 @@ type ActionResult struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 }
+
+// FollowUserRequest 关注/取消关注用户请求
+type FollowUserRequest struct {
+	AccountID int    `json:"account_id,omitempty"`
+	UserID    string `json:"user_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+}
+
+// FollowResult 关注/取消关注响应
+type FollowResult struct {
+	UserID string `json:"user_id"`
+	// Status 操作后的关注关系：none、follows、fans、both
+	Status  xiaohongshu.FollowStatus `json:"status"`
+	Success bool                     `json:"success"`
+	Message string                   `json:"message"`
+}
+
+// CommentTarget 定位评论：comment_id 与 user_id 至少一个，user_id 匹配该用户的第一条评论
+type CommentTarget struct {
+	AccountID int    `json:"account_id,omitempty"`
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+	CommentID string `json:"comment_id" binding:"required_without=UserID"`
+	UserID    string `json:"user_id" binding:"required_without=CommentID"`
+}
+
+// LikeCommentRequest 点赞/取消点赞评论请求
+type LikeCommentRequest struct {
+	CommentTarget
+	Unlike bool `json:"unlike,omitempty"`
+}
+
+// DeleteCommentRequest 删除评论请求，为避免误删必须指定 comment_id
+type DeleteCommentRequest struct {
+	AccountID int    `json:"account_id,omitempty"`
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+	CommentID string `json:"comment_id" binding:"required"`
+}
+
+// PinCommentRequest 置顶/取消置顶评论请求
+type PinCommentRequest struct {
+	CommentTarget
+	Unpin bool `json:"unpin,omitempty"`
+}
+
+// CommentActionResult 评论管理操作响应
+type CommentActionResult struct {
+	FeedID    string `json:"feed_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+}
+
+// EditNoteRequest 编辑已发布笔记请求，未传的字段保持不变
+type EditNoteRequest struct {
+	Title   string   `json:"title,omitempty"`
+	Content string   `json:"content,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// NoteVisibilityRequest 修改笔记可见范围请求
+type NoteVisibilityRequest struct {
+	Visibility string `json:"visibility" binding:"required,oneof=public private"`
+}
+
+// CreatorNoteResult 笔记管理操作响应
+type CreatorNoteResult struct {
+	NoteID  string `json:"note_id"`
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// SendMessageRequest 发送私信请求，对方由路径参数 user_id 指定
+type SendMessageRequest struct {
+	Content string `json:"content" binding:"required,max=500"`
+}
+
+// SendMessageResult 发送私信响应
+type SendMessageResult struct {
+	UserID    string `json:"user_id"`
+	MessageID string `json:"message_id,omitempty"`
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+}
+
+// PublishDraftRequest 发布草稿请求，未传的字段保持草稿原内容
+type PublishDraftRequest struct {
+	AccountID int `json:"account_id,omitempty"`
+	// DraftID 由路径参数填入
+	DraftID string   `json:"draft_id,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	Content string   `json:"content,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	// PublishAt 定时发布时间，RFC3339 或 "2006-01-02 15:04"（北京时间），为空表示立即发布
+	PublishAt string `json:"publish_at,omitempty"`
+	// queued 由任务执行时设置，表示 PublishAt 已在入队时校验过
+	queued bool
+}
+
+// PublishDraftResult 发布草稿结果
+type PublishDraftResult struct {
+	DraftID string `json:"draft_id"`
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	// PublishAt 定时发布时间（北京时间），立即发布时为空
+	PublishAt string `json:"publish_at,omitempty"`
+}
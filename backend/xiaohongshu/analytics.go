@@ -0,0 +1,390 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// noteAnalyticsAPI 笔记数据页按页加载笔记指标的接口
+	noteAnalyticsAPI = "/api/galaxy/creator/datacenter/note/analyze/list"
+	// accountAnalyticsAPI 账号概览页加载近 7 日/30 日趋势的接口
+	accountAnalyticsAPI = "/api/galaxy/creator/datacenter/account/overview"
+
+	noteAnalyticsQuery = "analytics-notes"
+
+	// AnalyticsMaxDays 数据中心最多提供最近多少天的账号趋势
+	AnalyticsMaxDays = 30
+	// AnalyticsDefaultDays 未指定日期范围时返回最近多少天
+	AnalyticsDefaultDays = 7
+
+	analyticsDateLayout = "2006-01-02"
+)
+
+// ErrInvalidDateRange 日期格式错误，或超出数据中心提供的范围
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// NoteMetrics 一篇笔记发布以来的累计数据
+type NoteMetrics struct {
+	NoteID       string `json:"note_id"`
+	Title        string `json:"title"`
+	Type         string `json:"type"`
+	PublishTime  string `json:"publish_time"`
+	Impressions  int    `json:"impressions"`
+	Views        int    `json:"views"`
+	Likes        int    `json:"likes"`
+	Collects     int    `json:"collects"`
+	Comments     int    `json:"comments"`
+	Shares       int    `json:"shares"`
+	FollowerGain int    `json:"follower_gain"`
+}
+
+// NoteMetricsPage 一页笔记数据，NextCursor 为空表示没有更多
+type NoteMetricsPage struct {
+	Notes      []NoteMetrics
+	NextCursor string
+}
+
+// AccountMetrics 账号在一段时间内的数据，Date 为空时表示汇总
+type AccountMetrics struct {
+	Date         string `json:"date,omitempty"`
+	Impressions  int    `json:"impressions"`
+	Views        int    `json:"views"`
+	Likes        int    `json:"likes"`
+	Collects     int    `json:"collects"`
+	Comments     int    `json:"comments"`
+	Shares       int    `json:"shares"`
+	FollowerGain int    `json:"follower_gain"`
+}
+
+func (m *AccountMetrics) add(o AccountMetrics) {
+	m.Impressions += o.Impressions
+	m.Views += o.Views
+	m.Likes += o.Likes
+	m.Collects += o.Collects
+	m.Comments += o.Comments
+	m.Shares += o.Shares
+	m.FollowerGain += o.FollowerGain
+}
+
+// AccountAnalytics 账号在 From~To（含）之间的每日趋势与汇总
+type AccountAnalytics struct {
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+	Followers int              `json:"followers"`
+	Total     AccountMetrics   `json:"total"`
+	Daily     []AccountMetrics `json:"daily"`
+}
+
+// DateRange 北京时间的日期范围，From 与 To 都包含在内
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func (r DateRange) contains(day time.Time) bool {
+	return !day.Before(r.From) && !day.After(r.To)
+}
+
+// ParseDateRange 解析 YYYY-MM-DD 格式的日期范围。数据中心只提供到昨天为止、最近 AnalyticsMaxDays 天的数据；
+// 都为空时返回最近 AnalyticsDefaultDays 天，只传一个时另一个按默认天数推算。
+func ParseDateRange(from, to string, now time.Time) (DateRange, error) {
+	today := truncateDay(now)
+	yesterday := today.AddDate(0, 0, -1)
+	earliest := yesterday.AddDate(0, 0, 1-AnalyticsMaxDays)
+
+	var r DateRange
+	var err error
+	if to != "" {
+		if r.To, err = time.ParseInLocation(analyticsDateLayout, to, ScheduleLocation); err != nil {
+			return r, errors.Wrapf(ErrInvalidDateRange, "to=%q，格式应为 YYYY-MM-DD", to)
+		}
+	}
+	if from != "" {
+		if r.From, err = time.ParseInLocation(analyticsDateLayout, from, ScheduleLocation); err != nil {
+			return r, errors.Wrapf(ErrInvalidDateRange, "from=%q，格式应为 YYYY-MM-DD", from)
+		}
+	}
+	switch {
+	case from == "" && to == "":
+		r.To = yesterday
+		r.From = yesterday.AddDate(0, 0, 1-AnalyticsDefaultDays)
+	case from == "":
+		r.From = r.To.AddDate(0, 0, 1-AnalyticsDefaultDays)
+		if r.From.Before(earliest) {
+			r.From = earliest
+		}
+	case to == "":
+		r.To = r.From.AddDate(0, 0, AnalyticsDefaultDays-1)
+		if r.To.After(yesterday) {
+			r.To = yesterday
+		}
+	}
+
+	if r.From.After(r.To) {
+		return r, errors.Wrapf(ErrInvalidDateRange, "from %s 晚于 to %s", r.From.Format(analyticsDateLayout), r.To.Format(analyticsDateLayout))
+	}
+	if r.From.Before(earliest) || r.To.After(yesterday) {
+		return r, errors.Wrapf(ErrInvalidDateRange, "只能查询 %s 至 %s 之间的数据",
+			earliest.Format(analyticsDateLayout), yesterday.Format(analyticsDateLayout))
+	}
+	return r, nil
+}
+
+// truncateDay 取北京时间当天零点
+func truncateDay(t time.Time) time.Time {
+	t = t.In(ScheduleLocation)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, ScheduleLocation)
+}
+
+// noteAnalyticsRaw noteAnalyticsAPI 返回的笔记字段
+type noteAnalyticsRaw struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Type         string `json:"type"`
+	PostTime     int64  `json:"post_time"`
+	ImpCount     int    `json:"imp_count"`
+	ReadCount    int    `json:"read_count"`
+	LikeCount    int    `json:"like_count"`
+	FavCount     int    `json:"fav_count"`
+	CommentCount int    `json:"comment_count"`
+	ShareCount   int    `json:"share_count"`
+	IncreaseFans int    `json:"increase_fans_count"`
+}
+
+// parseNoteAnalytics 解析 noteAnalyticsAPI 的响应体
+func parseNoteAnalytics(body []byte) ([]NoteMetrics, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			NoteInfos []noteAnalyticsRaw `json:"note_infos"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal note analytics failed")
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("note analytics api failed: %s", resp.Msg)
+	}
+	notes := make([]NoteMetrics, 0, len(resp.Data.NoteInfos))
+	for _, r := range resp.Data.NoteInfos {
+		n := NoteMetrics{
+			NoteID:       r.ID,
+			Title:        r.Title,
+			Type:         r.Type,
+			Impressions:  r.ImpCount,
+			Views:        r.ReadCount,
+			Likes:        r.LikeCount,
+			Collects:     r.FavCount,
+			Comments:     r.CommentCount,
+			Shares:       r.ShareCount,
+			FollowerGain: r.IncreaseFans,
+		}
+		if r.PostTime > 0 {
+			n.PublishTime = time.UnixMilli(r.PostTime).In(ScheduleLocation).Format(scheduleTimeLayout)
+		}
+		notes = append(notes, n)
+	}
+	return notes, nil
+}
+
+// trendPoint accountAnalyticsAPI 中某项指标某一天的值，date 为当天零点的毫秒时间戳
+type trendPoint struct {
+	Date  int64 `json:"date"`
+	Count int   `json:"count"`
+}
+
+type trendPeriod struct {
+	ImpList      []trendPoint `json:"imp_list"`
+	ViewList     []trendPoint `json:"view_list"`
+	LikeList     []trendPoint `json:"like_list"`
+	CollectList  []trendPoint `json:"collect_list"`
+	CommentList  []trendPoint `json:"comment_list"`
+	ShareList    []trendPoint `json:"share_list"`
+	RiseFansList []trendPoint `json:"rise_fans_list"`
+}
+
+func (p trendPeriod) empty() bool {
+	return len(p.ImpList)+len(p.ViewList)+len(p.LikeList)+len(p.CollectList)+
+		len(p.CommentList)+len(p.ShareList)+len(p.RiseFansList) == 0
+}
+
+// parseAccountAnalytics 解析 accountAnalyticsAPI 的响应体，按日期合并各项指标并截取 r 范围内的数据
+func parseAccountAnalytics(body []byte, r DateRange) (*AccountAnalytics, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			FansCount int         `json:"fans_count"`
+			Seven     trendPeriod `json:"seven"`
+			Thirty    trendPeriod `json:"thirty"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal account analytics failed")
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("account analytics api failed: %s", resp.Msg)
+	}
+	period := resp.Data.Thirty
+	if period.empty() {
+		period = resp.Data.Seven
+	}
+
+	days := map[string]*AccountMetrics{}
+	merge := func(points []trendPoint, set func(*AccountMetrics, int)) {
+		for _, p := range points {
+			day := truncateDay(time.UnixMilli(p.Date))
+			if !r.contains(day) {
+				continue
+			}
+			key := day.Format(analyticsDateLayout)
+			if days[key] == nil {
+				days[key] = &AccountMetrics{Date: key}
+			}
+			set(days[key], p.Count)
+		}
+	}
+	merge(period.ImpList, func(m *AccountMetrics, v int) { m.Impressions = v })
+	merge(period.ViewList, func(m *AccountMetrics, v int) { m.Views = v })
+	merge(period.LikeList, func(m *AccountMetrics, v int) { m.Likes = v })
+	merge(period.CollectList, func(m *AccountMetrics, v int) { m.Collects = v })
+	merge(period.CommentList, func(m *AccountMetrics, v int) { m.Comments = v })
+	merge(period.ShareList, func(m *AccountMetrics, v int) { m.Shares = v })
+	merge(period.RiseFansList, func(m *AccountMetrics, v int) { m.FollowerGain = v })
+
+	result := &AccountAnalytics{
+		From:      r.From.Format(analyticsDateLayout),
+		To:        r.To.Format(analyticsDateLayout),
+		Followers: resp.Data.FansCount,
+		Daily:     make([]AccountMetrics, 0, len(days)),
+	}
+	for _, m := range days {
+		result.Daily = append(result.Daily, *m)
+		result.Total.add(*m)
+	}
+	sort.Slice(result.Daily, func(i, j int) bool { return result.Daily[i].Date < result.Daily[j].Date })
+	return result, nil
+}
+
+// CheckNoteAnalyticsCursor 在打开页面前校验笔记数据列表的游标
+func CheckNoteAnalyticsCursor(cursor string) error {
+	_, err := decodeCursor(cursor, noteAnalyticsQuery)
+	return err
+}
+
+// CreatorAnalyticsAction 创作者中心数据中心：笔记数据与账号趋势
+type CreatorAnalyticsAction struct {
+	page *rod.Page
+}
+
+func NewCreatorAnalyticsAction(page *rod.Page) *CreatorAnalyticsAction {
+	return &CreatorAnalyticsAction{page: page}
+}
+
+// open 打开数据中心页面并记录指定接口的响应
+func (a *CreatorAnalyticsAction) open(ctx context.Context, url, api string) (*rod.Page, *responseRecorder) {
+	page := a.page.Context(ctx).Timeout(3 * time.Minute)
+	rec := recordResponses(page, api)
+
+	logrus.Infof("打开数据中心: %s", url)
+	page.MustNavigate(url).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+	return page, rec
+}
+
+// readNoteMetrics 合并目前所有接口响应中的笔记数据，按加载顺序
+func readNoteMetrics(rec *responseRecorder) ([]NoteMetrics, error) {
+	var all []NoteMetrics
+	for _, body := range rec.snapshot() {
+		notes, err := parseNoteAnalytics(body)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, notes...)
+	}
+	return all, nil
+}
+
+// nextAnalyticsPage 点击笔记数据表格的下一页，没有下一页时返回 false
+func nextAnalyticsPage(page *rod.Page) bool {
+	btn, err := findElement(page.Timeout(2*time.Second), "analytics.next_page")
+	if err != nil {
+		return false
+	}
+	btn.MustScrollIntoView()
+	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		logrus.Warnf("点击下一页失败: %v", err)
+		return false
+	}
+	return true
+}
+
+// Notes 列出笔记的累计数据，返回 opts.Cursor 之后的最多 opts.Limit 篇
+func (a *CreatorAnalyticsAction) Notes(ctx context.Context, opts PageOptions) (*NoteMetricsPage, error) {
+	page, rec := a.open(ctx, creatorURL(pathOfNoteAnalytics), noteAnalyticsAPI)
+	defer rec.stop()
+
+	read := func() ([]NoteMetrics, error) { return readNoteMetrics(rec) }
+	scroll := func(int) { nextAnalyticsPage(page) }
+	notes, next, err := collectPage(ctx, noteAnalyticsQuery, opts, read, func(n NoteMetrics) string { return n.NoteID }, scroll)
+	if err != nil {
+		return nil, err
+	}
+	return &NoteMetricsPage{Notes: notes, NextCursor: next}, nil
+}
+
+// Note 翻页查找单篇笔记的数据
+func (a *CreatorAnalyticsAction) Note(ctx context.Context, noteID string) (*NoteMetrics, error) {
+	page, rec := a.open(ctx, creatorURL(pathOfNoteAnalytics), noteAnalyticsAPI)
+	defer rec.stop()
+
+	for pages := 0; pages <= pageMaxScrolls; pages++ {
+		notes, err := readNoteMetrics(rec)
+		if err != nil {
+			return nil, err
+		}
+		for i := range notes {
+			if notes[i].NoteID == noteID {
+				return &notes[i], nil
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !nextAnalyticsPage(page) {
+			break
+		}
+		sleepRandom(readTimeRange.min, readTimeRange.max)
+	}
+	return nil, errors.Wrapf(ErrCreatorNoteNotFound, "note_id=%s", noteID)
+}
+
+// Account 读取账号在 r 范围内的每日趋势
+func (a *CreatorAnalyticsAction) Account(ctx context.Context, r DateRange) (*AccountAnalytics, error) {
+	_, rec := a.open(ctx, creatorURL(pathOfAccountAnalytics), accountAnalyticsAPI)
+	defer rec.stop()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if bodies := rec.snapshot(); len(bodies) > 0 {
+			return parseAccountAnalytics(bodies[len(bodies)-1], r)
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("没有获取到账号数据，请确认账号已开通数据中心")
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,122 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateRange(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, ScheduleLocation)
+	day := func(s string) time.Time {
+		d, err := time.ParseInLocation(analyticsDateLayout, s, ScheduleLocation)
+		require.NoError(t, err)
+		return d
+	}
+
+	r, err := ParseDateRange("", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, DateRange{From: day("2025-03-03"), To: day("2025-03-09")}, r)
+
+	r, err = ParseDateRange("2025-03-08", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, day("2025-03-09"), r.To, "to 不超过昨天")
+
+	r, err = ParseDateRange("", "2025-02-12", now)
+	require.NoError(t, err)
+	assert.Equal(t, day("2025-02-08"), r.From, "from 不早于 30 天前")
+
+	for _, tc := range [][2]string{
+		{"2025/03/01", ""},
+		{"2025-03-05", "2025-03-01"},
+		{"2025-02-01", "2025-02-10"},
+		{"", "2025-03-10"},
+	} {
+		_, err := ParseDateRange(tc[0], tc[1], now)
+		assert.ErrorIs(t, err, ErrInvalidDateRange, tc)
+	}
+}
+
+func TestParseNoteAnalytics(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"note_infos":[
+		{"id":"n1","title":"标题1","type":"normal","post_time":1735696800000,"imp_count":5000,"read_count":1200,"like_count":80,"fav_count":30,"comment_count":12,"share_count":2,"increase_fans_count":5}
+	]}}`)
+
+	notes, err := parseNoteAnalytics(body)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, NoteMetrics{
+		NoteID: "n1", Title: "标题1", Type: "normal", PublishTime: "2025-01-01 10:00",
+		Impressions: 5000, Views: 1200, Likes: 80, Collects: 30, Comments: 12, Shares: 2, FollowerGain: 5,
+	}, notes[0])
+
+	_, err = parseNoteAnalytics([]byte(`{"success":false,"msg":"登录已过期"}`))
+	assert.Error(t, err)
+}
+
+func TestParseAccountAnalytics(t *testing.T) {
+	ms := func(s string) int64 {
+		d, _ := time.ParseInLocation(analyticsDateLayout, s, ScheduleLocation)
+		return d.UnixMilli()
+	}
+	body := []byte(fmt.Sprintf(`{"success":true,"data":{"fans_count":321,
+		"seven":{"view_list":[{"date":%[1]d,"count":1}]},
+		"thirty":{
+			"imp_list":[{"date":%[1]d,"count":100},{"date":%[2]d,"count":200},{"date":%[3]d,"count":300}],
+			"view_list":[{"date":%[1]d,"count":10},{"date":%[2]d,"count":20},{"date":%[3]d,"count":30}],
+			"rise_fans_list":[{"date":%[2]d,"count":3}]
+		}}}`, ms("2025-03-01"), ms("2025-03-02"), ms("2025-03-03")))
+
+	r := DateRange{
+		From: time.Date(2025, 3, 2, 0, 0, 0, 0, ScheduleLocation),
+		To:   time.Date(2025, 3, 3, 0, 0, 0, 0, ScheduleLocation),
+	}
+	got, err := parseAccountAnalytics(body, r)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-03-02", got.From)
+	assert.Equal(t, 321, got.Followers)
+	assert.Equal(t, []AccountMetrics{
+		{Date: "2025-03-02", Impressions: 200, Views: 20, FollowerGain: 3},
+		{Date: "2025-03-03", Impressions: 300, Views: 30},
+	}, got.Daily)
+	assert.Equal(t, AccountMetrics{Impressions: 500, Views: 50, FollowerGain: 3}, got.Total)
+}
+
+func TestCreatorAnalytics_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+	ctx := context.Background()
+	action := NewCreatorAnalyticsAction(page)
+
+	// 每页两篇，第三篇需要点击下一页
+	got, err := action.Notes(ctx, PageOptions{Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, got.Notes, 3)
+	assert.Equal(t, "65f00000000000000000a001", got.Notes[0].NoteID)
+	assert.Equal(t, 3200, got.Notes[0].Views)
+	assert.Equal(t, "65f00000000000000000a003", got.Notes[2].NoteID)
+	assert.NotEmpty(t, got.NextCursor)
+
+	note, err := action.Note(ctx, "65f00000000000000000a004")
+	require.NoError(t, err)
+	assert.Equal(t, "年终总结", note.Title)
+	assert.Equal(t, 35, note.FollowerGain)
+
+	_, err = action.Note(ctx, "missing")
+	assert.ErrorIs(t, err, ErrCreatorNoteNotFound)
+
+	account, err := action.Account(ctx, DateRange{
+		From: time.Date(2025, 3, 2, 0, 0, 0, 0, ScheduleLocation),
+		To:   time.Date(2025, 3, 3, 0, 0, 0, 0, ScheduleLocation),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3456, account.Followers)
+	require.Len(t, account.Daily, 2)
+	assert.Equal(t, AccountMetrics{Date: "2025-03-02", Impressions: 2000, Views: 200, Likes: 20, Collects: 2, Comments: 1}, account.Daily[0])
+	assert.Equal(t, 5000, account.Total.Impressions)
+	assert.Equal(t, 5, account.Total.FollowerGain)
+}
@@ -0,0 +1,367 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 网页版加载列表数据的接口。首屏数据由服务端渲染进 __INITIAL_STATE__，
+// 滚动加载的后续内容只能从这些接口的响应中完整拿到。
+const (
+	homefeedAPI    = "/api/sns/web/v1/homefeed"
+	searchNotesAPI = "/api/sns/web/v1/search/notes"
+	commentPageAPI = "/api/sns/web/v2/comment/page"
+	userPostedAPI  = "/api/sns/web/v1/user_posted"
+)
+
+// responseRecorder 记录页面中 URL 包含指定片段的接口响应体
+type responseRecorder struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	stop   context.CancelFunc
+}
+
+// recordResponses 开始监听页面网络响应，需在导航前调用，用完后调用 stop
+func recordResponses(page *rod.Page, urlPart string) *responseRecorder {
+	ctx, cancel := context.WithCancel(page.GetContext())
+	p := page.Context(ctx)
+	r := &responseRecorder{stop: cancel}
+
+	if err := (proto.NetworkEnable{}).Call(p); err != nil {
+		logrus.Warnf("enable network domain failed: %v", err)
+	}
+	pending := map[proto.NetworkRequestID]bool{}
+	wait := p.EachEvent(func(e *proto.NetworkResponseReceived) {
+		if strings.Contains(e.Response.URL, urlPart) {
+			pending[e.RequestID] = true
+		}
+	}, func(e *proto.NetworkLoadingFinished) {
+		if !pending[e.RequestID] {
+			return
+		}
+		delete(pending, e.RequestID)
+		res, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(p)
+		if err != nil {
+			logrus.Warnf("get response body failed: %v", err)
+			return
+		}
+		body := []byte(res.Body)
+		if res.Base64Encoded {
+			if body, err = base64.StdEncoding.DecodeString(res.Body); err != nil {
+				return
+			}
+		}
+		r.mu.Lock()
+		r.bodies = append(r.bodies, body)
+		r.mu.Unlock()
+	})
+	go wait()
+	return r
+}
+
+func (r *responseRecorder) snapshot() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]byte(nil), r.bodies...)
+}
+
+// reset 丢弃已记录的响应，例如切换筛选条件后旧结果不再有效
+func (r *responseRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = nil
+}
+
+// apiPage 一次列表接口响应解析出的条目，Cursor 与 HasMore 为接口给出的翻页信息
+type apiPage[T any] struct {
+	Items   []T
+	Cursor  string
+	HasMore bool
+}
+
+// readCaptured 按到达顺序合并 rec 中目前的全部响应；ok 为 false 表示还没有可用的响应。
+// 单个响应解析失败（如被风控拦截）只记日志并跳过，其余响应照常使用。
+func readCaptured[T any](rec *responseRecorder, decode func([]byte) (apiPage[T], error)) (merged apiPage[T], ok bool) {
+	for _, body := range rec.snapshot() {
+		p, err := decode(body)
+		if err != nil {
+			logrus.Debugf("跳过无法解析的接口响应: %v", err)
+			continue
+		}
+		merged.Items = append(merged.Items, p.Items...)
+		merged.Cursor, merged.HasMore = p.Cursor, p.HasMore
+		ok = true
+	}
+	return merged, ok
+}
+
+// readMerged 合并页面状态与接口响应中的条目并按 id 去重，页面状态在前以保持展示顺序。
+// 同一条目两边都有时使用接口中的数据：页面状态里的副本经过前端裁剪，可能缺少封面、子评论等字段。
+// 页面状态读取失败时只用接口数据，两者都没有时返回页面状态的错误。
+func readMerged[T any](state func() ([]T, error), captured []T, id func(T) string) ([]T, error) {
+	items, err := state()
+	if err != nil {
+		if len(captured) == 0 {
+			return nil, err
+		}
+		logrus.Debugf("页面状态读取失败，使用接口数据: %v", err)
+		items = nil
+	}
+
+	index := make(map[string]int, len(items)+len(captured))
+	out := make([]T, 0, len(items)+len(captured))
+	for _, list := range [][]T{items, captured} {
+		for _, item := range list {
+			key := id(item)
+			if key == "" {
+				continue
+			}
+			if i, ok := index[key]; ok {
+				out[i] = item
+				continue
+			}
+			index[key] = len(out)
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func feedID(f Feed) string { return f.ID }
+
+// apiEnvelope 网页版接口的通用响应外层
+type apiEnvelope[T any] struct {
+	Code    int    `json:"code"`
+	Success bool   `json:"success"`
+	Msg     string `json:"msg"`
+	Data    T      `json:"data"`
+}
+
+func decodeEnvelope[T any](body []byte, api string) (T, error) {
+	var resp apiEnvelope[T]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return resp.Data, errors.Wrapf(err, "unmarshal %s response failed", api)
+	}
+	if !resp.Success {
+		return resp.Data, fmt.Errorf("%s failed: code=%d %s", api, resp.Code, resp.Msg)
+	}
+	return resp.Data, nil
+}
+
+// apiUser 接口中的用户字段
+type apiUser struct {
+	UserID   string `json:"user_id"`
+	Nickname string `json:"nickname"`
+	NickName string `json:"nick_name"`
+	Avatar   string `json:"avatar"`
+	Image    string `json:"image"`
+}
+
+func (u apiUser) toUser() User {
+	avatar := u.Avatar
+	if avatar == "" {
+		avatar = u.Image
+	}
+	return User{UserID: u.UserID, Nickname: u.Nickname, NickName: u.NickName, Avatar: avatar}
+}
+
+// apiNoteCard 列表接口中的笔记卡片，字段与 __INITIAL_STATE__ 相同但为下划线命名
+type apiNoteCard struct {
+	Type         string  `json:"type"`
+	DisplayTitle string  `json:"display_title"`
+	User         apiUser `json:"user"`
+	InteractInfo struct {
+		Liked          bool   `json:"liked"`
+		LikedCount     string `json:"liked_count"`
+		SharedCount    string `json:"shared_count"`
+		CommentCount   string `json:"comment_count"`
+		CollectedCount string `json:"collected_count"`
+		Collected      bool   `json:"collected"`
+	} `json:"interact_info"`
+	Cover struct {
+		Width      int    `json:"width"`
+		Height     int    `json:"height"`
+		URL        string `json:"url"`
+		FileID     string `json:"file_id"`
+		URLPre     string `json:"url_pre"`
+		URLDefault string `json:"url_default"`
+		InfoList   []struct {
+			ImageScene string `json:"image_scene"`
+			URL        string `json:"url"`
+		} `json:"info_list"`
+	} `json:"cover"`
+	Video *struct {
+		Capa struct {
+			Duration int `json:"duration"`
+		} `json:"capa"`
+	} `json:"video,omitempty"`
+}
+
+func (n apiNoteCard) toNoteCard() NoteCard {
+	card := NoteCard{
+		Type:         n.Type,
+		DisplayTitle: n.DisplayTitle,
+		User:         n.User.toUser(),
+		InteractInfo: InteractInfo{
+			Liked:          n.InteractInfo.Liked,
+			LikedCount:     n.InteractInfo.LikedCount,
+			SharedCount:    n.InteractInfo.SharedCount,
+			CommentCount:   n.InteractInfo.CommentCount,
+			CollectedCount: n.InteractInfo.CollectedCount,
+			Collected:      n.InteractInfo.Collected,
+		},
+		Cover: Cover{
+			Width:      n.Cover.Width,
+			Height:     n.Cover.Height,
+			URL:        n.Cover.URL,
+			FileID:     n.Cover.FileID,
+			URLPre:     n.Cover.URLPre,
+			URLDefault: n.Cover.URLDefault,
+		},
+	}
+	for _, info := range n.Cover.InfoList {
+		card.Cover.InfoList = append(card.Cover.InfoList, ImageInfo{ImageScene: info.ImageScene, URL: info.URL})
+	}
+	if n.Video != nil {
+		card.Video = &Video{Capa: VideoCapability{Duration: n.Video.Capa.Duration}}
+	}
+	return card
+}
+
+// apiFeedItem homefeed 与 search/notes 的 items 条目
+type apiFeedItem struct {
+	ID        string      `json:"id"`
+	ModelType string      `json:"model_type"`
+	XsecToken string      `json:"xsec_token"`
+	NoteCard  apiNoteCard `json:"note_card"`
+}
+
+// toFeeds 只保留笔记，搜索结果中夹杂的热门搜索词等卡片丢弃
+func toFeeds(items []apiFeedItem) []Feed {
+	feeds := make([]Feed, 0, len(items))
+	for _, it := range items {
+		if it.ModelType != "note" {
+			continue
+		}
+		feeds = append(feeds, Feed{
+			XsecToken: it.XsecToken,
+			ID:        it.ID,
+			ModelType: it.ModelType,
+			NoteCard:  it.NoteCard.toNoteCard(),
+			Index:     len(feeds),
+		})
+	}
+	return feeds
+}
+
+// decodeHomefeed 解析首页推荐流 homefeedAPI 的响应
+func decodeHomefeed(body []byte) (apiPage[Feed], error) {
+	data, err := decodeEnvelope[struct {
+		CursorScore string        `json:"cursor_score"`
+		Items       []apiFeedItem `json:"items"`
+	}](body, "homefeed")
+	if err != nil {
+		return apiPage[Feed]{}, err
+	}
+	// 推荐流没有尽头，有游标即视为还有更多
+	return apiPage[Feed]{Items: toFeeds(data.Items), Cursor: data.CursorScore, HasMore: data.CursorScore != ""}, nil
+}
+
+// decodeSearchNotes 解析搜索结果 searchNotesAPI 的响应
+func decodeSearchNotes(body []byte) (apiPage[Feed], error) {
+	data, err := decodeEnvelope[struct {
+		HasMore bool          `json:"has_more"`
+		Items   []apiFeedItem `json:"items"`
+	}](body, "search/notes")
+	if err != nil {
+		return apiPage[Feed]{}, err
+	}
+	return apiPage[Feed]{Items: toFeeds(data.Items), HasMore: data.HasMore}, nil
+}
+
+// decodeUserPosted 解析用户主页笔记列表 userPostedAPI 的响应
+func decodeUserPosted(body []byte) (apiPage[Feed], error) {
+	data, err := decodeEnvelope[struct {
+		Cursor  string `json:"cursor"`
+		HasMore bool   `json:"has_more"`
+		Notes   []struct {
+			NoteID    string `json:"note_id"`
+			XsecToken string `json:"xsec_token"`
+			apiNoteCard
+		} `json:"notes"`
+	}](body, "user_posted")
+	if err != nil {
+		return apiPage[Feed]{}, err
+	}
+	feeds := make([]Feed, 0, len(data.Notes))
+	for _, n := range data.Notes {
+		feeds = append(feeds, Feed{
+			XsecToken: n.XsecToken,
+			ID:        n.NoteID,
+			ModelType: "note",
+			NoteCard:  n.apiNoteCard.toNoteCard(),
+			Index:     len(feeds),
+		})
+	}
+	return apiPage[Feed]{Items: feeds, Cursor: data.Cursor, HasMore: data.HasMore}, nil
+}
+
+// apiComment comment/page 中的评论，sub_comments 为首屏展示的部分回复
+type apiComment struct {
+	ID              string       `json:"id"`
+	NoteID          string       `json:"note_id"`
+	Content         string       `json:"content"`
+	LikeCount       string       `json:"like_count"`
+	CreateTime      int64        `json:"create_time"`
+	IPLocation      string       `json:"ip_location"`
+	Liked           bool         `json:"liked"`
+	UserInfo        apiUser      `json:"user_info"`
+	SubCommentCount string       `json:"sub_comment_count"`
+	SubComments     []apiComment `json:"sub_comments"`
+	ShowTags        []string     `json:"show_tags"`
+}
+
+func (c apiComment) toComment() Comment {
+	out := Comment{
+		ID:              c.ID,
+		NoteID:          c.NoteID,
+		Content:         c.Content,
+		LikeCount:       c.LikeCount,
+		CreateTime:      c.CreateTime,
+		IPLocation:      c.IPLocation,
+		Liked:           c.Liked,
+		UserInfo:        c.UserInfo.toUser(),
+		SubCommentCount: c.SubCommentCount,
+		ShowTags:        c.ShowTags,
+	}
+	for _, sub := range c.SubComments {
+		out.SubComments = append(out.SubComments, sub.toComment())
+	}
+	return out
+}
+
+// decodeCommentPage 解析笔记评论 commentPageAPI 的响应
+func decodeCommentPage(body []byte) (apiPage[Comment], error) {
+	data, err := decodeEnvelope[struct {
+		Cursor   string       `json:"cursor"`
+		HasMore  bool         `json:"has_more"`
+		Comments []apiComment `json:"comments"`
+	}](body, "comment/page")
+	if err != nil {
+		return apiPage[Comment]{}, err
+	}
+	comments := make([]Comment, 0, len(data.Comments))
+	for _, c := range data.Comments {
+		comments = append(comments, c.toComment())
+	}
+	return apiPage[Comment]{Items: comments, Cursor: data.Cursor, HasMore: data.HasMore}, nil
+}
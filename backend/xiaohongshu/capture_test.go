@@ -0,0 +1,129 @@
+package xiaohongshu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeHomefeed(t *testing.T) {
+	body := []byte(`{"code":0,"success":true,"data":{"cursor_score":"1.7e9","items":[
+		{"id":"n1","model_type":"note","xsec_token":"t1","note_card":{"type":"video","display_title":"标题1",
+		 "user":{"user_id":"u1","nickname":"小红","avatar":"https://img/a.jpg"},
+		 "interact_info":{"liked":true,"liked_count":"12"},
+		 "cover":{"width":1080,"height":1440,"url_default":"https://img/c.jpg","info_list":[{"image_scene":"WB_DFT","url":"https://img/c.jpg"}]},
+		 "video":{"capa":{"duration":35}}}}
+	]}}`)
+
+	page, err := decodeHomefeed(body)
+	require.NoError(t, err)
+	assert.True(t, page.HasMore)
+	require.Len(t, page.Items, 1)
+	feed := page.Items[0]
+	assert.Equal(t, "n1", feed.ID)
+	assert.Equal(t, "t1", feed.XsecToken)
+	assert.Equal(t, "标题1", feed.NoteCard.DisplayTitle)
+	assert.Equal(t, User{UserID: "u1", Nickname: "小红", Avatar: "https://img/a.jpg"}, feed.NoteCard.User)
+	assert.Equal(t, InteractInfo{Liked: true, LikedCount: "12"}, feed.NoteCard.InteractInfo)
+	assert.Equal(t, []ImageInfo{{ImageScene: "WB_DFT", URL: "https://img/c.jpg"}}, feed.NoteCard.Cover.InfoList)
+	require.NotNil(t, feed.NoteCard.Video)
+	assert.Equal(t, 35, feed.NoteCard.Video.Capa.Duration)
+
+	_, err = decodeHomefeed([]byte(`{"code":-104,"success":false,"msg":"您当前登录的账号没有权限访问"}`))
+	assert.ErrorContains(t, err, "code=-104")
+}
+
+func TestDecodeSearchNotes(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"has_more":false,"items":[
+		{"id":"n1","model_type":"note","xsec_token":"t1","note_card":{"type":"normal","display_title":"标题1"}},
+		{"id":"q1","model_type":"hot_query"},
+		{"id":"n2","model_type":"note","xsec_token":"t2","note_card":{"type":"normal","display_title":"标题2"}}
+	]}}`)
+
+	page, err := decodeSearchNotes(body)
+	require.NoError(t, err)
+	assert.False(t, page.HasMore)
+	require.Len(t, page.Items, 2, "非笔记卡片被丢弃")
+	assert.Equal(t, "n2", page.Items[1].ID)
+	assert.Nil(t, page.Items[0].NoteCard.Video)
+}
+
+func TestDecodeUserPosted(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"cursor":"n2","has_more":true,"notes":[
+		{"note_id":"n1","xsec_token":"t1","type":"normal","display_title":"标题1","user":{"user_id":"u1","nick_name":"小红"}},
+		{"note_id":"n2","xsec_token":"t2","type":"video","display_title":"标题2","interact_info":{"liked_count":"3"}}
+	]}}`)
+
+	page, err := decodeUserPosted(body)
+	require.NoError(t, err)
+	assert.Equal(t, "n2", page.Cursor)
+	assert.True(t, page.HasMore)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, Feed{
+		ID: "n1", XsecToken: "t1", ModelType: "note",
+		NoteCard: NoteCard{Type: "normal", DisplayTitle: "标题1", User: User{UserID: "u1", NickName: "小红"}},
+	}, page.Items[0])
+	assert.Equal(t, "3", page.Items[1].NoteCard.InteractInfo.LikedCount)
+}
+
+func TestDecodeCommentPage(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"cursor":"c2","has_more":true,"comments":[
+		{"id":"c1","note_id":"n1","content":"好看","like_count":"5","create_time":1735696800000,"ip_location":"上海",
+		 "user_info":{"user_id":"u1","nickname":"小红","image":"https://img/a.jpg"},"sub_comment_count":"1",
+		 "sub_comments":[{"id":"c1-1","note_id":"n1","content":"谢谢","user_info":{"user_id":"u0"}}],
+		 "show_tags":["is_author"]}
+	]}}`)
+
+	page, err := decodeCommentPage(body)
+	require.NoError(t, err)
+	assert.Equal(t, "c2", page.Cursor)
+	require.Len(t, page.Items, 1)
+	c := page.Items[0]
+	assert.Equal(t, "好看", c.Content)
+	assert.Equal(t, int64(1735696800000), c.CreateTime)
+	assert.Equal(t, "https://img/a.jpg", c.UserInfo.Avatar)
+	assert.Equal(t, []string{"is_author"}, c.ShowTags)
+	require.Len(t, c.SubComments, 1)
+	assert.Equal(t, "u0", c.SubComments[0].UserInfo.UserID)
+}
+
+func TestReadMerged(t *testing.T) {
+	state := []Feed{{ID: "a"}, {ID: "b"}}
+	captured := []Feed{{ID: "b", XsecToken: "xt-b"}, {ID: "c"}, {ID: ""}}
+
+	// 顺序以页面状态为准，重复条目用接口数据
+	got, err := readMerged(func() ([]Feed, error) { return state, nil }, captured, feedID)
+	require.NoError(t, err)
+	assert.Equal(t, []Feed{{ID: "a"}, {ID: "b", XsecToken: "xt-b"}, {ID: "c"}}, got)
+
+	// 页面状态取不到时使用接口数据
+	errState := errors.New("state not found")
+	got, err = readMerged(func() ([]Feed, error) { return nil, errState }, captured, feedID)
+	require.NoError(t, err)
+	assert.Equal(t, []Feed{{ID: "b", XsecToken: "xt-b"}, {ID: "c"}}, got)
+
+	_, err = readMerged(func() ([]Feed, error) { return nil, errState }, nil, feedID)
+	assert.ErrorIs(t, err, errState)
+}
+
+func TestReadCaptured(t *testing.T) {
+	rec := &responseRecorder{}
+	_, ok := readCaptured(rec, decodeCommentPage)
+	assert.False(t, ok)
+
+	rec.bodies = [][]byte{
+		[]byte(`{"success":true,"data":{"cursor":"c1","has_more":true,"comments":[{"id":"c1"}]}}`),
+		[]byte(`{"success":false,"msg":"访问频繁"}`),
+		[]byte(`{"success":true,"data":{"cursor":"","has_more":false,"comments":[{"id":"c2"}]}}`),
+	}
+	page, ok := readCaptured(rec, decodeCommentPage)
+	require.True(t, ok)
+	assert.Len(t, page.Items, 2, "解析失败的响应被跳过")
+	assert.False(t, page.HasMore, "翻页信息取最后一次响应")
+
+	rec.reset()
+	_, ok = readCaptured(rec, decodeCommentPage)
+	assert.False(t, ok)
+}
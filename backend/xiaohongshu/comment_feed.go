@@ -38,7 +38,7 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 		return err
 	}
 
-	elem, err := page.Element("div.input-box div.content-edit span")
+	elem, err := findElement(page, "comment.input_trigger")
 	if err != nil {
 		logrus.Warnf("Failed to find comment input box: %v", err)
 		return fmt.Errorf("未找到评论输入框，该帖子可能不支持评论或网页端不可访问: %w", err)
@@ -49,7 +49,7 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 		return fmt.Errorf("无法点击评论输入框: %w", err)
 	}
 
-	elem2, err := page.Element("div.input-box div.content-edit p.content-input")
+	elem2, err := findElement(page, "comment.input")
 	if err != nil {
 		logrus.Warnf("Failed to find comment input field: %v", err)
 		return fmt.Errorf("未找到评论输入区域: %w", err)
@@ -62,7 +62,7 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 
 	time.Sleep(1 * time.Second)
 
-	submitButton, err := page.Element("div.bottom button.submit")
+	submitButton, err := findElement(page, "comment.submit")
 	if err != nil {
 		logrus.Warnf("Failed to find submit button: %v", err)
 		return fmt.Errorf("未找到提交按钮: %w", err)
@@ -114,7 +114,7 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	logrus.Info("准备点击回复按钮")
 
 	// 查找并点击回复按钮
-	replyBtn, err := commentEl.Element(".right .interactions .reply")
+	replyBtn, err := findChild(commentEl, "comment.reply_button")
 	if err != nil {
 		return fmt.Errorf("无法找到回复按钮: %w", err)
 	}
@@ -126,7 +126,7 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	time.Sleep(1 * time.Second)
 
 	// 查找回复输入框
-	inputEl, err := page.Element("div.input-box div.content-edit p.content-input")
+	inputEl, err := findElement(page, "comment.input")
 	if err != nil {
 		return fmt.Errorf("无法找到回复输入框: %w", err)
 	}
@@ -139,7 +139,7 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	time.Sleep(500 * time.Millisecond)
 
 	// 查找并点击提交按钮
-	submitBtn, err := page.Element("div.bottom button.submit")
+	submitBtn, err := findElement(page, "comment.submit")
 	if err != nil {
 		return fmt.Errorf("无法找到提交按钮: %w", err)
 	}
@@ -204,7 +204,7 @@ func findCommentElement(page *rod.Page, commentID, userID string) (*rod.Element,
 			logrus.Infof("滚动到最后一个评论（共 %d 条）", currentCount)
 			
 			// 使用 Go 获取所有评论元素
-			elements, err := page.Timeout(2 * time.Second).Elements(".parent-comment, .comment-item, .comment")
+			elements, err := findElements(page.Timeout(2*time.Second), "comment.item")
 			if err == nil && len(elements) > 0 {
 				// 滚动到最后一个评论
 				lastComment := elements[len(elements)-1]
@@ -229,11 +229,10 @@ func findCommentElement(page *rod.Page, commentID, userID string) (*rod.Element,
 		// === 6. 滚动后立即查找（边滚动边查找）===
 		// 优先通过 commentID 查找（使用 Timeout 避免长时间等待）
 		if commentID != "" {
-			selector := fmt.Sprintf("#comment-%s", commentID)
-			logrus.Infof("尝试通过 commentID 查找: %s", selector)
+			logrus.Infof("尝试通过 commentID 查找: %s", commentID)
 			
 			// 使用 Timeout 避免长时间等待
-			el, err := page.Timeout(2 * time.Second).Element(selector)
+			el, err := findElement(page.Timeout(2*time.Second), "comment.by_id", commentID)
 			if err == nil && el != nil {
 				logrus.Infof("✓ 通过 commentID 找到评论: %s (尝试 %d 次)", commentID, attempt+1)
 				return el, nil
@@ -246,12 +245,12 @@ func findCommentElement(page *rod.Page, commentID, userID string) (*rod.Element,
 			logrus.Infof("尝试通过 userID 查找: %s", userID)
 			
 			// 使用 Timeout 避免长时间等待
-			elements, err := page.Timeout(2 * time.Second).Elements(".comment-item, .comment, .parent-comment")
+			elements, err := findElements(page.Timeout(2*time.Second), "comment.item")
 			if err == nil && len(elements) > 0 {
 				logrus.Infof("找到 %d 个评论元素", len(elements))
 				for i, el := range elements {
 					// 快速检查，不等待
-					userEl, err := el.Timeout(500 * time.Millisecond).Element(fmt.Sprintf(`[data-user-id="%s"]`, userID))
+					userEl, err := findChild(el, "comment.by_user", userID)
 					if err == nil && userEl != nil {
 						logrus.Infof("✓ 通过 userID 在第 %d 个元素中找到评论: %s (尝试 %d 次)", i+1, userID, attempt+1)
 						return el, nil
@@ -0,0 +1,26 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostComment_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+	ctx := context.Background()
+	action := NewCommentFeedAction(page)
+
+	require.NoError(t, action.PostComment(ctx, "65f000000000000000000b01", "xt-b01", "很实用"))
+	// 第二条评论只在接口响应中，通过用户 ID 定位
+	require.NoError(t, action.ReplyToComment(ctx, "65f000000000000000000b01", "xt-b01", "", "u22", "谢谢"))
+
+	posts := srv.posted("/api/sns/web/v1/comment/post")
+	require.Len(t, posts, 2)
+	assert.Equal(t, "很实用", posts[0]["content"])
+	assert.Equal(t, "", posts[0]["target_comment_id"])
+	assert.Equal(t, "谢谢", posts[1]["content"])
+	assert.Equal(t, "65f0000000000000000c0002", posts[1]["target_comment_id"])
+}
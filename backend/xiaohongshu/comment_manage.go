@@ -0,0 +1,261 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrCommentActionNotApplied 操作后校验发现评论状态没有变化
+var ErrCommentActionNotApplied = errors.New("comment action did not take effect")
+
+// LikeComment 点赞或取消点赞指定评论，状态已符合时直接返回
+func (f *CommentFeedAction) LikeComment(ctx context.Context, feedID, xsecToken, commentID, userID string, unlike bool) error {
+	page, commentEl, err := f.openComment(feedID, xsecToken, commentID, userID)
+	if err != nil {
+		return err
+	}
+	commentID = resolveCommentID(commentEl, commentID)
+	target := !unlike
+
+	if liked, err := commentLiked(page, commentEl, feedID, commentID); err == nil && liked == target {
+		logrus.Infof("评论 %s 点赞状态已是 %v，跳过点击", commentID, liked)
+		return nil
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		likeBtn, err := findChild(commentEl, "comment.like_button")
+		if err != nil {
+			return fmt.Errorf("无法找到评论点赞按钮: %w", err)
+		}
+		if err := likeBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return fmt.Errorf("点击评论点赞按钮失败: %w", err)
+		}
+		time.Sleep(2 * time.Second)
+
+		liked, err := commentLiked(page, commentEl, feedID, commentID)
+		if err != nil {
+			logrus.Warnf("验证评论点赞状态失败: %v", err)
+			return nil
+		}
+		if liked == target {
+			logrus.Infof("评论 %s 点赞状态已变为 %v", commentID, liked)
+			return nil
+		}
+		logrus.Warnf("评论 %s 点赞状态未变化，尝试再次点击", commentID)
+	}
+	return errors.Wrapf(ErrCommentActionNotApplied, "comment %s liked != %v", commentID, target)
+}
+
+// DeleteComment 删除评论。只能删除自己的评论/回复，或自己笔记下他人的评论
+func (f *CommentFeedAction) DeleteComment(ctx context.Context, feedID, xsecToken, commentID string) error {
+	if commentID == "" {
+		return fmt.Errorf("删除评论需要 comment_id")
+	}
+	page, commentEl, err := f.openComment(feedID, xsecToken, commentID, "")
+	if err != nil {
+		return err
+	}
+
+	if err := clickCommentMenu(page, commentEl, "删除"); err != nil {
+		return err
+	}
+	if err := confirmDialog(page); err != nil {
+		return err
+	}
+	time.Sleep(2 * time.Second)
+
+	if _, err := findElement(page.Timeout(2*time.Second), "comment.by_id", commentID); err == nil {
+		return errors.Wrapf(ErrCommentActionNotApplied, "comment %s still present", commentID)
+	}
+	logrus.Infof("评论 %s 已删除", commentID)
+	return nil
+}
+
+// PinComment 在自己的笔记下置顶或取消置顶评论，状态已符合时直接返回
+func (f *CommentFeedAction) PinComment(ctx context.Context, feedID, xsecToken, commentID, userID string, unpin bool) error {
+	page, commentEl, err := f.openComment(feedID, xsecToken, commentID, userID)
+	if err != nil {
+		return err
+	}
+	commentID = resolveCommentID(commentEl, commentID)
+	target := !unpin
+
+	if commentPinned(commentEl) == target {
+		logrus.Infof("评论 %s 置顶状态已是 %v，跳过", commentID, target)
+		return nil
+	}
+
+	item := "置顶"
+	if unpin {
+		item = "取消置顶"
+	}
+	if err := clickCommentMenu(page, commentEl, item); err != nil {
+		return err
+	}
+	// 已有置顶评论时网页会提示替换
+	if err := confirmDialog(page); err != nil {
+		logrus.Debugf("置顶无需确认: %v", err)
+	}
+	time.Sleep(2 * time.Second)
+
+	// 置顶后评论会移动到顶部，重新查找
+	commentEl, err = findCommentElement(page, commentID, "")
+	if err != nil {
+		return fmt.Errorf("置顶后无法重新找到评论: %w", err)
+	}
+	if commentPinned(commentEl) != target {
+		return errors.Wrapf(ErrCommentActionNotApplied, "comment %s pinned != %v", commentID, target)
+	}
+	logrus.Infof("评论 %s 置顶状态已变为 %v", commentID, target)
+	return nil
+}
+
+// openComment 打开笔记详情页并滚动查找目标评论
+func (f *CommentFeedAction) openComment(feedID, xsecToken, commentID, userID string) (*rod.Page, *rod.Element, error) {
+	// 注意：不使用 Context(ctx)，避免继承外部 context 的超时
+	page := f.page.Timeout(5 * time.Minute)
+	url := makeFeedDetailURL(feedID, xsecToken)
+	logrus.Infof("打开 feed 详情页管理评论: %s", url)
+
+	page.MustNavigate(url)
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	if err := checkPageAccessible(page); err != nil {
+		return nil, nil, err
+	}
+	time.Sleep(2 * time.Second)
+
+	commentEl, err := findCommentElement(page, commentID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("无法找到评论: %w", err)
+	}
+	commentEl.MustScrollIntoView()
+	time.Sleep(1 * time.Second)
+	return page, commentEl, nil
+}
+
+// resolveCommentID 通过 userID 找到评论时，从元素 id（comment-<id>）中取出评论 ID
+func resolveCommentID(el *rod.Element, commentID string) string {
+	if commentID != "" {
+		return commentID
+	}
+	if id, err := el.Attribute("id"); err == nil && id != nil {
+		return strings.TrimPrefix(*id, "comment-")
+	}
+	return ""
+}
+
+// clickCommentMenu 悬停评论展开“更多”菜单并点击指定菜单项
+func clickCommentMenu(page *rod.Page, commentEl *rod.Element, item string) error {
+	if err := commentEl.Hover(); err != nil {
+		return fmt.Errorf("悬停评论失败: %w", err)
+	}
+	sleepRandom(hoverTimeRange.min, hoverTimeRange.max)
+
+	moreBtn, err := findChild(commentEl, "comment.more_button")
+	if err != nil {
+		return fmt.Errorf("无法找到评论更多按钮: %w", err)
+	}
+	if err := moreBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("点击评论更多按钮失败: %w", err)
+	}
+	sleepRandom(reactionTimeRange.min, reactionTimeRange.max)
+
+	menuItem, err := findElementR(page.Timeout(3*time.Second), "comment.menu_item", "^"+item+"$")
+	if err != nil {
+		return fmt.Errorf("菜单中没有“%s”，可能无权操作该评论: %w", item, err)
+	}
+	if err := menuItem.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("点击“%s”失败: %w", item, err)
+	}
+	sleepRandom(humanDelayRange.min, humanDelayRange.max)
+	return nil
+}
+
+// confirmDialog 点击确认弹窗中的确认按钮
+func confirmDialog(page *rod.Page) error {
+	btn, err := findElement(page.Timeout(3*time.Second), "dialog.confirm")
+	if err != nil {
+		return fmt.Errorf("未出现确认弹窗: %w", err)
+	}
+	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("点击确认失败: %w", err)
+	}
+	return nil
+}
+
+// commentLiked 读取评论点赞状态，优先使用 __INITIAL_STATE__，找不到时看按钮样式
+func commentLiked(page *rod.Page, commentEl *rod.Element, feedID, commentID string) (bool, error) {
+	if c, err := findStateComment(page, feedID, commentID); err == nil {
+		return c.Liked, nil
+	}
+	has, _, err := hasElement(commentEl, "comment.liked")
+	if err != nil {
+		return false, errors.Wrap(err, "read comment like state failed")
+	}
+	return has, nil
+}
+
+// commentPinned 评论上是否带有“置顶”标签
+func commentPinned(commentEl *rod.Element) bool {
+	tags, err := findElements(commentEl, "comment.tag")
+	if err != nil {
+		return false
+	}
+	for _, tag := range tags {
+		if text, err := tag.Text(); err == nil && strings.Contains(text, "置顶") {
+			return true
+		}
+	}
+	return false
+}
+
+// findStateComment 在 noteDetailMap 的评论列表（含子评论）中查找评论
+func findStateComment(page *rod.Page, feedID, commentID string) (*Comment, error) {
+	if commentID == "" {
+		return nil, fmt.Errorf("comment id unknown")
+	}
+	result := page.MustEval(`(feedID) => {
+		const state = window.__INITIAL_STATE__;
+		if (state && state.note && state.note.noteDetailMap && state.note.noteDetailMap[feedID]) {
+			const comments = state.note.noteDetailMap[feedID].comments;
+			if (comments) {
+				return JSON.stringify(comments.list || []);
+			}
+		}
+		return "";
+	}`, feedID).String()
+	if result == "" {
+		return nil, fmt.Errorf("feed %s comments not in noteDetailMap", feedID)
+	}
+
+	var list []Comment
+	if err := json.Unmarshal([]byte(result), &list); err != nil {
+		return nil, errors.Wrap(err, "unmarshal comments failed")
+	}
+	if c := lookupComment(list, commentID); c != nil {
+		return c, nil
+	}
+	return nil, fmt.Errorf("comment %s not in noteDetailMap", commentID)
+}
+
+func lookupComment(list []Comment, commentID string) *Comment {
+	for i := range list {
+		if list[i].ID == commentID {
+			return &list[i]
+		}
+		if c := lookupComment(list[i].SubComments, commentID); c != nil {
+			return c
+		}
+	}
+	return nil
+}
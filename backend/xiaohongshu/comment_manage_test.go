@@ -0,0 +1,47 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupComment(t *testing.T) {
+	list := []Comment{
+		{ID: "c1"},
+		{ID: "c2", SubComments: []Comment{{ID: "r1", Liked: true}}},
+	}
+
+	assert.Equal(t, "c2", lookupComment(list, "c2").ID)
+	// 回复在子评论中
+	r := lookupComment(list, "r1")
+	if assert.NotNil(t, r) {
+		assert.True(t, r.Liked)
+	}
+	assert.Nil(t, lookupComment(list, "missing"))
+}
+
+func TestManageComment_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+	ctx := context.Background()
+	action := NewCommentFeedAction(page)
+	const feedID = "65f000000000000000000b01"
+
+	// 第一条评论的点赞状态在 __INITIAL_STATE__ 中，第二条只能看按钮样式
+	require.NoError(t, action.LikeComment(ctx, feedID, "xt-b01", "", "u21", false))
+	require.NoError(t, action.LikeComment(ctx, feedID, "xt-b01", "65f0000000000000000c0002", "", false))
+	likes := srv.posted("/api/sns/web/v1/comment/like")
+	require.Len(t, likes, 2)
+	assert.Equal(t, "65f0000000000000000c0001", likes[0]["comment_id"])
+	assert.Equal(t, "65f0000000000000000c0002", likes[1]["comment_id"])
+
+	require.NoError(t, action.PinComment(ctx, feedID, "xt-b01", "65f0000000000000000c0002", "", false))
+	require.Len(t, srv.posted("/api/sns/web/v1/comment/top"), 1)
+
+	require.NoError(t, action.DeleteComment(ctx, feedID, "xt-b01", "65f0000000000000000c0001"))
+	deletes := srv.posted("/api/sns/web/v1/comment/delete")
+	require.Len(t, deletes, 1)
+	assert.Equal(t, "65f0000000000000000c0001", deletes[0]["comment_id"])
+}
@@ -0,0 +1,481 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// creatorNotesAPI 笔记管理页加载已发布笔记的接口，滚动时按页请求
+	creatorNotesAPI = "/api/galaxy/creator/note/user/posted"
+
+	creatorNotesQuery = "creator-notes"
+)
+
+// 笔记可见范围
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// ErrCreatorNoteNotFound 笔记管理页中找不到指定笔记
+var ErrCreatorNoteNotFound = errors.New("note not found in creator center")
+
+// CreatorNote 创作者中心中的一篇已发布笔记
+type CreatorNote struct {
+	NoteID      string `json:"note_id"`
+	Title       string `json:"title"`
+	Type        string `json:"type"`
+	PublishTime string `json:"publish_time"`
+	// Status 审核状态说明，审核通过时为“正常”
+	Status      string `json:"status"`
+	AuditPassed bool   `json:"audit_passed"`
+	Visibility  string `json:"visibility"`
+	Views       int    `json:"views"`
+	Likes       int    `json:"likes"`
+	Comments    int    `json:"comments"`
+	Collects    int    `json:"collects"`
+	Shares      int    `json:"shares"`
+	XsecToken   string `json:"xsec_token,omitempty"`
+}
+
+// CreatorNotePage 一页已发布笔记，NextCursor 为空表示没有更多
+type CreatorNotePage struct {
+	Notes      []CreatorNote
+	NextCursor string
+}
+
+// EditNoteContent 编辑笔记的内容，空字段保持不变
+type EditNoteContent struct {
+	Title   string
+	Content string
+	// Tags 非空时替换正文中原有的话题标签，新标签放在正文末尾
+	Tags []string
+}
+
+// topicRe 正文中的话题标签：编辑器中显示为 #标签，接口返回的正文中为 #标签[话题]#
+var topicRe = regexp.MustCompile(`#([^\s#\[\]]+)(?:\[话题\]#)?`)
+
+// stripTopics 去掉正文中的话题标签
+func stripTopics(body string) string {
+	lines := strings.Split(topicRe.ReplaceAllString(body, ""), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// bodyTopics 按出现顺序返回正文中的话题标签（不含 #），重复的只保留一次
+func bodyTopics(body string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, m := range topicRe.FindAllStringSubmatch(body, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			tags = append(tags, m[1])
+		}
+	}
+	return tags
+}
+
+// normalizeTags 去掉标签前的 # 与空白，忽略空标签和重复标签
+func normalizeTags(tags []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// creatorNoteRaw creatorNotesAPI 返回的笔记字段
+type creatorNoteRaw struct {
+	ID             string `json:"id"`
+	DisplayTitle   string `json:"display_title"`
+	Type           string `json:"type"`
+	Time           string `json:"time"`
+	Likes          int    `json:"likes"`
+	ViewCount      int    `json:"view_count"`
+	CommentsCount  int    `json:"comments_count"`
+	CollectedCount int    `json:"collected_count"`
+	SharedCount    int    `json:"shared_count"`
+	PermissionCode int    `json:"permission_code"`
+	PermissionMsg  string `json:"permission_msg"`
+	XsecToken      string `json:"xsec_token"`
+}
+
+func (r creatorNoteRaw) toNote() CreatorNote {
+	n := CreatorNote{
+		NoteID:      r.ID,
+		Title:       r.DisplayTitle,
+		Type:        r.Type,
+		PublishTime: r.Time,
+		Status:      r.PermissionMsg,
+		AuditPassed: r.PermissionCode == 0,
+		Visibility:  VisibilityPublic,
+		Views:       r.ViewCount,
+		Likes:       r.Likes,
+		Comments:    r.CommentsCount,
+		Collects:    r.CollectedCount,
+		Shares:      r.SharedCount,
+		XsecToken:   r.XsecToken,
+	}
+	if n.Status == "" {
+		n.Status = "正常"
+	}
+	if strings.Contains(r.PermissionMsg, "仅自己可见") {
+		n.Visibility = VisibilityPrivate
+	}
+	return n
+}
+
+// parseCreatorNotes 解析 creatorNotesAPI 的响应体
+func parseCreatorNotes(body []byte) ([]CreatorNote, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			Notes []creatorNoteRaw `json:"notes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal creator notes failed")
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("creator notes api failed: %s", resp.Msg)
+	}
+	notes := make([]CreatorNote, 0, len(resp.Data.Notes))
+	for _, r := range resp.Data.Notes {
+		notes = append(notes, r.toNote())
+	}
+	return notes, nil
+}
+
+// CheckCreatorNotesCursor 在打开页面前校验已发布笔记列表的游标
+func CheckCreatorNotesCursor(cursor string) error {
+	_, err := decodeCursor(cursor, creatorNotesQuery)
+	return err
+}
+
+// CreatorNotesAction 创作者中心笔记管理：列出、删除、编辑已发布笔记及修改可见范围
+type CreatorNotesAction struct {
+	page *rod.Page
+}
+
+func NewCreatorNotesAction(page *rod.Page) *CreatorNotesAction {
+	return &CreatorNotesAction{page: page}
+}
+
+// openManager 打开笔记管理页并记录笔记列表接口的响应
+func (a *CreatorNotesAction) openManager(ctx context.Context) (*rod.Page, *responseRecorder) {
+	page := a.page.Context(ctx).Timeout(5 * time.Minute)
+	rec := recordResponses(page, creatorNotesAPI)
+
+	logrus.Infof("打开笔记管理页: %s", creatorURL(pathOfNoteManager))
+	page.MustNavigate(creatorURL(pathOfNoteManager)).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+	return page, rec
+}
+
+// readNotes 合并目前所有接口响应中的笔记，按加载顺序
+func readNotes(rec *responseRecorder) ([]CreatorNote, error) {
+	var all []CreatorNote
+	for _, body := range rec.snapshot() {
+		notes, err := parseCreatorNotes(body)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, notes...)
+	}
+	return all, nil
+}
+
+// List 列出当前账号已发布的笔记，返回 opts.Cursor 之后的最多 opts.Limit 篇
+func (a *CreatorNotesAction) List(ctx context.Context, opts PageOptions) (*CreatorNotePage, error) {
+	page, rec := a.openManager(ctx)
+	defer rec.stop()
+
+	read := func() ([]CreatorNote, error) { return readNotes(rec) }
+	scroll := func(stagnant int) { humanScroll(page, "normal", stagnant > 0, 1+stagnant) }
+	notes, next, err := collectPage(ctx, creatorNotesQuery, opts, read, func(n CreatorNote) string { return n.NoteID }, scroll)
+	if err != nil {
+		return nil, err
+	}
+	return &CreatorNotePage{Notes: notes, NextCursor: next}, nil
+}
+
+// findNote 在笔记管理页中滚动查找笔记，返回笔记数据与卡片元素
+func findNote(ctx context.Context, page *rod.Page, rec *responseRecorder, noteID string) (*CreatorNote, *rod.Element, error) {
+	stagnant, lastCount := 0, -1
+	for scrolls := 0; scrolls <= pageMaxScrolls && stagnant < pageStagnantLimit; scrolls++ {
+		notes, err := readNotes(rec)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range notes {
+			if notes[i].NoteID != noteID {
+				continue
+			}
+			card, err := page.Timeout(5 * time.Second).ElementByJS(rod.Eval(`(candidates, id) =>
+				candidates.map((c) => Array.from(document.querySelectorAll(c)).find((el) => el.outerHTML.includes(id))).find((el) => el) || null`,
+				selCandidates("creator.note_card"), noteID))
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "note %s card not found", noteID)
+			}
+			return &notes[i], card, nil
+		}
+
+		if len(notes) == lastCount {
+			stagnant++
+		} else {
+			stagnant, lastCount = 0, len(notes)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		humanScroll(page, "normal", stagnant > 0, 1+stagnant)
+		sleepRandom(readTimeRange.min, readTimeRange.max)
+	}
+	return nil, nil, errors.Wrapf(ErrCreatorNoteNotFound, "note_id=%s", noteID)
+}
+
+// clickCardAction 悬停笔记卡片并点击其中的操作按钮（编辑、删除等）
+func clickCardAction(card *rod.Element, name string) error {
+	if err := card.Hover(); err != nil {
+		return errors.Wrap(err, "hover note card failed")
+	}
+	sleepRandom(hoverTimeRange.min, hoverTimeRange.max)
+
+	btn, err := findChildR(card, "creator.note_action", "^"+name+"$")
+	if err != nil {
+		return errors.Wrapf(err, "note card has no %s button", name)
+	}
+	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrapf(err, "click %s failed", name)
+	}
+	sleepRandom(humanDelayRange.min, humanDelayRange.max)
+	return nil
+}
+
+// Delete 删除已发布的笔记，并确认其从列表中消失
+func (a *CreatorNotesAction) Delete(ctx context.Context, noteID string) error {
+	page, rec := a.openManager(ctx)
+	defer rec.stop()
+
+	_, card, err := findNote(ctx, page, rec, noteID)
+	if err != nil {
+		return err
+	}
+	if err := clickCardAction(card, "删除"); err != nil {
+		return err
+	}
+	if err := confirmDialog(page); err != nil {
+		return err
+	}
+	time.Sleep(2 * time.Second)
+	rec.stop()
+
+	// 重新打开列表校验
+	page, rec2 := a.openManager(ctx)
+	defer rec2.stop()
+	if _, _, err := findNote(ctx, page, rec2, noteID); err == nil {
+		return errors.Errorf("删除后笔记 %s 仍在列表中", noteID)
+	} else if !errors.Is(err, ErrCreatorNoteNotFound) {
+		return errors.Wrap(err, "验证删除结果失败")
+	}
+	logrus.Infof("笔记 %s 已删除", noteID)
+	return nil
+}
+
+// Edit 修改已发布笔记的标题、正文或标签。标题从笔记列表校验，正文与标签从重新打开的编辑页校验
+func (a *CreatorNotesAction) Edit(ctx context.Context, noteID string, content EditNoteContent) error {
+	if content.Title == "" && content.Content == "" && len(normalizeTags(content.Tags)) == 0 {
+		return errors.New("没有需要修改的内容")
+	}
+	err := a.update(ctx, noteID, func(page *rod.Page) error {
+		return applyNoteEdits(page, content)
+	}, func(n *CreatorNote) bool {
+		return content.Title == "" || n.Title == content.Title
+	})
+	if err != nil {
+		return err
+	}
+	if content.Content == "" && len(normalizeTags(content.Tags)) == 0 {
+		return nil
+	}
+	return a.verifyBody(ctx, noteID, content)
+}
+
+// verifyBody 重新打开笔记的编辑页（正文由笔记详情接口载入），确认正文与标签已按 content 修改；不提交
+func (a *CreatorNotesAction) verifyBody(ctx context.Context, noteID string, content EditNoteContent) error {
+	page, rec := a.openManager(ctx)
+	defer rec.stop()
+
+	_, card, err := findNote(ctx, page, rec, noteID)
+	if err != nil {
+		return errors.Wrap(err, "验证修改结果失败")
+	}
+	if err := clickCardAction(card, "编辑"); err != nil {
+		return errors.Wrap(err, "验证修改结果失败")
+	}
+	page.MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	contentElem, ok := getContentElement(page)
+	if !ok {
+		return errors.New("验证修改结果失败: 没有找到内容输入框")
+	}
+	body, err := contentElem.Text()
+	if err != nil {
+		return errors.Wrap(err, "验证修改结果失败: 读取正文失败")
+	}
+
+	if content.Content != "" && stripTopics(body) != stripTopics(content.Content) {
+		return errors.Errorf("笔记 %s 修改后正文与提交的不一致（可能仍在审核中）", noteID)
+	}
+	if tags := normalizeTags(content.Tags); len(tags) > 0 {
+		got := bodyTopics(body)
+		if strings.Join(got, ",") != strings.Join(tags, ",") {
+			return errors.Errorf("笔记 %s 修改后标签为 %v，期望 %v（可能仍在审核中）", noteID, got, tags)
+		}
+	}
+	return nil
+}
+
+// applyNoteEdits 在编辑页中替换标题、正文与标签，空字段保持不变。
+// 只修改标签时保留原正文，去掉其中的话题标签后再输入新标签
+func applyNoteEdits(page *rod.Page, content EditNoteContent) error {
+	if content.Title != "" {
+		titleElem, err := findElement(page, "publish.title_input")
+		if err != nil {
+			return errors.Wrap(err, "没有找到标题输入框")
+		}
+		if err := titleElem.SelectAllText(); err != nil {
+			return errors.Wrap(err, "选中标题失败")
+		}
+		titleElem.MustInput(content.Title)
+		time.Sleep(1 * time.Second)
+	}
+	tags := normalizeTags(content.Tags)
+	if content.Content == "" && len(tags) == 0 {
+		return nil
+	}
+
+	contentElem, ok := getContentElement(page)
+	if !ok {
+		return errors.New("没有找到内容输入框")
+	}
+	body := content.Content
+	if len(tags) > 0 {
+		if body == "" {
+			text, err := contentElem.Text()
+			if err != nil {
+				return errors.Wrap(err, "读取原正文失败")
+			}
+			body = text
+		}
+		body = stripTopics(body)
+	}
+	contentElem.MustClick()
+	contentElem.MustKeyActions().Press(input.ControlLeft).Type(input.KeyA).MustDo()
+	contentElem.MustKeyActions().Type(input.Backspace).MustDo()
+	if body != "" {
+		contentElem.MustInput(body)
+	}
+	inputTags(contentElem, tags)
+	time.Sleep(1 * time.Second)
+	return nil
+}
+
+// SetVisibility 修改笔记可见范围：public 公开可见，private 仅自己可见
+func (a *CreatorNotesAction) SetVisibility(ctx context.Context, noteID, visibility string) error {
+	var option string
+	switch visibility {
+	case VisibilityPublic:
+		option = "公开可见"
+	case VisibilityPrivate:
+		option = "仅自己可见"
+	default:
+		return errors.Errorf("visibility 只能是 %s 或 %s", VisibilityPublic, VisibilityPrivate)
+	}
+
+	return a.update(ctx, noteID, func(page *rod.Page) error {
+		selectBox, err := findElement(page, "creator.visibility_select")
+		if err != nil {
+			return errors.Wrap(err, "没有找到可见范围设置")
+		}
+		selectBox.MustScrollIntoView()
+		if err := selectBox.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return errors.Wrap(err, "展开可见范围失败")
+		}
+		sleepRandom(reactionTimeRange.min, reactionTimeRange.max)
+
+		opt, err := findElementR(page.Timeout(3*time.Second), "creator.visibility_option", option)
+		if err != nil {
+			return errors.Wrapf(err, "没有找到“%s”选项", option)
+		}
+		if err := opt.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return errors.Wrapf(err, "选择“%s”失败", option)
+		}
+		sleepRandom(humanDelayRange.min, humanDelayRange.max)
+		return nil
+	}, func(n *CreatorNote) bool {
+		return n.Visibility == visibility
+	})
+}
+
+// update 从笔记管理页进入编辑页，执行 modify 后重新发布，再回到列表用 verify 校验结果
+func (a *CreatorNotesAction) update(ctx context.Context, noteID string, modify func(*rod.Page) error, verify func(*CreatorNote) bool) error {
+	page, rec := a.openManager(ctx)
+	defer rec.stop()
+
+	_, card, err := findNote(ctx, page, rec, noteID)
+	if err != nil {
+		return err
+	}
+	if err := clickCardAction(card, "编辑"); err != nil {
+		return err
+	}
+	page.MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	if err := modify(page); err != nil {
+		return err
+	}
+
+	submitButton, err := findElement(page, "publish.submit_button")
+	if err != nil {
+		return errors.Wrap(err, "没有找到发布按钮")
+	}
+	if err := submitButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "点击发布按钮失败")
+	}
+	time.Sleep(3 * time.Second)
+	rec.stop()
+
+	page, rec2 := a.openManager(ctx)
+	defer rec2.stop()
+	note, _, err := findNote(ctx, page, rec2, noteID)
+	if err != nil {
+		return errors.Wrap(err, "验证修改结果失败")
+	}
+	if !verify(note) {
+		return errors.Errorf("笔记 %s 修改后状态未变化（可能仍在审核中）", noteID)
+	}
+	logrus.Infof("笔记 %s 已更新", noteID)
+	return nil
+}
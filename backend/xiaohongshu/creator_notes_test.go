@@ -0,0 +1,78 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreatorNotes(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"notes":[
+		{"id":"n1","display_title":"标题1","type":"normal","time":"2025-01-01 10:00","likes":3,"view_count":100,"comments_count":1,"collected_count":2,"shared_count":0,"permission_code":0,"permission_msg":""},
+		{"id":"n2","display_title":"标题2","type":"video","permission_code":1,"permission_msg":"仅自己可见"}
+	]}}`)
+
+	notes, err := parseCreatorNotes(body)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+
+	assert.Equal(t, "n1", notes[0].NoteID)
+	assert.Equal(t, 100, notes[0].Views)
+	assert.Equal(t, "正常", notes[0].Status)
+	assert.True(t, notes[0].AuditPassed)
+	assert.Equal(t, VisibilityPublic, notes[0].Visibility)
+
+	assert.False(t, notes[1].AuditPassed)
+	assert.Equal(t, VisibilityPrivate, notes[1].Visibility)
+
+	_, err = parseCreatorNotes([]byte(`{"success":false,"msg":"登录已过期"}`))
+	assert.Error(t, err)
+}
+
+func TestCreatorNotes_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+	ctx := context.Background()
+	action := NewCreatorNotesAction(page)
+
+	got, err := action.List(ctx, PageOptions{})
+	require.NoError(t, err)
+	require.Len(t, got.Notes, 3)
+	assert.Equal(t, "春日穿搭", got.Notes[0].Title)
+	assert.False(t, got.Notes[2].AuditPassed)
+	assert.Equal(t, "审核中", got.Notes[2].Status)
+
+	require.NoError(t, action.Edit(ctx, "65f00000000000000000a001", EditNoteContent{Title: "春日穿搭合集", Content: "三套通勤穿搭"}))
+	edited := srv.creatorNote("65f00000000000000000a001")
+	assert.Equal(t, "春日穿搭合集", edited["display_title"])
+	assert.Equal(t, "三套通勤穿搭", edited["desc"])
+
+	// 标签替换原有标签，正文保持不变
+	require.NoError(t, action.Edit(ctx, "65f00000000000000000a001", EditNoteContent{Tags: []string{"通勤", "#春日"}}))
+	desc := srv.creatorNote("65f00000000000000000a001")["desc"].(string)
+	assert.Equal(t, "三套通勤穿搭", stripTopics(desc))
+	assert.Equal(t, []string{"通勤", "春日"}, bodyTopics(desc))
+
+	require.NoError(t, action.Edit(ctx, "65f00000000000000000a001", EditNoteContent{Tags: []string{"穿搭"}}))
+	desc = srv.creatorNote("65f00000000000000000a001")["desc"].(string)
+	assert.Equal(t, "三套通勤穿搭", stripTopics(desc))
+	assert.Equal(t, []string{"穿搭"}, bodyTopics(desc))
+
+	require.NoError(t, action.SetVisibility(ctx, "65f00000000000000000a002", VisibilityPrivate))
+	assert.Equal(t, "仅自己可见", srv.creatorNote("65f00000000000000000a002")["permission_msg"])
+
+	require.NoError(t, action.Delete(ctx, "65f00000000000000000a003"))
+	assert.Nil(t, srv.creatorNote("65f00000000000000000a003"))
+
+	err = action.Delete(ctx, "65f00000000000000000a003")
+	assert.ErrorIs(t, err, ErrCreatorNoteNotFound)
+}
+
+func TestBodyTopics(t *testing.T) {
+	body := "今天分享三套穿搭 #穿搭[话题]# #通勤\n第二行 #穿搭 #春日[话题]#"
+	assert.Equal(t, []string{"穿搭", "通勤", "春日"}, bodyTopics(body))
+	assert.Equal(t, "今天分享三套穿搭\n第二行", stripTopics(body))
+	assert.Equal(t, "没有标签", stripTopics("没有标签"))
+	assert.Equal(t, []string{"穿搭", "通勤"}, normalizeTags([]string{"#穿搭", " 通勤 ", "", "#", "穿搭"}))
+}
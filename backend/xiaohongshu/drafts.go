@@ -0,0 +1,324 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 草稿类型
+const (
+	DraftTypeImage = "image"
+	DraftTypeVideo = "video"
+)
+
+// draftTabs 草稿箱中的分类 tab，按页面顺序
+var draftTabs = []struct {
+	typ   string
+	label string
+}{
+	{DraftTypeImage, "图文"},
+	{DraftTypeVideo, "视频"},
+}
+
+// ErrDraftNotFound 草稿箱中找不到指定草稿
+var ErrDraftNotFound = errors.New("draft not found in draft box")
+
+// Draft 草稿箱中的一篇草稿。草稿只保存在网页端，没有服务端 ID，
+// DraftID 由类型、标题和保存时间计算，草稿被修改后会变化。
+// 保存时间显示为“3分钟前”“昨天 10:00”等相对时间时不参与计算，以免 ID 随时间变化。
+// 这些都相同的草稿按出现顺序加“-2”等后缀，后缀只表示位置：前面的草稿发布或删除后，后面的会改用前面的 ID。
+type Draft struct {
+	DraftID string `json:"draft_id"`
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	SavedAt string `json:"saved_at"`
+	Cover   string `json:"cover,omitempty"`
+}
+
+// draftRaw 页面上读取到的草稿条目，Index 为该条目在所属 tab 中的位置
+type draftRaw struct {
+	Title   string `json:"title"`
+	SavedAt string `json:"saved_at"`
+	Cover   string `json:"cover"`
+	Index   int    `json:"index"`
+}
+
+// draftEntry 草稿及其在页面中的位置，用于重新定位元素
+type draftEntry struct {
+	Draft
+	index int
+}
+
+// absoluteDraftTimeRe 绝对时间，如 2025-03-01 10:00、03-01 10:00、2025年3月1日
+var absoluteDraftTimeRe = regexp.MustCompile(`^(\d{4}[-/.年])?\d{1,2}[-/.月]\d{1,2}日?(\s+\d{1,2}:\d{2}(:\d{2})?)?$`)
+
+// toDrafts 为一个 tab 中的草稿计算 DraftID；类型、标题与保存时间都相同时按出现顺序加后缀区分
+func toDrafts(typ string, raws []draftRaw) []draftEntry {
+	entries := make([]draftEntry, 0, len(raws))
+	counts := map[string]int{}
+	for _, r := range raws {
+		title := strings.TrimSpace(r.Title)
+		savedAt := normalizeDraftTime(r.SavedAt)
+		key := typ + "\x00" + title
+		if absoluteDraftTimeRe.MatchString(savedAt) {
+			key += "\x00" + savedAt
+		}
+		id := fmt.Sprintf("%08x", hash32(key))
+		counts[id]++
+		if n := counts[id]; n > 1 {
+			id = fmt.Sprintf("%s-%d", id, n)
+		}
+		entries = append(entries, draftEntry{
+			Draft: Draft{DraftID: id, Type: typ, Title: title, SavedAt: savedAt, Cover: r.Cover},
+			index: r.Index,
+		})
+	}
+	return entries
+}
+
+// trimDraftSuffix 去掉 DraftID 的顺序后缀
+func trimDraftSuffix(draftID string) string {
+	base, _, _ := strings.Cut(draftID, "-")
+	return base
+}
+
+// countTwins 统计 ID 前缀为 base 的草稿数
+func countTwins(entries []draftEntry, base string) int {
+	n := 0
+	for _, e := range entries {
+		if trimDraftSuffix(e.DraftID) == base {
+			n++
+		}
+	}
+	return n
+}
+
+// normalizeDraftTime 去掉“保存于”等前缀，只保留时间文本
+func normalizeDraftTime(s string) string {
+	s = strings.TrimSpace(s)
+	for _, prefix := range []string{"保存于", "最后编辑于", "编辑于"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	return strings.TrimSpace(s)
+}
+
+// DraftsAction 草稿箱：列出草稿，打开草稿修改后发布或定时发布
+type DraftsAction struct {
+	page *rod.Page
+}
+
+func NewDraftsAction(page *rod.Page) *DraftsAction {
+	return &DraftsAction{page: page}
+}
+
+// openDraftBox 打开发布页并展开草稿箱
+func (a *DraftsAction) openDraftBox(ctx context.Context) (*rod.Page, error) {
+	page := a.page.Context(ctx).Timeout(5 * time.Minute)
+
+	logrus.Infof("打开草稿箱: %s", creatorURL(pathOfPublic))
+	page.MustNavigate(creatorURL(pathOfPublic)).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	entry, err := findElementR(page.Timeout(10*time.Second), "drafts.entry", "草稿箱")
+	if err != nil {
+		return nil, errors.Wrap(err, "没有找到草稿箱入口")
+	}
+	if err := entry.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return nil, errors.Wrap(err, "打开草稿箱失败")
+	}
+	if _, err := findElement(page.Timeout(10*time.Second), "drafts.box"); err != nil {
+		return nil, errors.Wrap(err, "草稿箱没有展开")
+	}
+	sleepRandom(reactionTimeRange.min, reactionTimeRange.max)
+	return page, nil
+}
+
+// switchDraftTab 切换到指定分类的草稿列表；页面没有分类 tab 时忽略
+func switchDraftTab(page *rod.Page, label string) {
+	tab, err := findElementR(page.Timeout(2*time.Second), "drafts.tab", label)
+	if err != nil {
+		logrus.Debugf("草稿箱没有“%s” tab: %v", label, err)
+		return
+	}
+	if err := tab.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		logrus.Warnf("切换草稿 tab “%s” 失败: %v", label, err)
+		return
+	}
+	sleepRandom(humanDelayRange.min, humanDelayRange.max)
+}
+
+// readDraftItems 读取当前 tab 中的草稿条目
+func readDraftItems(page *rod.Page) ([]draftRaw, error) {
+	result := page.MustEval(`(candidates, titleCandidates, timeCandidates) => {
+		const first = (root, cs) => cs.map((c) => root.querySelector(c)).find((el) => el) || null;
+		const items = candidates
+			.map((c) => Array.from(document.querySelectorAll(c)).filter((el) => el.offsetParent !== null))
+			.find((els) => els.length) || [];
+		return JSON.stringify(items.map((el, index) => {
+			const title = first(el, titleCandidates);
+			const time = first(el, timeCandidates);
+			const img = el.querySelector("img");
+			return {
+				title: title ? title.textContent : "",
+				saved_at: time ? time.textContent : "",
+				cover: img ? img.src : "",
+				index: index,
+			};
+		}));
+	}`, selCandidates("drafts.item"), selCandidates("drafts.item_title"), selCandidates("drafts.item_time")).String()
+
+	var raws []draftRaw
+	if err := json.Unmarshal([]byte(result), &raws); err != nil {
+		return nil, errors.Wrap(err, "unmarshal drafts failed")
+	}
+	return raws, nil
+}
+
+// List 列出草稿箱中的所有草稿
+func (a *DraftsAction) List(ctx context.Context) ([]Draft, error) {
+	page, err := a.openDraftBox(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	drafts := []Draft{}
+	for _, tab := range draftTabs {
+		switchDraftTab(page, tab.label)
+		raws, err := readDraftItems(page)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range toDrafts(tab.typ, raws) {
+			drafts = append(drafts, e.Draft)
+		}
+	}
+	return drafts, nil
+}
+
+// findDraft 在草稿箱中查找草稿，找到时停留在其所属 tab 并返回条目元素，
+// 以及该 tab 中与它 ID 前缀相同（类型、标题、保存时间都相同）的草稿数
+func findDraft(page *rod.Page, draftID string) (*Draft, *rod.Element, int, error) {
+	for _, tab := range draftTabs {
+		switchDraftTab(page, tab.label)
+		raws, err := readDraftItems(page)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		entries := toDrafts(tab.typ, raws)
+		for _, e := range entries {
+			if e.DraftID != draftID {
+				continue
+			}
+			item, err := page.ElementByJS(rod.Eval(`(candidates, index) =>
+				(candidates
+					.map((c) => Array.from(document.querySelectorAll(c)).filter((el) => el.offsetParent !== null))
+					.find((els) => els.length) || [])[index] || null`,
+				selCandidates("drafts.item"), e.index))
+			if err != nil {
+				return nil, nil, 0, errors.Wrapf(err, "draft %s item not found", draftID)
+			}
+			return &e.Draft, item, countTwins(entries, trimDraftSuffix(draftID)), nil
+		}
+	}
+	return nil, nil, 0, errors.Wrapf(ErrDraftNotFound, "draft_id=%s", draftID)
+}
+
+// countDrafts 统计 typ 分类中 ID 前缀为 base 的草稿数
+func countDrafts(page *rod.Page, typ, base string) (int, error) {
+	for _, tab := range draftTabs {
+		if tab.typ != typ {
+			continue
+		}
+		switchDraftTab(page, tab.label)
+		raws, err := readDraftItems(page)
+		if err != nil {
+			return 0, err
+		}
+		return countTwins(toDrafts(typ, raws), base), nil
+	}
+	return 0, errors.Errorf("unknown draft type %s", typ)
+}
+
+// Publish 打开草稿，按 edits 修改后发布；when 非零时设置定时发布。发布后确认草稿已从草稿箱移除
+func (a *DraftsAction) Publish(ctx context.Context, draftID string, edits EditNoteContent, when time.Time) error {
+	page, err := a.openDraftBox(ctx)
+	if err != nil {
+		return err
+	}
+	draft, item, twins, err := findDraft(page, draftID)
+	if err != nil {
+		return err
+	}
+
+	if err := item.Hover(); err != nil {
+		return errors.Wrap(err, "悬停草稿失败")
+	}
+	sleepRandom(hoverTimeRange.min, hoverTimeRange.max)
+	editBtn, err := findChildR(item, "drafts.item_action", "^编辑$")
+	if err != nil {
+		return errors.Wrap(err, "草稿没有编辑按钮")
+	}
+	if err := editBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "打开草稿失败")
+	}
+	page.MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	if err := applyNoteEdits(page, edits); err != nil {
+		return err
+	}
+	if !when.IsZero() {
+		if err := applySchedule(page, when); err != nil {
+			return err
+		}
+	}
+
+	logrus.Infof("发布草稿: id=%s type=%s title=%s scheduled=%v", draftID, draft.Type, draft.Title, !when.IsZero())
+	submitButton, err := findElement(page, "publish.submit_button")
+	if err != nil {
+		return errors.Wrap(err, "没有找到发布按钮")
+	}
+	if err := clickSubmit(submitButton); err != nil {
+		return errors.Wrap(err, "点击发布按钮失败")
+	}
+	time.Sleep(3 * time.Second)
+
+	// 已经点击发布，之后的验证失败不代表没有发出
+	if err := a.verifyPublished(ctx, draft, twins); err != nil {
+		return markSubmitted(err)
+	}
+	logrus.Infof("草稿 %s 已发布", draftID)
+	return nil
+}
+
+// verifyPublished 确认草稿已从草稿箱移除（发布成功后网页会删除该草稿）。
+// 相同草稿的 ID 只表示位置，发布后后面的草稿会改用这篇的 ID，因此比较发布前后 ID 前缀相同的草稿数
+func (a *DraftsAction) verifyPublished(ctx context.Context, draft *Draft, before int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("验证发布结果失败: %v", r)
+		}
+	}()
+
+	page, err := a.openDraftBox(ctx)
+	if err != nil {
+		return errors.Wrap(err, "验证发布结果失败")
+	}
+	after, err := countDrafts(page, draft.Type, trimDraftSuffix(draft.DraftID))
+	if err != nil {
+		return errors.Wrap(err, "验证发布结果失败")
+	}
+	if after >= before {
+		return errors.Errorf("发布后草稿 %s 仍在草稿箱中", draft.DraftID)
+	}
+	return nil
+}
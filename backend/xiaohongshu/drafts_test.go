@@ -0,0 +1,128 @@
+package xiaohongshu
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToDrafts(t *testing.T) {
+	raws := []draftRaw{
+		{Title: " 春日穿搭 ", SavedAt: "保存于 2025-03-01 10:00", Index: 0},
+		{Title: "春日穿搭", SavedAt: "2025-03-01 10:00", Index: 1},
+		{Title: "周末探店", SavedAt: "保存于 2025-03-02 09:30", Index: 2},
+	}
+
+	drafts := toDrafts(DraftTypeImage, raws)
+	require.Len(t, drafts, 3)
+	assert.Equal(t, "春日穿搭", drafts[0].Title)
+	assert.Equal(t, "2025-03-01 10:00", drafts[0].SavedAt)
+	assert.Equal(t, DraftTypeImage, drafts[0].Type)
+	assert.Equal(t, 2, drafts[2].index)
+
+	// 标题与保存时间相同的草稿按顺序加后缀
+	assert.Equal(t, drafts[0].DraftID+"-2", drafts[1].DraftID)
+	assert.NotEqual(t, drafts[0].DraftID, drafts[2].DraftID)
+	assert.Equal(t, 2, countTwins(drafts, trimDraftSuffix(drafts[1].DraftID)))
+
+	// 后缀只表示位置：前一篇移除后，后一篇改用它的 ID，只能按数量判断是否移除
+	remaining := toDrafts(DraftTypeImage, raws[1:])
+	assert.Equal(t, drafts[0].DraftID, remaining[0].DraftID)
+	assert.Equal(t, 1, countTwins(remaining, drafts[0].DraftID))
+
+	// 多次读取得到相同的 ID
+	again := toDrafts(DraftTypeImage, raws)
+	assert.Equal(t, drafts[0].DraftID, again[0].DraftID)
+
+	// 类型不同 ID 不同
+	video := toDrafts(DraftTypeVideo, raws[:1])
+	assert.NotEqual(t, drafts[0].DraftID, video[0].DraftID)
+}
+
+// 相对时间会随读取时间变化，不参与计算 ID
+func TestToDrafts_RelativeTime(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"保存于 刚刚", "保存于 3分钟前"},
+		{"5小时前", "昨天 10:00"},
+		{"昨天 10:00", "3天前"},
+	} {
+		before := toDrafts(DraftTypeImage, []draftRaw{{Title: "春日穿搭", SavedAt: pair[0]}})
+		after := toDrafts(DraftTypeImage, []draftRaw{{Title: "春日穿搭", SavedAt: pair[1]}})
+		assert.Equal(t, before[0].DraftID, after[0].DraftID, pair)
+		assert.Equal(t, normalizeDraftTime(pair[1]), after[0].SavedAt)
+	}
+
+	for _, savedAt := range []string{"2025-03-01 10:00", "03-01 10:00", "2025/3/1", "2025年3月1日 10:00"} {
+		assert.True(t, absoluteDraftTimeRe.MatchString(savedAt), savedAt)
+	}
+	a := toDrafts(DraftTypeImage, []draftRaw{{Title: "春日穿搭", SavedAt: "2025-03-01 10:00"}})
+	b := toDrafts(DraftTypeImage, []draftRaw{{Title: "春日穿搭", SavedAt: "2025-03-02 10:00"}})
+	assert.NotEqual(t, a[0].DraftID, b[0].DraftID, "绝对时间不同的草稿 ID 不同")
+}
+
+func TestDrafts_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	image := filepath.Join(dir, "cover.png")
+	require.NoError(t, os.WriteFile(image, []byte("\x89PNG\r\n\x1a\n"), 0o644))
+	video := filepath.Join(dir, "vlog.mp4")
+	require.NoError(t, os.WriteFile(video, []byte("\x00\x00\x00\x18ftypmp42"), 0o644))
+
+	// 先通过发布页的“暂存离开”各存一篇图文与视频草稿
+	action, err := NewPublishImageAction(page)
+	require.NoError(t, err)
+	require.NoError(t, action.SaveDraft(ctx, PublishImageContent{Title: "春日穿搭", Content: "三套通勤穿搭", ImagePaths: []string{image}}))
+	action, err = NewPublishVideoAction(page)
+	require.NoError(t, err)
+	require.NoError(t, action.SaveDraftVideo(ctx, PublishVideoContent{Title: "通勤 vlog", Content: "早八通勤日常", VideoPath: video}))
+
+	drafts, err := NewDraftsAction(page).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, drafts, 2)
+	assert.Equal(t, DraftTypeImage, drafts[0].Type)
+	assert.Equal(t, "春日穿搭", drafts[0].Title)
+	assert.Equal(t, DraftTypeVideo, drafts[1].Type)
+	assert.Equal(t, "通勤 vlog", drafts[1].Title)
+
+	err = NewDraftsAction(page).Publish(ctx, drafts[1].DraftID, EditNoteContent{Title: "通勤 vlog 第一期"}, time.Time{})
+	require.NoError(t, err)
+
+	notes := srv.publishedNotes()
+	require.Len(t, notes, 1)
+	assert.Equal(t, "video", notes[0]["type"])
+	assert.Equal(t, "通勤 vlog 第一期", notes[0]["title"])
+	assert.Equal(t, "早八通勤日常", notes[0]["desc"])
+	assert.Equal(t, "vlog.mp4", notes[0]["video"])
+
+	drafts, err = NewDraftsAction(page).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, drafts, 1)
+	assert.Equal(t, "春日穿搭", drafts[0].Title)
+
+	// 再存一篇相同的图文草稿，发布第一篇后第二篇会改用它的 ID，仍应判断为发布成功
+	action, err = NewPublishImageAction(page)
+	require.NoError(t, err)
+	require.NoError(t, action.SaveDraft(ctx, PublishImageContent{Title: "春日穿搭", Content: "三套通勤穿搭", ImagePaths: []string{image}}))
+	// 两篇保存时间可能跨分钟，统一成同一时间
+	page.MustEval(`() => localStorage.setItem("drafts", JSON.stringify(
+		JSON.parse(localStorage.getItem("drafts")).map((d) => ({...d, savedAt: "2025-03-01 10:00"}))))`)
+	drafts, err = NewDraftsAction(page).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, drafts, 2)
+	require.Equal(t, drafts[0].DraftID+"-2", drafts[1].DraftID)
+	require.NoError(t, NewDraftsAction(page).Publish(ctx, drafts[0].DraftID, EditNoteContent{}, time.Time{}))
+	assert.Len(t, srv.publishedNotes(), 2)
+	drafts, err = NewDraftsAction(page).List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, drafts, 1)
+
+	err = NewDraftsAction(page).Publish(ctx, "missing", EditNoteContent{}, time.Time{})
+	assert.ErrorIs(t, err, ErrDraftNotFound)
+}
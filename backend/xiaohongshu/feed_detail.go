@@ -15,6 +15,8 @@ import (
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ========== 配置常量 ==========
@@ -79,25 +81,29 @@ func (f *FeedDetailAction) GetFeedDetail(ctx context.Context, feedID, xsecToken
 func (f *FeedDetailAction) GetFeedDetailWithConfig(ctx context.Context, feedID, xsecToken string, loadAllComments bool, config CommentLoadConfig) (*FeedDetailResponse, error) {
 	page := f.page.Context(ctx).Timeout(10 * time.Minute)
 	url := makeFeedDetailURL(feedID, xsecToken)
+	rec := recordResponses(page, commentPageAPI)
+	defer rec.stop()
 
 	logrus.Infof("打开 feed 详情页: %s", url)
 	logrus.Infof("配置: 点击更多=%v, 回复阈值=%d, 最大评论数=%d, 滚动速度=%s",
 		config.ClickMoreReplies, config.MaxRepliesThreshold, config.MaxCommentItems, config.ScrollSpeed)
 
 	// 使用retry-go处理页面导航和DOM稳定等待
-	err := retry.Do(
-		func() error {
-			page.MustNavigate(url)
-			page.MustWaitDOMStable()
-			return nil
-		},
-		retry.Attempts(3),
-		retry.Delay(500*time.Millisecond),
-		retry.MaxJitter(1000*time.Millisecond),
-		retry.OnRetry(func(n uint, err error) {
-			logrus.Debugf("页面导航重试 #%d: %v", n, err)
-		}),
-	)
+	err := phase(page, "navigate", func(page *rod.Page) error {
+		return retry.Do(
+			func() error {
+				page.MustNavigate(url)
+				page.MustWaitDOMStable()
+				return nil
+			},
+			retry.Attempts(3),
+			retry.Delay(500*time.Millisecond),
+			retry.MaxJitter(1000*time.Millisecond),
+			retry.OnRetry(func(n uint, err error) {
+				logrus.Debugf("页面导航重试 #%d: %v", n, err)
+			}),
+		)
+	})
 	if err != nil {
 		logrus.Errorf("页面导航失败: %v", err)
 		return nil, err
@@ -109,12 +115,37 @@ func (f *FeedDetailAction) GetFeedDetailWithConfig(ctx context.Context, feedID,
 	}
 
 	if loadAllComments {
-		if err := f.loadAllCommentsWithConfig(page, config); err != nil {
+		err := phase(page, "load_comments", func(page *rod.Page) error {
+			return f.loadAllCommentsWithConfig(page, config)
+		})
+		if err != nil {
 			logrus.Warnf("加载全部评论失败: %v", err)
 		}
 	}
 
-	return f.extractFeedDetail(page, feedID)
+	var detail *FeedDetailResponse
+	err = phase(page, "wait_initial_state", func(page *rod.Page) (err error) {
+		detail, err = f.extractFeedDetail(page, feedID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	mergeCapturedComments(&detail.Comments, rec)
+	return detail, nil
+}
+
+// mergeCapturedComments 把评论接口返回的评论合并进页面状态中的评论列表。
+// 页面状态只保留部分已渲染的评论，接口响应包含滚动加载的全部评论及翻页信息。
+func mergeCapturedComments(list *CommentList, rec *responseRecorder) {
+	captured, ok := readCaptured(rec, decodeCommentPage)
+	if !ok {
+		return
+	}
+	merged, _ := readMerged(func() ([]Comment, error) { return list.List, nil }, captured.Items, func(c Comment) string { return c.ID })
+	logrus.Infof("合并评论: 页面状态 %d 条，接口 %d 条，合并后 %d 条", len(list.List), len(captured.Items), len(merged))
+	list.List = merged
+	list.Cursor, list.HasMore = captured.Cursor, captured.HasMore
 }
 
 // ========== 评论加载器 ==========
@@ -165,29 +196,48 @@ func (cl *commentLoader) load() error {
 	for cl.stats.attempts = 0; cl.stats.attempts < maxAttempts; cl.stats.attempts++ {
 		logrus.Debugf("=== 尝试 %d/%d ===", cl.stats.attempts+1, maxAttempts)
 
-		if cl.checkComplete() {
+		if cl.iterate() {
 			return nil
 		}
 
-		if cl.shouldClickButtons() {
-			cl.clickButtonsWithRetry()
-		}
+		time.Sleep(scrollInterval)
+	}
 
-		currentCount := getCommentCount(cl.page)
-		cl.updateState(currentCount)
+	cl.performFinalSprint()
+	return nil
+}
 
-		if cl.shouldStopAtTarget(currentCount) {
-			return nil
-		}
+// iterate 执行一轮点击与滚动，每轮记为一个 span，返回 true 表示评论已加载完成
+func (cl *commentLoader) iterate() (done bool) {
+	_, span := tracing.Start(cl.page.GetContext(), "load_comments.scroll",
+		attribute.Int("xhs.attempt", cl.stats.attempts+1))
+	defer tracing.End(span, nil)
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("xhs.comment_count", cl.state.lastCount),
+			attribute.Int("xhs.stagnant_checks", cl.state.stagnantChecks),
+			attribute.Bool("xhs.done", done),
+		)
+	}()
+
+	if cl.checkComplete() {
+		return true
+	}
 
-		cl.performScroll()
-		cl.handleStagnation()
+	if cl.shouldClickButtons() {
+		cl.clickButtonsWithRetry()
+	}
 
-		time.Sleep(scrollInterval)
+	currentCount := getCommentCount(cl.page)
+	cl.updateState(currentCount)
+
+	if cl.shouldStopAtTarget(currentCount) {
+		return true
 	}
 
-	cl.performFinalSprint()
-	return nil
+	cl.performScroll()
+	cl.handleStagnation()
+	return false
 }
 
 func (cl *commentLoader) calculateMaxAttempts() int {
@@ -349,7 +399,7 @@ func getScrollInterval(speed string) time.Duration {
 // ========== 按钮点击 ==========
 
 func clickShowMoreButtonsSmart(page *rod.Page, maxRepliesThreshold int) (clicked, skipped int) {
-	elements, err := page.Elements(".show-more")
+	elements, err := findElements(page, "comments.show_more")
 	if err != nil {
 		return 0, 0
 	}
@@ -539,7 +589,7 @@ func scrollToCommentsArea(page *rod.Page) {
 	logrus.Info("滚动到评论区...")
 
 	// 先定位到评论区
-	if el, err := page.Timeout(2 * time.Second).Element(".comments-container"); err == nil {
+	if el, err := findElement(page.Timeout(2*time.Second), "comments.container"); err == nil {
 		el.MustScrollIntoView()
 	}
 	// 等待滚动完成
@@ -551,10 +601,9 @@ func scrollToCommentsArea(page *rod.Page) {
 
 // smartScroll 智能滚动：触发滚轮事件以正确触发懒加载
 func smartScroll(page *rod.Page, delta float64) {
-	page.MustEval(`(delta) => {
-		// 查找滚动目标元素
-		let targetElement = document.querySelector('.note-scroller') 
-			|| document.querySelector('.interaction-container') 
+	page.MustEval(`(delta, candidates) => {
+		// 按顺序查找滚动目标元素
+		let targetElement = candidates.map((c) => document.querySelector(c)).find((el) => el)
 			|| document.documentElement;
 		
 		// 触发滚轮事件（关键！这样才能触发懒加载）
@@ -566,12 +615,12 @@ func smartScroll(page *rod.Page, delta float64) {
 			view: window
 		});
 		targetElement.dispatchEvent(wheelEvent);
-	}`, delta)
+	}`, delta, selCandidates("note.scroller"))
 }
 
 func scrollToLastComment(page *rod.Page) {
 	// 获取所有主评论元素
-	elements, err := page.Timeout(2 * time.Second).Elements(".parent-comment")
+	elements, err := findElements(page.Timeout(2*time.Second), "comment.parent")
 	if err != nil || len(elements) == 0 {
 		return
 	}
@@ -618,7 +667,7 @@ func getCommentCount(page *rod.Page) int {
 	err := retry.Do(
 		func() error {
 			// 使用 Go 获取评论元素
-			elements, err := page.Timeout(2 * time.Second).Elements(".parent-comment")
+			elements, err := findElements(page.Timeout(2*time.Second), "comment.parent")
 			if err != nil {
 				return err
 			}
@@ -648,7 +697,7 @@ func getTotalCommentCount(page *rod.Page) int {
 	err := retry.Do(
 		func() error {
 			// 使用 Go 获取总评论数元素
-			totalEl, err := page.Timeout(2 * time.Second).Element(".comments-container .total")
+			totalEl, err := findElement(page.Timeout(2*time.Second), "comments.total")
 			if err != nil {
 				return err
 			}
@@ -692,7 +741,7 @@ func getTotalCommentCount(page *rod.Page) int {
 
 func checkNoCommentsArea(page *rod.Page) bool {
 	// 查找无评论区域
-	noCommentsEl, err := page.Timeout(2 * time.Second).Element(".no-comments-text")
+	noCommentsEl, err := findElement(page.Timeout(2*time.Second), "comments.empty")
 	if err != nil {
 		// 未找到无评论元素，说明有评论或评论区正常
 		return false
@@ -716,7 +765,7 @@ func checkEndContainer(page *rod.Page) bool {
 	err := retry.Do(
 		func() error {
 			// 使用 Go 查找结束容器
-			endEl, err := page.Timeout(2 * time.Second).Element(".end-container")
+			endEl, err := findElement(page.Timeout(2*time.Second), "comments.end")
 			if err != nil {
 				// 未找到元素，说明未到底部
 				result = false
@@ -757,7 +806,7 @@ func checkPageAccessible(page *rod.Page) error {
 	time.Sleep(500 * time.Millisecond)
 
 	// 查找错误提示容器
-	wrapperEl, err := page.Timeout(2 * time.Second).Element(".access-wrapper, .error-wrapper, .not-found-wrapper, .blocked-wrapper")
+	wrapperEl, err := findElement(page.Timeout(2*time.Second), "note.access_error")
 	if err != nil {
 		// 未找到错误容器，说明页面可访问
 		return nil
@@ -863,5 +912,5 @@ func (f *FeedDetailAction) extractFeedDetail(page *rod.Page, feedID string) (*Fe
 }
 
 func makeFeedDetailURL(feedID, xsecToken string) string {
-	return fmt.Sprintf("https://www.xiaohongshu.com/explore/%s?xsec_token=%s&xsec_source=pc_feed", feedID, xsecToken)
+	return siteURL(fmt.Sprintf("%s/%s?xsec_token=%s&xsec_source=pc_feed", pathOfExplore, feedID, xsecToken))
 }
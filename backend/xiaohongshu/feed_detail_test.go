@@ -0,0 +1,35 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFeedDetail_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+	ctx := context.Background()
+
+	detail, err := NewFeedDetailAction(page).GetFeedDetail(ctx, "65f000000000000000000b01", "xt-b01", false, DefaultCommentLoadConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "春日穿搭", detail.Note.Title)
+	assert.Equal(t, "小红", detail.Note.User.Nickname)
+
+	// 首屏只有第一条评论，第二条和子评论来自 comment/page 接口
+	comments := detail.Comments.List
+	require.Len(t, comments, 2)
+	assert.Equal(t, "65f0000000000000000c0001", comments[0].ID)
+	assert.Equal(t, "65f0000000000000000c0002", comments[1].ID)
+	require.Len(t, comments[0].SubComments, 1)
+	assert.Equal(t, "主页有哦", comments[0].SubComments[0].Content)
+	assert.True(t, detail.Comments.HasMore)
+}
+
+func TestGetFeedDetail_Fixture_Deleted(t *testing.T) {
+	page, _ := newFixturePage(t)
+
+	_, err := NewFeedDetailAction(page).GetFeedDetail(context.Background(), "deleted", "xt-x", false, DefaultCommentLoadConfig())
+	assert.Error(t, err)
+}
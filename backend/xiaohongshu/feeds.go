@@ -15,32 +15,58 @@ type FeedsListAction struct {
 }
 
 func NewFeedsListAction(page *rod.Page) *FeedsListAction {
-	pp := page.Timeout(60 * time.Second)
+	return &FeedsListAction{page: page.Timeout(60 * time.Second)}
+}
+
+// open 打开首页并记录推荐流接口的响应
+func (f *FeedsListAction) open(ctx context.Context) (*rod.Page, *responseRecorder) {
+	page := f.page.Context(ctx)
+	rec := recordResponses(page, homefeedAPI)
 
-	pp.MustNavigate("https://www.xiaohongshu.com")
-	pp.MustWaitDOMStable()
+	page.MustNavigate(siteURL(pathOfExplore))
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+	return page, rec
+}
 
-	return &FeedsListAction{page: pp}
+// readFeeds 合并页面状态与推荐流接口返回的 Feed
+func readFeeds(page *rod.Page, rec *responseRecorder) ([]Feed, error) {
+	captured, _ := readCaptured(rec, decodeHomefeed)
+	return readMerged(func() ([]Feed, error) { return readStateFeeds(page, "feed") }, captured.Items, feedID)
 }
 
 // GetFeedsList 获取页面的 Feed 列表数据
 func (f *FeedsListAction) GetFeedsList(ctx context.Context) ([]Feed, error) {
-	page := f.page.Context(ctx)
+	page, rec := f.open(ctx)
+	defer rec.stop()
 
-	time.Sleep(1 * time.Second)
+	return readFeeds(page, rec)
+}
+
+// GetFeedsPage 滚动首页推荐流，返回 opts.Cursor 之后的最多 opts.Limit 条 Feed
+func (f *FeedsListAction) GetFeedsPage(ctx context.Context, opts PageOptions) (*FeedPage, error) {
+	page, rec := f.open(ctx)
+	defer rec.stop()
+
+	return collectFeeds(ctx, page, feedsQuery, opts, func() ([]Feed, error) {
+		return readFeeds(page, rec)
+	})
+}
 
-	result := page.MustEval(`() => {
-		if (window.__INITIAL_STATE__ &&
-		    window.__INITIAL_STATE__.feed &&
-		    window.__INITIAL_STATE__.feed.feeds) {
-			const feeds = window.__INITIAL_STATE__.feed.feeds;
+// readStateFeeds 读取 window.__INITIAL_STATE__[store].feeds，store 为 feed（首页）或 search（搜索）。
+// 页面滚动加载的新内容也会追加到这里。
+func readStateFeeds(page *rod.Page, store string) ([]Feed, error) {
+	result := page.MustEval(`(store) => {
+		const state = window.__INITIAL_STATE__;
+		if (state && state[store] && state[store].feeds) {
+			const feeds = state[store].feeds;
 			const feedsData = feeds.value !== undefined ? feeds.value : feeds._value;
 			if (feedsData) {
 				return JSON.stringify(feedsData);
 			}
 		}
 		return "";
-	}`).String()
+	}`, store).String()
 
 	if result == "" {
 		return nil, errors.ErrNoFeeds
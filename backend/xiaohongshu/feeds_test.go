@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 )
 
 func TestGetFeedsList(t *testing.T) {
 
-	t.Skip("SKIP: 测试发布")
+	skipUnlessLive(t)
 
 	b, err := browser.New(browser.Config{Headless: false})
 	require.NoError(t, err)
@@ -21,7 +22,7 @@ func TestGetFeedsList(t *testing.T) {
 	page := b.NewPage()
 	defer page.Close()
 
-	// NewFeedsListAction 内部已经处理导航
+	// GetFeedsList 内部会打开首页
 	action := NewFeedsListAction(page)
 
 	feeds, err := action.GetFeedsList(context.Background())
@@ -84,3 +85,33 @@ func TestGetFeedsList(t *testing.T) {
 		}
 	}
 }
+
+func feedIDs(feeds []Feed) []string {
+	ids := make([]string, 0, len(feeds))
+	for _, f := range feeds {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func TestGetFeedsPage_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+	ctx := context.Background()
+
+	// 首屏来自 __INITIAL_STATE__，第 2 页起来自 homefeed 接口
+	first, err := NewFeedsListAction(page).GetFeedsPage(ctx, PageOptions{Limit: 4})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"65f000000000000000000f01", "65f000000000000000000f02",
+		"65f000000000000000000f03", "65f000000000000000000f04",
+	}, feedIDs(first.Feeds))
+	require.NotEmpty(t, first.NextCursor)
+	assert.Equal(t, "xt-f03", first.Feeds[2].XsecToken)
+	assert.Equal(t, "小绿", first.Feeds[2].NoteCard.User.Nickname)
+
+	second, err := NewFeedsListAction(page).GetFeedsPage(ctx, PageOptions{Limit: 4, Cursor: first.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"65f000000000000000000f05", "65f000000000000000000f06"}, feedIDs(second.Feeds))
+	assert.Empty(t, second.NextCursor)
+	assert.Equal(t, "https://sns-img.example/f06.jpg", second.Feeds[1].NoteCard.Cover.URLDefault)
+}
@@ -0,0 +1,282 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+)
+
+// fixtureDir 页面快照与接口响应。快照只保留 action 依赖的结构：
+// __INITIAL_STATE__、选择器用到的元素，以及滚动时请求接口的脚本。
+const fixtureDir = "testdata/fixtures"
+
+// fixtureAPIPrefixes 按文件提供响应的接口前缀，请求路径映射为 api/ 下的文件：
+// /api/sns/web/v1/search/notes?page=2 -> api/search_notes_2.json，
+// 带 user_id 参数时文件名中加上用户 ID：/api/im/web/message/list?user_id=u1 -> api/message_list_u1_1.json
+var fixtureAPIPrefixes = []string{"/api/sns/web/", "/api/im/web/", "/api/galaxy/creator/"}
+
+var apiVersionRe = regexp.MustCompile(`^v\d+/`)
+
+// skipUnlessLive 访问真实站点的测试只在设置 XHS_LIVE_TEST=1 时运行
+func skipUnlessLive(t *testing.T) {
+	t.Helper()
+	if os.Getenv("XHS_LIVE_TEST") == "" {
+		t.Skip("访问真实站点，设置 XHS_LIVE_TEST=1 后运行")
+	}
+}
+
+// fixtureServer 本地替身站点，同时充当主站与创作者中心
+type fixtureServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	loggedIn  bool
+	published []map[string]any
+	// posts 页面调用的其它写接口，按路径记录请求体
+	posts map[string][]map[string]any
+	// creatorNotes 笔记管理页的已发布笔记，删除与编辑会修改它
+	creatorNotes []map[string]any
+}
+
+func newFixtureServer(t *testing.T) *fixtureServer {
+	t.Helper()
+	s := &fixtureServer{loggedIn: true, posts: map[string][]map[string]any{}}
+	data, err := os.ReadFile(filepath.Join(fixtureDir, "api", "note_user_posted_1.json"))
+	require.NoError(t, err)
+	var posted struct {
+		Data struct {
+			Notes []map[string]any `json:"notes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &posted))
+	s.creatorNotes = posted.Data.Notes
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fixtureServer) setLoggedIn(v bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loggedIn = v
+}
+
+// publishedNotes 发布页提交的笔记
+func (s *fixtureServer) publishedNotes() []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]any(nil), s.published...)
+}
+
+// posted 页面向 path 提交的请求体
+func (s *fixtureServer) posted(path string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]any(nil), s.posts[path]...)
+}
+
+// creatorNote 笔记管理页中的笔记，已删除时返回 nil
+func (s *fixtureServer) creatorNote(id string) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, n := range s.creatorNotes {
+		if n["id"] == id {
+			return maps.Clone(n)
+		}
+	}
+	return nil
+}
+
+func (s *fixtureServer) serve(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	if r.Method == http.MethodPost && path != "/web_api/sns/v2/note" {
+		s.servePost(w, r)
+		return
+	}
+	switch {
+	case path == pathOfExplore:
+		s.mu.Lock()
+		loggedIn := s.loggedIn
+		s.mu.Unlock()
+		if loggedIn {
+			s.file(w, "explore.html")
+		} else {
+			s.file(w, "login.html")
+		}
+	case path == pathOfExplore+"/deleted":
+		s.file(w, "note_deleted.html")
+	case strings.HasPrefix(path, pathOfExplore+"/"):
+		s.file(w, "note.html")
+	case path == pathOfSearch:
+		s.file(w, "search.html")
+	case strings.HasPrefix(path, "/user/profile/"):
+		s.file(w, "profile.html")
+	case path == "/publish/publish":
+		s.file(w, "publish.html")
+	case path == "/publish/update":
+		s.file(w, "note_edit.html")
+	case path == pathOfIM:
+		s.file(w, "im.html")
+	case path == pathOfNotification:
+		s.file(w, "notification.html")
+	case path == pathOfNoteManager:
+		s.file(w, "note_manager.html")
+	case path == pathOfNoteAnalytics:
+		s.file(w, "data_analysis.html")
+	case path == pathOfAccountAnalytics:
+		s.file(w, "account_analysis.html")
+	case path == creatorNotesAPI:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, map[string]any{"success": true, "data": map[string]any{"notes": s.creatorNotes}})
+	case path == "/web_api/sns/v1/note":
+		note := s.creatorNote(r.URL.Query().Get("id"))
+		if note == nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, map[string]any{"success": true, "data": note})
+	case path == "/web_api/sns/v2/note" && r.Method == http.MethodPost:
+		var note map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.published = append(s.published, note)
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"data":{"id":"65f000000000000000000e01"}}`))
+	case strings.HasPrefix(path, "/fixtures/"):
+		s.file(w, strings.TrimPrefix(path, "/fixtures/"))
+	default:
+		for _, prefix := range fixtureAPIPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				s.file(w, apiFixture(r, prefix))
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}
+}
+
+// apiFixture 接口请求对应的响应文件
+func apiFixture(r *http.Request, prefix string) string {
+	name := apiVersionRe.ReplaceAllString(strings.TrimPrefix(r.URL.Path, prefix), "")
+	name = strings.ReplaceAll(name, "/", "_")
+	query := r.URL.Query()
+	if user := query.Get("user_id"); user != "" {
+		name += "_" + user
+	}
+	page := query.Get("page")
+	if page == "" {
+		page = "1"
+	}
+	return filepath.Join("api", name+"_"+page+".json")
+}
+
+// servePost 记录写接口的请求体；笔记管理与私信接口按真实站点返回结果
+func (s *fixtureServer) servePost(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.posts[r.URL.Path] = append(s.posts[r.URL.Path], body)
+
+	data := map[string]any{}
+	switch r.URL.Path {
+	case sendMessageAPI:
+		data = map[string]any{
+			"id":          "m-sent-" + time.Now().Format("150405.000"),
+			"sender_id":   "5f00000000000000000000aa",
+			"receiver_id": body["receiver_id"],
+			"type":        "TEXT",
+			"content":     body["content"],
+			"create_time": time.Now().UnixMilli(),
+		}
+	case "/web_api/sns/v1/note/delete":
+		for i, n := range s.creatorNotes {
+			if n["id"] == body["id"] {
+				s.creatorNotes = append(s.creatorNotes[:i:i], s.creatorNotes[i+1:]...)
+				break
+			}
+		}
+	case "/web_api/sns/v1/note/update":
+		for _, n := range s.creatorNotes {
+			if n["id"] != body["id"] {
+				continue
+			}
+			n["display_title"] = body["title"]
+			n["desc"] = body["desc"]
+			n["permission_msg"] = ""
+			if body["visibility"] == VisibilityPrivate {
+				n["permission_msg"] = "仅自己可见"
+			}
+		}
+	}
+	writeJSON(w, map[string]any{"success": true, "data": data})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *fixtureServer) file(w http.ResponseWriter, name string) {
+	data, err := os.ReadFile(filepath.Join(fixtureDir, name))
+	if err != nil {
+		http.NotFound(w, nil)
+		return
+	}
+	switch filepath.Ext(name) {
+	case ".html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	case ".js":
+		w.Header().Set("Content-Type", "application/javascript")
+	case ".json":
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Write(data)
+}
+
+// newFixturePage 启动无头浏览器并把站点地址指向替身服务。
+// 浏览器取自 ROD_BROWSER_BIN 或系统中已安装的 Chrome，都没有时跳过测试。
+func newFixturePage(t *testing.T) (*rod.Page, *fixtureServer) {
+	t.Helper()
+	bin := os.Getenv("ROD_BROWSER_BIN")
+	if bin == "" {
+		path, ok := launcher.LookPath()
+		if !ok {
+			t.Skip("没有找到 Chrome，设置 ROD_BROWSER_BIN 后运行")
+		}
+		bin = path
+	}
+
+	srv := newFixtureServer(t)
+	t.Cleanup(SetBaseURLs(srv.URL, srv.URL))
+
+	b, err := browser.New(browser.Config{Headless: true, BinPath: bin})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	page := b.NewPage()
+	t.Cleanup(func() { _ = page.Close() })
+	return page, srv
+}
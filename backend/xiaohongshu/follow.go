@@ -0,0 +1,272 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// FollowStatus 与目标用户的关注关系，取自 userPageData.extraInfo.fstatus
+type FollowStatus string
+
+const (
+	FollowStatusNone    FollowStatus = "none"    // 未关注
+	FollowStatusFollows FollowStatus = "follows" // 已关注
+	FollowStatusFans    FollowStatus = "fans"    // 对方关注了我
+	FollowStatusBoth    FollowStatus = "both"    // 互相关注
+)
+
+// Following 是否已关注对方
+func (s FollowStatus) Following() bool {
+	return s == FollowStatusFollows || s == FollowStatusBoth
+}
+
+// FollowListKind 关注或粉丝列表
+type FollowListKind string
+
+const (
+	FollowListFollowing FollowListKind = "following"
+	FollowListFollowers FollowListKind = "followers"
+)
+
+// tabText 主页互动区对应入口的文字
+func (k FollowListKind) tabText() string {
+	if k == FollowListFollowers {
+		return "粉丝"
+	}
+	return "关注"
+}
+
+// FollowUser 关注/粉丝列表中的用户
+type FollowUser struct {
+	UserID    string `json:"userId"`
+	Nickname  string `json:"nickname"`
+	Avatar    string `json:"avatar,omitempty"`
+	Desc      string `json:"desc,omitempty"`
+	XsecToken string `json:"xsecToken,omitempty"`
+}
+
+// FollowUserPage 一页关注/粉丝用户，NextCursor 为空表示没有更多
+type FollowUserPage struct {
+	Users      []FollowUser
+	NextCursor string
+}
+
+// followListQuery 关注/粉丝列表游标对应的查询
+func followListQuery(kind FollowListKind) string {
+	return "follow:" + string(kind)
+}
+
+// CheckFollowListCursor 在打开页面前校验关注/粉丝列表的游标
+func CheckFollowListCursor(cursor string, kind FollowListKind) error {
+	_, err := decodeCursor(cursor, followListQuery(kind))
+	return err
+}
+
+// FollowAction 负责关注、取关以及读取关注/粉丝列表
+type FollowAction struct {
+	page *rod.Page
+}
+
+func NewFollowAction(page *rod.Page) *FollowAction {
+	return &FollowAction{page: page}
+}
+
+// Follow 关注指定用户，如果已关注则直接返回
+func (a *FollowAction) Follow(ctx context.Context, userID, xsecToken string) (FollowStatus, error) {
+	return a.perform(ctx, userID, xsecToken, true)
+}
+
+// Unfollow 取消关注指定用户，如果未关注则直接返回
+func (a *FollowAction) Unfollow(ctx context.Context, userID, xsecToken string) (FollowStatus, error) {
+	return a.perform(ctx, userID, xsecToken, false)
+}
+
+// FollowState 打开用户主页并读取当前关注关系
+func (a *FollowAction) FollowState(ctx context.Context, userID, xsecToken string) (FollowStatus, error) {
+	page := a.preparePage(ctx, userID, xsecToken)
+	return getFollowState(page)
+}
+
+func (a *FollowAction) preparePage(ctx context.Context, userID, xsecToken string) *rod.Page {
+	page := a.page.Context(ctx).Timeout(60 * time.Second)
+	url := makeUserProfileURL(userID, xsecToken)
+	logrus.Infof("Opening user profile page for follow: %s", url)
+
+	page.MustNavigate(url)
+	page.MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	return page
+}
+
+func (a *FollowAction) perform(ctx context.Context, userID, xsecToken string, targetFollowing bool) (FollowStatus, error) {
+	page := a.preparePage(ctx, userID, xsecToken)
+
+	status, err := getFollowState(page)
+	if err != nil {
+		logrus.Warnf("failed to read follow state: %v (continue to try clicking)", err)
+	} else if status.Following() == targetFollowing {
+		logrus.Infof("user %s follow state is already %s, skip clicking", userID, status)
+		return status, nil
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if err := clickFollowButton(page, targetFollowing); err != nil {
+			return status, err
+		}
+		time.Sleep(2 * time.Second)
+
+		status, err = getFollowState(page)
+		if err != nil {
+			logrus.Warnf("验证关注状态失败: %v", err)
+			return status, nil
+		}
+		if status.Following() == targetFollowing {
+			logrus.Infof("user %s 关注状态已变为 %s", userID, status)
+			return status, nil
+		}
+		logrus.Warnf("user %s 关注状态未变化 (%s)，尝试再次点击", userID, status)
+	}
+
+	return status, fmt.Errorf("关注状态未变化: %s", status)
+}
+
+// clickFollowButton 点击关注按钮；取消关注时网页会弹出确认框
+func clickFollowButton(page *rod.Page, targetFollowing bool) error {
+	button, err := findElement(page, "profile.follow_button")
+	if err != nil {
+		return errors.Wrap(err, "follow button not found")
+	}
+	if err := button.Click("left", 1); err != nil {
+		return errors.Wrap(err, "click follow button failed")
+	}
+	if targetFollowing {
+		return nil
+	}
+
+	sleepRandom(humanDelayRange.min, humanDelayRange.max)
+	if confirm, err := findElement(page.Timeout(3*time.Second), "profile.unfollow_confirm"); err == nil {
+		if err := confirm.Click("left", 1); err != nil {
+			return errors.Wrap(err, "click unfollow confirm failed")
+		}
+	}
+	return nil
+}
+
+// getFollowState 从 __INITIAL_STATE__ 读取与主页用户的关注关系
+func getFollowState(page *rod.Page) (FollowStatus, error) {
+	result := page.MustEval(`() => {
+		if (window.__INITIAL_STATE__ &&
+		    window.__INITIAL_STATE__.user &&
+		    window.__INITIAL_STATE__.user.userPageData) {
+			const userPageData = window.__INITIAL_STATE__.user.userPageData;
+			const data = userPageData.value !== undefined ? userPageData.value : userPageData._value;
+			if (data && data.extraInfo) {
+				return JSON.stringify(data.extraInfo);
+			}
+		}
+		return "";
+	}`).String()
+	if result == "" {
+		return "", fmt.Errorf("user.userPageData.extraInfo not found in __INITIAL_STATE__")
+	}
+
+	var extraInfo struct {
+		FStatus FollowStatus `json:"fstatus"`
+	}
+	if err := json.Unmarshal([]byte(result), &extraInfo); err != nil {
+		return "", errors.Wrap(err, "unmarshal extraInfo failed")
+	}
+	return extraInfo.FStatus, nil
+}
+
+// ListMine 打开当前账号主页的关注或粉丝列表，返回 opts.Cursor 之后的最多 opts.Limit 个用户
+func (a *FollowAction) ListMine(ctx context.Context, kind FollowListKind, opts PageOptions) (*FollowUserPage, error) {
+	page := a.page.Context(ctx).Timeout(2 * time.Minute)
+
+	if err := NewNavigate(page).ToProfilePage(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to navigate to profile page via sidebar")
+	}
+	page.MustWaitStable()
+
+	tab, err := findElementR(page, "profile.interactions", kind.tabText())
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s entry not found", kind.tabText())
+	}
+	if err := tab.Click("left", 1); err != nil {
+		return nil, errors.Wrapf(err, "click %s entry failed", kind.tabText())
+	}
+	if _, err := findElement(page, "profile.follow_list"); err != nil {
+		return nil, errors.Wrapf(err, "%s list not shown", kind.tabText())
+	}
+	sleepRandom(readTimeRange.min, readTimeRange.max)
+
+	read := func() ([]FollowUser, error) { return readFollowList(page) }
+	scroll := func(stagnant int) { scrollFollowList(page, 1+stagnant) }
+	users, next, err := collectPage(ctx, followListQuery(kind), opts, read, func(u FollowUser) string { return u.UserID }, scroll)
+	if err != nil {
+		return nil, err
+	}
+	return &FollowUserPage{Users: users, NextCursor: next}, nil
+}
+
+// readFollowList 读取弹出列表中已加载的用户
+func readFollowList(page *rod.Page) ([]FollowUser, error) {
+	result := page.MustEval(`(candidates, nameCandidates, descCandidates) => {
+		const first = (root, cs) => cs.map((c) => root.querySelector(c)).find((el) => el) || null;
+		const list = first(document, candidates);
+		if (!list) {
+			return "";
+		}
+		const users = [];
+		const seen = new Set();
+		list.querySelectorAll('a[href*="/user/profile/"]').forEach((a) => {
+			const url = new URL(a.href, location.origin);
+			const userId = url.pathname.split("/").pop();
+			if (!userId || seen.has(userId)) {
+				return;
+			}
+			seen.add(userId);
+			const item = a.closest("li, .user-item") || a;
+			const name = first(item, nameCandidates);
+			const desc = first(item, descCandidates);
+			const img = item.querySelector("img");
+			users.push({
+				userId: userId,
+				nickname: (name ? name.textContent : a.textContent).trim(),
+				avatar: img ? img.src : "",
+				desc: desc ? desc.textContent.trim() : "",
+				xsecToken: url.searchParams.get("xsec_token") || "",
+			});
+		});
+		return JSON.stringify(users);
+	}`, selCandidates("profile.follow_list"), selCandidates("profile.follow_list_name"), selCandidates("profile.follow_list_desc")).String()
+	if result == "" {
+		return nil, fmt.Errorf("follow list not found")
+	}
+
+	var users []FollowUser
+	if err := json.Unmarshal([]byte(result), &users); err != nil {
+		return nil, errors.Wrap(err, "unmarshal follow list failed")
+	}
+	return users, nil
+}
+
+// scrollFollowList 滚动弹出的列表容器（而不是整个页面）以加载更多用户
+func scrollFollowList(page *rod.Page, pushCount int) {
+	for i := 0; i < pushCount; i++ {
+		page.MustEval(`(candidates) => {
+			const list = candidates.map((c) => document.querySelector(c)).find((el) => el);
+			if (list) {
+				list.scrollTop += list.clientHeight * (0.6 + Math.random() * 0.3);
+			}
+		}`, selCandidates("profile.follow_list"))
+		sleepRandom(scrollWaitRange.min, scrollWaitRange.max)
+	}
+}
@@ -0,0 +1,49 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollow_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+	ctx := context.Background()
+	action := NewFollowAction(page)
+	const userID = "5f00000000000000000000bb"
+
+	status, err := action.Follow(ctx, userID, "xt-bb")
+	require.NoError(t, err)
+	assert.Equal(t, FollowStatusFollows, status)
+
+	// 已关注时不再点击
+	status, err = action.Follow(ctx, userID, "xt-bb")
+	require.NoError(t, err)
+	assert.Equal(t, FollowStatusFollows, status)
+	assert.Len(t, srv.posted("/api/sns/web/v1/user/follow"), 1)
+
+	// 取消关注需要在确认框中确认
+	status, err = action.Unfollow(ctx, userID, "xt-bb")
+	require.NoError(t, err)
+	assert.Equal(t, FollowStatusNone, status)
+	assert.Len(t, srv.posted("/api/sns/web/v1/user/unfollow"), 1)
+}
+
+func TestListMine_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+
+	following, err := NewFollowAction(page).ListMine(context.Background(), FollowListFollowing, PageOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []FollowUser{
+		{UserID: "u31", Nickname: "穿搭博主", Desc: "每天一套穿搭", XsecToken: "xt-u31"},
+		{UserID: "u32", Nickname: "美食探店", XsecToken: "xt-u32"},
+	}, following.Users)
+
+	fans, err := NewFollowAction(page).ListMine(context.Background(), FollowListFollowers, PageOptions{Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, fans.Users, 1)
+	assert.Equal(t, "u41", fans.Users[0].UserID)
+	assert.Empty(t, fans.NextCursor, "列表已到底")
+}
@@ -19,12 +19,6 @@ type ActionResult struct {
 	Message string `json:"message"`
 }
 
-// 选择器常量
-const (
-	SelectorLikeButton    = ".interact-container .left .like-lottie"
-	SelectorCollectButton = ".interact-container .left .reds-icon.collect-icon"
-)
-
 // interactActionType 交互动作类型
 type interactActionType string
 
@@ -55,8 +49,8 @@ func (a *interactAction) preparePage(ctx context.Context, actionType interactAct
 	return page
 }
 
-func (a *interactAction) performClick(page *rod.Page, selector string) {
-	element := page.MustElement(selector)
+func (a *interactAction) performClick(page *rod.Page, name string) {
+	element := mustFindElement(page, name)
 	element.MustClick()
 }
 
@@ -106,7 +100,7 @@ func (a *LikeAction) perform(ctx context.Context, feedID, xsecToken string, targ
 }
 
 func (a *LikeAction) toggleLike(page *rod.Page, feedID string, targetLiked bool, actionType interactActionType) error {
-	a.performClick(page, SelectorLikeButton)
+	a.performClick(page, "note.like_button")
 	time.Sleep(3 * time.Second)
 
 	liked, _, err := a.getInteractState(page, feedID)
@@ -120,7 +114,7 @@ func (a *LikeAction) toggleLike(page *rod.Page, feedID string, targetLiked bool,
 	}
 
 	logrus.Warnf("feed %s %s可能未成功，状态未变化，尝试再次点击", feedID, actionType)
-	a.performClick(page, SelectorLikeButton)
+	a.performClick(page, "note.like_button")
 	time.Sleep(2 * time.Second)
 
 	liked, _, err = a.getInteractState(page, feedID)
@@ -182,7 +176,7 @@ func (a *FavoriteAction) perform(ctx context.Context, feedID, xsecToken string,
 }
 
 func (a *FavoriteAction) toggleFavorite(page *rod.Page, feedID string, targetCollected bool, actionType interactActionType) error {
-	a.performClick(page, SelectorCollectButton)
+	a.performClick(page, "note.collect_button")
 	time.Sleep(3 * time.Second)
 
 	_, collected, err := a.getInteractState(page, feedID)
@@ -196,7 +190,7 @@ func (a *FavoriteAction) toggleFavorite(page *rod.Page, feedID string, targetCol
 	}
 
 	logrus.Warnf("feed %s %s可能未成功，状态未变化，尝试再次点击", feedID, actionType)
-	a.performClick(page, SelectorCollectButton)
+	a.performClick(page, "note.collect_button")
 	time.Sleep(2 * time.Second)
 
 	_, collected, err = a.getInteractState(page, feedID)
@@ -0,0 +1,27 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLikeFavorite_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+	ctx := context.Background()
+	const feedID = "65f000000000000000000b01"
+
+	require.NoError(t, NewLikeAction(page).Like(ctx, feedID, "xt-b01"))
+	assert.Len(t, srv.posted("/api/sns/web/v1/note/like"), 1)
+
+	// 重新打开页面后是未点赞状态，取消点赞不需要点击
+	require.NoError(t, NewLikeAction(page).Unlike(ctx, feedID, "xt-b01"))
+	assert.Empty(t, srv.posted("/api/sns/web/v1/note/dislike"))
+
+	require.NoError(t, NewFavoriteAction(page).Favorite(ctx, feedID, "xt-b01"))
+	collects := srv.posted("/api/sns/web/v1/note/collect")
+	require.Len(t, collects, 1)
+	assert.Equal(t, feedID, collects[0]["note_id"])
+}
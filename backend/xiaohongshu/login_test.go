@@ -0,0 +1,35 @@
+package xiaohongshu
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogin_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+	ctx := context.Background()
+	action := NewLogin(page)
+
+	loggedIn, err := action.CheckLoginStatus(ctx)
+	require.NoError(t, err)
+	assert.True(t, loggedIn)
+
+	_, loggedIn, err = action.FetchQrcodeImage(ctx)
+	require.NoError(t, err)
+	assert.True(t, loggedIn, "已登录时不返回二维码")
+
+	srv.setLoggedIn(false)
+
+	loggedIn, err = action.CheckLoginStatus(ctx)
+	require.NoError(t, err)
+	assert.False(t, loggedIn)
+
+	src, loggedIn, err := action.FetchQrcodeImage(ctx)
+	require.NoError(t, err)
+	assert.False(t, loggedIn)
+	assert.True(t, strings.HasPrefix(src, "data:image/"), src)
+}
@@ -0,0 +1,442 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// 私信页接口：会话列表、会话消息（向上滚动加载更早的消息）、发送消息
+	conversationsAPI = "/api/im/web/conversation/list"
+	messagesAPI      = "/api/im/web/message/list"
+	sendMessageAPI   = "/api/im/web/message/send"
+
+	conversationsQuery = "im-conversations"
+
+	// MaxMessageLength 单条私信的最大字数
+	MaxMessageLength = 500
+)
+
+// ErrConversationNotFound 私信列表中找不到与该用户的会话
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// ErrConversationMismatch 打开的会话或发出的私信的对方不是目标用户
+var ErrConversationMismatch = errors.New("conversation peer mismatch")
+
+// errSendRejected 发送接口明确返回失败，私信没有发出
+var errSendRejected = errors.New("私信发送失败")
+
+// Conversation 私信会话，以对方用户 ID 标识
+type Conversation struct {
+	UserID      string `json:"user_id"`
+	Nickname    string `json:"nickname"`
+	Avatar      string `json:"avatar,omitempty"`
+	LastMessage string `json:"last_message,omitempty"`
+	LastTime    string `json:"last_time,omitempty"`
+	Unread      int    `json:"unread"`
+}
+
+// ConversationPage 一页会话，NextCursor 为空表示没有更多
+type ConversationPage struct {
+	Conversations []Conversation
+	NextCursor    string
+}
+
+// Message 一条私信，按时间从新到旧排列
+type Message struct {
+	MessageID string `json:"message_id"`
+	SenderID  string `json:"sender_id"`
+	FromMe    bool   `json:"from_me"`
+	// Type 消息类型，文字消息为 text，其它类型（图片、笔记卡片等）Content 为平台给出的摘要
+	Type      string `json:"type"`
+	Content   string `json:"content"`
+	Time      string `json:"time"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// MessagePage 一页消息，NextCursor 为空表示没有更早的消息
+type MessagePage struct {
+	Messages   []Message
+	NextCursor string
+}
+
+// messagesQuery 与某个用户的会话消息游标对应的查询
+func messagesQuery(userID string) string {
+	return "im-messages:" + userID
+}
+
+// CheckConversationsCursor 在打开页面前校验会话列表的游标
+func CheckConversationsCursor(cursor string) error {
+	_, err := decodeCursor(cursor, conversationsQuery)
+	return err
+}
+
+// CheckMessagesCursor 在打开页面前校验会话消息的游标
+func CheckMessagesCursor(cursor, userID string) error {
+	_, err := decodeCursor(cursor, messagesQuery(userID))
+	return err
+}
+
+// parseConversations 解析 conversationsAPI 的响应体
+func parseConversations(body []byte) ([]Conversation, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			Conversations []struct {
+				UserInfo struct {
+					UserID   string `json:"user_id"`
+					Nickname string `json:"nickname"`
+					Image    string `json:"image"`
+				} `json:"user_info"`
+				LastMessage struct {
+					Content    string `json:"content"`
+					CreateTime int64  `json:"create_time"`
+				} `json:"last_message"`
+				UnreadCount int `json:"unread_count"`
+			} `json:"conversations"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal conversations failed")
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("conversations api failed: %s", resp.Msg)
+	}
+	out := make([]Conversation, 0, len(resp.Data.Conversations))
+	for _, r := range resp.Data.Conversations {
+		c := Conversation{
+			UserID:      r.UserInfo.UserID,
+			Nickname:    r.UserInfo.Nickname,
+			Avatar:      r.UserInfo.Image,
+			LastMessage: r.LastMessage.Content,
+			Unread:      r.UnreadCount,
+		}
+		if r.LastMessage.CreateTime > 0 {
+			c.LastTime = time.UnixMilli(r.LastMessage.CreateTime).In(ScheduleLocation).Format(scheduleTimeLayout)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// messageRaw messagesAPI / sendMessageAPI 返回的消息字段
+type messageRaw struct {
+	ID         string `json:"id"`
+	SenderID   string `json:"sender_id"`
+	ReceiverID string `json:"receiver_id"`
+	Type       string `json:"type"`
+	Content    string `json:"content"`
+	CreateTime int64  `json:"create_time"`
+}
+
+func (r messageRaw) toMessage(peerID string) Message {
+	return Message{
+		MessageID: r.ID,
+		SenderID:  r.SenderID,
+		FromMe:    r.SenderID != peerID,
+		Type:      strings.ToLower(r.Type),
+		Content:   r.Content,
+		Time:      time.UnixMilli(r.CreateTime).In(ScheduleLocation).Format(scheduleTimeLayout),
+		Timestamp: r.CreateTime / 1000,
+	}
+}
+
+// parseMessages 解析 messagesAPI 的响应体，peerID 为对方用户 ID
+func parseMessages(body []byte, peerID string) ([]Message, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			Messages []messageRaw `json:"messages"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal messages failed")
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("messages api failed: %s", resp.Msg)
+	}
+	out := make([]Message, 0, len(resp.Data.Messages))
+	for _, r := range resp.Data.Messages {
+		out = append(out, r.toMessage(peerID))
+	}
+	return out, nil
+}
+
+// messagePeers 会话消息接口响应中出现的全部发送方与接收方
+func messagePeers(body []byte) (map[string]bool, error) {
+	var resp struct {
+		Data struct {
+			Messages []messageRaw `json:"messages"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal messages failed")
+	}
+	peers := map[string]bool{}
+	for _, m := range resp.Data.Messages {
+		peers[m.SenderID] = true
+		peers[m.ReceiverID] = true
+	}
+	return peers, nil
+}
+
+// parseSentMessage 解析 sendMessageAPI 的响应体，接收方不是 peerID 时返回 ErrConversationMismatch
+func parseSentMessage(body []byte, peerID string) (*Message, error) {
+	var resp struct {
+		Success bool       `json:"success"`
+		Msg     string     `json:"msg"`
+		Data    messageRaw `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal send message response failed")
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%w: %s", errSendRejected, resp.Msg)
+	}
+	if resp.Data.ReceiverID != peerID {
+		return nil, errors.Wrapf(ErrConversationMismatch, "私信发给了 %q 而不是 %s", resp.Data.ReceiverID, peerID)
+	}
+	m := resp.Data.toMessage(peerID)
+	return &m, nil
+}
+
+// MessagesAction 私信：列出会话、读取会话消息、发送文字消息
+type MessagesAction struct {
+	page *rod.Page
+}
+
+func NewMessagesAction(page *rod.Page) *MessagesAction {
+	return &MessagesAction{page: page}
+}
+
+// open 打开私信页并记录会话列表接口的响应
+func (a *MessagesAction) open(ctx context.Context) (*rod.Page, *responseRecorder) {
+	page := a.page.Context(ctx).Timeout(3 * time.Minute)
+	rec := recordResponses(page, conversationsAPI)
+
+	logrus.Infof("打开私信页: %s", siteURL(pathOfIM))
+	page.MustNavigate(siteURL(pathOfIM)).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+	return page, rec
+}
+
+// readConversations 合并目前所有接口响应中的会话，按加载顺序
+func readConversations(rec *responseRecorder) ([]Conversation, error) {
+	var all []Conversation
+	for _, body := range rec.snapshot() {
+		items, err := parseConversations(body)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// scrollContainer 滚动命名选择器 name 对应的列表容器，up 为 true 时向上滚动（加载更早的消息）
+func scrollContainer(page *rod.Page, name string, up bool, pushCount int) {
+	for i := 0; i < pushCount; i++ {
+		page.MustEval(`(candidates, up) => {
+			const el = candidates.map((c) => document.querySelector(c)).find((el) => el);
+			if (!el) {
+				return;
+			}
+			const delta = el.clientHeight * (0.6 + Math.random() * 0.3);
+			el.scrollTop += up ? -delta : delta;
+		}`, selCandidates(name), up)
+		sleepRandom(scrollWaitRange.min, scrollWaitRange.max)
+	}
+}
+
+// Conversations 列出私信会话（最近的在前），返回 opts.Cursor 之后的最多 opts.Limit 个
+func (a *MessagesAction) Conversations(ctx context.Context, opts PageOptions) (*ConversationPage, error) {
+	page, rec := a.open(ctx)
+	defer rec.stop()
+
+	read := func() ([]Conversation, error) { return readConversations(rec) }
+	scroll := func(stagnant int) { scrollContainer(page, "im.conversation_list", false, 1+stagnant) }
+	items, next, err := collectPage(ctx, conversationsQuery, opts, read, func(c Conversation) string { return c.UserID }, scroll)
+	if err != nil {
+		return nil, err
+	}
+	return &ConversationPage{Conversations: items, NextCursor: next}, nil
+}
+
+// openConversation 在会话列表中找到与 userID 的会话并点击打开，确认打开的确实是与 userID 的会话。
+// msgRec 为会话消息接口的记录，点击前清空，之后只包含该会话的消息。
+func openConversation(ctx context.Context, page *rod.Page, rec, msgRec *responseRecorder, userID string) error {
+	stagnant, lastCount := 0, -1
+	for scrolls := 0; scrolls <= pageMaxScrolls && stagnant < pageStagnantLimit; scrolls++ {
+		convs, err := readConversations(rec)
+		if err != nil {
+			return err
+		}
+		if hasConversation(convs, userID) {
+			// 按用户 ID 定位会话，不依赖列表顺序：接口重复加载或选择器匹配到嵌套节点时下标会错位
+			item, err := findElement(page.Timeout(5*time.Second), "im.conversation_item_by_user", userID)
+			if err != nil {
+				return errors.Wrapf(err, "conversation with %s not rendered", userID)
+			}
+			item.MustScrollIntoView()
+			msgRec.reset()
+			if err := item.Click(proto.InputMouseButtonLeft, 1); err != nil {
+				return errors.Wrap(err, "打开会话失败")
+			}
+			page.MustWaitIdle()
+			sleepRandom(reactionTimeRange.min, reactionTimeRange.max)
+			return checkConversationPeer(ctx, page, msgRec, userID)
+		}
+
+		if len(convs) == lastCount {
+			stagnant++
+		} else {
+			stagnant, lastCount = 0, len(convs)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		scrollContainer(page, "im.conversation_list", false, 1+stagnant)
+	}
+	return errors.Wrapf(ErrConversationNotFound, "user_id=%s", userID)
+}
+
+func hasConversation(convs []Conversation, userID string) bool {
+	for _, c := range convs {
+		if c.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConversationPeer 确认打开的会话对方是 userID：以会话消息接口中的发送方与接收方为准，
+// 还没有收到接口响应时看会话头部是否指向该用户。超时仍无法确认时返回错误，不在不确定的会话中操作。
+func checkConversationPeer(ctx context.Context, page *rod.Page, msgRec *responseRecorder, userID string) error {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		for _, body := range msgRec.snapshot() {
+			peers, err := messagePeers(body)
+			if err != nil {
+				return err
+			}
+			if len(peers) == 0 {
+				continue
+			}
+			if !peers[userID] {
+				return errors.Wrapf(ErrConversationMismatch, "打开的会话不是与 %s 的会话", userID)
+			}
+			return nil
+		}
+		for _, c := range selCandidates("im.chat_peer", userID) {
+			if ok, _, err := page.Has(c); err == nil && ok {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrapf(ErrConversationMismatch, "无法确认打开的会话是与 %s 的会话", userID)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// Messages 读取与 userID 的会话消息（从新到旧），返回 opts.Cursor 之后的最多 opts.Limit 条
+func (a *MessagesAction) Messages(ctx context.Context, userID string, opts PageOptions) (*MessagePage, error) {
+	page, rec := a.open(ctx)
+	defer rec.stop()
+	msgRec := recordResponses(page, messagesAPI)
+	defer msgRec.stop()
+
+	if err := openConversation(ctx, page, rec, msgRec, userID); err != nil {
+		return nil, err
+	}
+
+	read := func() ([]Message, error) {
+		var all []Message
+		for _, body := range msgRec.snapshot() {
+			items, err := parseMessages(body, userID)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, items...)
+		}
+		return all, nil
+	}
+	scroll := func(stagnant int) { scrollContainer(page, "im.message_list", true, 1+stagnant) }
+	items, next, err := collectPage(ctx, messagesQuery(userID), opts, read, func(m Message) string { return m.MessageID }, scroll)
+	if err != nil {
+		return nil, err
+	}
+	return &MessagePage{Messages: items, NextCursor: next}, nil
+}
+
+// Send 向 userID 发送一条文字私信，以发送接口的响应确认结果。
+// 点击发送后出错时私信可能已经发出，返回的错误带 ErrSubmitUncertain，不能直接重试
+func (a *MessagesAction) Send(ctx context.Context, userID, content string) (*Message, error) {
+	page, rec := a.open(ctx)
+	defer rec.stop()
+	msgRec := recordResponses(page, messagesAPI)
+	defer msgRec.stop()
+	sendRec := recordResponses(page, sendMessageAPI)
+	defer sendRec.stop()
+
+	if err := openConversation(ctx, page, rec, msgRec, userID); err != nil {
+		return nil, err
+	}
+
+	inputEl, err := findElement(page, "im.message_input")
+	if err != nil {
+		return nil, errors.Wrap(err, "没有找到私信输入框")
+	}
+	if err := inputEl.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return nil, errors.Wrap(err, "点击私信输入框失败")
+	}
+	if err := inputEl.Input(content); err != nil {
+		return nil, errors.Wrap(err, "输入私信内容失败")
+	}
+	sleepRandom(humanDelayRange.min, humanDelayRange.max)
+
+	if btn, err := findElement(page.Timeout(2*time.Second), "im.send_button"); err == nil {
+		if err := clickSubmit(btn); err != nil {
+			return nil, errors.Wrap(err, "点击发送失败")
+		}
+	} else if err := page.Keyboard.Type(input.Enter); err != nil {
+		return nil, markSubmitted(errors.Wrap(err, "发送私信失败"))
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if bodies := sendRec.snapshot(); len(bodies) > 0 {
+			msg, err := parseSentMessage(bodies[len(bodies)-1], userID)
+			if err != nil {
+				// 接口明确返回失败时没有发出，其余情况（如响应无法解析）无法确定
+				if errors.Is(err, errSendRejected) {
+					return nil, err
+				}
+				return nil, markSubmitted(err)
+			}
+			logrus.Infof("私信已发送给 %s: message_id=%s", userID, msg.MessageID)
+			return msg, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, markSubmitted(errors.New("没有收到发送结果，无法确认私信是否已发出"))
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, markSubmitted(err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,93 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConversations(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"conversations":[
+		{"id":"c1","user_info":{"user_id":"u1","nickname":"小红","image":"https://img/a.jpg"},
+		 "last_message":{"content":"你好","type":"TEXT","create_time":1735696800000},"unread_count":2},
+		{"id":"c2","user_info":{"user_id":"u2","nickname":"小蓝"},"last_message":{},"unread_count":0}
+	]}}`)
+
+	convs, err := parseConversations(body)
+	require.NoError(t, err)
+	require.Len(t, convs, 2)
+	assert.Equal(t, Conversation{
+		UserID: "u1", Nickname: "小红", Avatar: "https://img/a.jpg",
+		LastMessage: "你好", LastTime: "2025-01-01 10:00", Unread: 2,
+	}, convs[0])
+	assert.Empty(t, convs[1].LastTime, "没有消息时不填时间")
+
+	_, err = parseConversations([]byte(`{"success":false,"msg":"登录已过期"}`))
+	assert.Error(t, err)
+}
+
+func TestParseMessages(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"has_more":true,"messages":[
+		{"id":"m2","sender_id":"me","receiver_id":"u1","type":"TEXT","content":"在的","create_time":1735696860000},
+		{"id":"m1","sender_id":"u1","receiver_id":"me","type":"TEXT","content":"你好","create_time":1735696800000}
+	]}}`)
+
+	msgs, err := parseMessages(body, "u1")
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, Message{
+		MessageID: "m2", SenderID: "me", FromMe: true, Type: "text",
+		Content: "在的", Time: "2025-01-01 10:01", Timestamp: 1735696860,
+	}, msgs[0])
+	assert.False(t, msgs[1].FromMe)
+
+	sent, err := parseSentMessage([]byte(`{"success":true,"data":{"id":"m3","sender_id":"me","receiver_id":"u1","type":"TEXT","content":"好的","create_time":1735696900000}}`), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "m3", sent.MessageID)
+	assert.True(t, sent.FromMe)
+
+	_, err = parseSentMessage([]byte(`{"success":true,"data":{"id":"m4","sender_id":"me","receiver_id":"u2","type":"TEXT","content":"好的"}}`), "u1")
+	assert.ErrorIs(t, err, ErrConversationMismatch, "发给了其他人")
+	_, err = parseSentMessage([]byte(`{"success":true,"data":{"id":"m5","sender_id":"me","type":"TEXT","content":"好的"}}`), "u1")
+	assert.ErrorIs(t, err, ErrConversationMismatch, "无法确认接收方")
+
+	peers, err := messagePeers(body)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"me": true, "u1": true}, peers)
+
+	_, err = parseSentMessage([]byte(`{"success":false,"msg":"对方设置了私信权限"}`), "u1")
+	assert.ErrorContains(t, err, "对方设置了私信权限")
+	assert.ErrorIs(t, err, errSendRejected)
+}
+
+func TestMessages_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+	ctx := context.Background()
+	action := NewMessagesAction(page)
+
+	convs, err := action.Conversations(ctx, PageOptions{})
+	require.NoError(t, err)
+	require.Len(t, convs.Conversations, 2)
+	assert.Equal(t, "u51", convs.Conversations[0].UserID)
+	assert.Equal(t, 1, convs.Conversations[0].Unread)
+
+	// 打开的是第二个会话，不是列表中的第一个
+	msgs, err := action.Messages(ctx, "u52", PageOptions{})
+	require.NoError(t, err)
+	require.Len(t, msgs.Messages, 1)
+	assert.Equal(t, "期待下一期", msgs.Messages[0].Content)
+	assert.False(t, msgs.Messages[0].FromMe)
+
+	sent, err := action.Send(ctx, "u51", "好的，稍后私信你")
+	require.NoError(t, err)
+	assert.True(t, sent.FromMe)
+	assert.Equal(t, "好的，稍后私信你", sent.Content)
+	posts := srv.posted(sendMessageAPI)
+	require.Len(t, posts, 1)
+	assert.Equal(t, "u51", posts[0]["receiver_id"])
+
+	_, err = action.Messages(ctx, "u99", PageOptions{})
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+}
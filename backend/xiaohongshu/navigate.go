@@ -17,9 +17,9 @@ func NewNavigate(page *rod.Page) *NavigateAction {
 func (n *NavigateAction) ToExplorePage(ctx context.Context) error {
 	page := n.page.Context(ctx)
 
-	page.MustNavigate("https://www.xiaohongshu.com/explore").
-		MustWaitLoad().
-		MustElement(`div#app`)
+	page.MustNavigate(siteURL(pathOfExplore)).
+		MustWaitLoad()
+	mustFindElement(page, "explore.app")
 
 	return nil
 }
@@ -35,7 +35,7 @@ func (n *NavigateAction) ToProfilePage(ctx context.Context) error {
 	page.MustWaitStable()
 
 	// Find and click the "我" channel link in sidebar
-	profileLink := page.MustElement(`div.main-container li.user.side-bar-component a.link-wrapper span.channel`)
+	profileLink := mustFindElement(page, "sidebar.profile_link")
 	profileLink.MustClick()
 
 	// Wait for navigation to complete
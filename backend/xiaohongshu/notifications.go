@@ -0,0 +1,229 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationKind 通知分类，对应通知页的三个 tab
+type NotificationKind string
+
+const (
+	NotificationMentions NotificationKind = "mentions" // 评论和@
+	NotificationLikes    NotificationKind = "likes"    // 赞和收藏
+	NotificationFollows  NotificationKind = "follows"  // 新增关注
+)
+
+// ParseNotificationKind 校验通知分类
+func ParseNotificationKind(s string) (NotificationKind, error) {
+	switch k := NotificationKind(s); k {
+	case NotificationMentions, NotificationLikes, NotificationFollows:
+		return k, nil
+	}
+	return "", fmt.Errorf("通知类型只能是 %s、%s 或 %s", NotificationMentions, NotificationLikes, NotificationFollows)
+}
+
+// tabText 通知页对应 tab 的文字
+func (k NotificationKind) tabText() string {
+	switch k {
+	case NotificationLikes:
+		return "赞和收藏"
+	case NotificationFollows:
+		return "新增关注"
+	}
+	return "评论和@"
+}
+
+// api 该分类列表的接口，切换 tab 或滚动时按页请求
+func (k NotificationKind) api() string {
+	switch k {
+	case NotificationLikes:
+		return "/api/sns/web/v1/you/likes"
+	case NotificationFollows:
+		return "/api/sns/web/v1/you/connections"
+	}
+	return "/api/sns/web/v1/you/mentions"
+}
+
+func (k NotificationKind) query() string {
+	return "notifications:" + string(k)
+}
+
+// CheckNotificationsCursor 在打开页面前校验通知列表的游标
+func CheckNotificationsCursor(cursor string, kind NotificationKind) error {
+	_, err := decodeCursor(cursor, kind.query())
+	return err
+}
+
+// Notification 一条通知。评论类通知中 FeedID、XsecToken、CommentID、UserID 可直接用于回复评论。
+type Notification struct {
+	ID   string           `json:"id"`
+	Kind NotificationKind `json:"kind"`
+	// Type 平台的通知类型，如 comment/comment、mention/comment、like/note、faved/note
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Time      string `json:"time"`
+	Timestamp int64  `json:"timestamp"`
+
+	UserID        string `json:"user_id"`
+	Nickname      string `json:"nickname"`
+	UserXsecToken string `json:"user_xsec_token,omitempty"`
+
+	FeedID    string `json:"feed_id,omitempty"`
+	XsecToken string `json:"xsec_token,omitempty"`
+	NoteTitle string `json:"note_title,omitempty"`
+
+	CommentID      string `json:"comment_id,omitempty"`
+	CommentContent string `json:"comment_content,omitempty"`
+	// TargetCommentID 对方回复的是我们的哪条评论
+	TargetCommentID string `json:"target_comment_id,omitempty"`
+}
+
+// NotificationPage 一页通知，NextCursor 为空表示没有更多。
+// Loaded 为本次页面加载到的全部通知（未经过滤），用于推进已读位置。
+type NotificationPage struct {
+	Notifications []Notification
+	NextCursor    string
+	Loaded        []Notification
+}
+
+// notificationRaw 通知接口返回的 message_list 条目
+type notificationRaw struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Time     int64  `json:"time"`
+	UserInfo struct {
+		UserID    string `json:"userid"`
+		Nickname  string `json:"nickname"`
+		XsecToken string `json:"xsec_token"`
+	} `json:"user_info"`
+	ItemInfo struct {
+		ID        string `json:"id"`
+		XsecToken string `json:"xsec_token"`
+		Content   string `json:"content"`
+	} `json:"item_info"`
+	CommentInfo struct {
+		ID            string `json:"id"`
+		Content       string `json:"content"`
+		TargetComment struct {
+			ID string `json:"id"`
+		} `json:"target_comment"`
+	} `json:"comment_info"`
+}
+
+// parseNotifications 解析通知接口的响应体
+func parseNotifications(body []byte, kind NotificationKind) ([]Notification, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			MessageList []notificationRaw `json:"message_list"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "unmarshal notifications failed")
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("notifications api failed: %s", resp.Msg)
+	}
+	out := make([]Notification, 0, len(resp.Data.MessageList))
+	for _, r := range resp.Data.MessageList {
+		out = append(out, Notification{
+			ID:              r.ID,
+			Kind:            kind,
+			Type:            r.Type,
+			Title:           r.Title,
+			Time:            time.Unix(r.Time, 0).In(ScheduleLocation).Format(scheduleTimeLayout),
+			Timestamp:       r.Time,
+			UserID:          r.UserInfo.UserID,
+			Nickname:        r.UserInfo.Nickname,
+			UserXsecToken:   r.UserInfo.XsecToken,
+			FeedID:          r.ItemInfo.ID,
+			XsecToken:       r.ItemInfo.XsecToken,
+			NoteTitle:       r.ItemInfo.Content,
+			CommentID:       r.CommentInfo.ID,
+			CommentContent:  r.CommentInfo.Content,
+			TargetCommentID: r.CommentInfo.TargetComment.ID,
+		})
+	}
+	return out, nil
+}
+
+// NotificationsAction 通知页：评论和@、赞和收藏、新增关注
+type NotificationsAction struct {
+	page *rod.Page
+}
+
+func NewNotificationsAction(page *rod.Page) *NotificationsAction {
+	return &NotificationsAction{page: page}
+}
+
+// List 读取某类通知（从新到旧），返回 opts.Cursor 之后的最多 opts.Limit 条；
+// skip 非空时跳过其返回 true 的通知（如已读通知）。
+func (a *NotificationsAction) List(ctx context.Context, kind NotificationKind, opts PageOptions, skip func(Notification) bool) (*NotificationPage, error) {
+	page := a.page.Context(ctx).Timeout(3 * time.Minute)
+	rec := recordResponses(page, kind.api())
+	defer rec.stop()
+
+	logrus.Infof("打开通知页: %s (%s)", siteURL(pathOfNotification), kind.tabText())
+	page.MustNavigate(siteURL(pathOfNotification)).MustWaitIdle().MustWaitDOMStable()
+	time.Sleep(1 * time.Second)
+
+	if kind != NotificationMentions {
+		tab, err := findElementR(page.Timeout(10*time.Second), "notifications.tab", kind.tabText())
+		if err != nil {
+			return nil, errors.Wrapf(err, "没有找到“%s”tab", kind.tabText())
+		}
+		if err := tab.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return nil, errors.Wrapf(err, "切换到“%s”失败", kind.tabText())
+		}
+		page.MustWaitIdle()
+		sleepRandom(reactionTimeRange.min, reactionTimeRange.max)
+	}
+
+	var loaded []Notification
+	read := func() ([]Notification, error) {
+		all, err := readNotifications(rec, kind)
+		if err != nil {
+			return nil, err
+		}
+		loaded = all
+		if skip == nil {
+			return all, nil
+		}
+		kept := make([]Notification, 0, len(all))
+		for _, n := range all {
+			if !skip(n) {
+				kept = append(kept, n)
+			}
+		}
+		return kept, nil
+	}
+	scroll := func(stagnant int) { humanScroll(page, "normal", stagnant > 0, 1+stagnant) }
+	items, next, err := collectPage(ctx, kind.query(), opts, read, func(n Notification) string { return n.ID }, scroll)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationPage{Notifications: items, NextCursor: next, Loaded: loaded}, nil
+}
+
+// readNotifications 合并目前所有接口响应中的通知，按加载顺序
+func readNotifications(rec *responseRecorder, kind NotificationKind) ([]Notification, error) {
+	var all []Notification
+	for _, body := range rec.snapshot() {
+		items, err := parseNotifications(body, kind)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
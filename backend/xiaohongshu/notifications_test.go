@@ -0,0 +1,81 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotifications(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"message_list":[
+		{"id":"m1","type":"comment/comment","title":"回复了你的评论","time":1735696800,
+		 "user_info":{"userid":"u1","nickname":"小红","xsec_token":"ut"},
+		 "item_info":{"id":"f1","xsec_token":"ft","content":"笔记标题"},
+		 "comment_info":{"id":"c1","content":"好看","target_comment":{"id":"c0"}}},
+		{"id":"m2","type":"mention/note","title":"在笔记中@了你","time":1735696700,
+		 "user_info":{"userid":"u2","nickname":"小蓝"},
+		 "item_info":{"id":"f2","xsec_token":"ft2"}}
+	],"has_more":true,"cursor":"m2"}}`)
+
+	items, err := parseNotifications(body, NotificationMentions)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, Notification{
+		ID: "m1", Kind: NotificationMentions, Type: "comment/comment", Title: "回复了你的评论",
+		Time: "2025-01-01 10:00", Timestamp: 1735696800,
+		UserID: "u1", Nickname: "小红", UserXsecToken: "ut",
+		FeedID: "f1", XsecToken: "ft", NoteTitle: "笔记标题",
+		CommentID: "c1", CommentContent: "好看", TargetCommentID: "c0",
+	}, items[0])
+	assert.Empty(t, items[1].CommentID)
+
+	_, err = parseNotifications([]byte(`{"success":false,"msg":"登录已过期"}`), NotificationLikes)
+	assert.Error(t, err)
+}
+
+func TestParseNotificationKind(t *testing.T) {
+	for _, s := range []string{"mentions", "likes", "follows"} {
+		k, err := ParseNotificationKind(s)
+		require.NoError(t, err)
+		assert.Equal(t, NotificationKind(s), k)
+	}
+	_, err := ParseNotificationKind("comments")
+	assert.Error(t, err)
+}
+
+func TestNotifications_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+	ctx := context.Background()
+	action := NewNotificationsAction(page)
+
+	// 第一页只有两条，需要滚动加载第二页
+	got, err := action.List(ctx, NotificationMentions, PageOptions{Limit: 3}, nil)
+	require.NoError(t, err)
+	require.Len(t, got.Notifications, 3)
+	assert.Equal(t, "n-m01", got.Notifications[0].ID)
+	assert.Equal(t, "65f0000000000000000c1001", got.Notifications[0].TargetCommentID)
+	assert.Equal(t, "n-m03", got.Notifications[2].ID)
+	assert.NotEmpty(t, got.NextCursor)
+
+	rest, err := action.List(ctx, NotificationMentions, PageOptions{Limit: 3, Cursor: got.NextCursor}, nil)
+	require.NoError(t, err)
+	require.Len(t, rest.Notifications, 1)
+	assert.Equal(t, "n-m04", rest.Notifications[0].ID)
+	assert.Empty(t, rest.NextCursor)
+
+	// 切换 tab 后只读取该分类的接口
+	likes, err := action.List(ctx, NotificationLikes, PageOptions{}, nil)
+	require.NoError(t, err)
+	require.Len(t, likes.Notifications, 1)
+	assert.Equal(t, NotificationLikes, likes.Notifications[0].Kind)
+	assert.Equal(t, "faved/note", likes.Notifications[0].Type)
+
+	// 跳过的通知仍计入 Loaded
+	skipped, err := action.List(ctx, NotificationMentions, PageOptions{}, func(n Notification) bool { return n.ID == "n-m01" })
+	require.NoError(t, err)
+	require.Len(t, skipped.Notifications, 1)
+	assert.Equal(t, "n-m02", skipped.Notifications[0].ID)
+	assert.Len(t, skipped.Loaded, 2)
+}
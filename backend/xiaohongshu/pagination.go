@@ -0,0 +1,209 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPageLimit 未指定 limit 时每页返回的 Feed 数
+	DefaultPageLimit = 20
+	// MaxPageLimit 单页最多返回的 Feed 数
+	MaxPageLimit = 100
+
+	cursorVersion     = 1
+	cursorMaxSeen     = 500 // 游标最多记住的已返回 Feed 数，超出后丢弃最早的
+	pageStagnantLimit = 3   // 连续多少次滚动没有新 Feed 视为已到底
+	pageMaxScrolls    = 40
+)
+
+// ErrInvalidCursor 游标无法解析，或不属于当前列表/搜索条件
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageOptions 分页参数。Limit 与 Cursor 都为空时只返回页面首次加载的内容，不滚动。
+type PageOptions struct {
+	Limit  int
+	Cursor string
+}
+
+// FeedPage 一页 Feed，NextCursor 为空表示没有更多
+type FeedPage struct {
+	Feeds      []Feed
+	NextCursor string
+}
+
+// feedCursor 记录已经返回过的 Feed（ID 的 32 位哈希），用于下一页去重。
+// 推荐流每次打开页面顺序都不同，按偏移量翻页会重复，因此按 ID 排除。
+type feedCursor struct {
+	query uint32
+	seen  []uint32
+	set   map[uint32]bool
+}
+
+func hash32(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// decodeCursor 解析游标，token 为空时返回空游标；query 标识列表或搜索条件
+func decodeCursor(token, query string) (*feedCursor, error) {
+	c := &feedCursor{query: hash32(query), set: map[uint32]bool{}}
+	if token == "" {
+		return c, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) < 5 || raw[0] != cursorVersion || (len(raw)-5)%4 != 0 {
+		return nil, ErrInvalidCursor
+	}
+	if binary.BigEndian.Uint32(raw[1:5]) != c.query {
+		return nil, errors.Wrap(ErrInvalidCursor, "cursor belongs to a different query")
+	}
+	for i := 5; i < len(raw); i += 4 {
+		c.add(binary.BigEndian.Uint32(raw[i : i+4]))
+	}
+	return c, nil
+}
+
+func (c *feedCursor) add(h uint32) {
+	if c.set[h] {
+		return
+	}
+	c.set[h] = true
+	c.seen = append(c.seen, h)
+}
+
+func (c *feedCursor) encode() string {
+	seen := c.seen
+	if len(seen) > cursorMaxSeen {
+		seen = seen[len(seen)-cursorMaxSeen:]
+	}
+	raw := make([]byte, 5, 5+4*len(seen))
+	raw[0] = cursorVersion
+	binary.BigEndian.PutUint32(raw[1:5], c.query)
+	for _, h := range seen {
+		raw = binary.BigEndian.AppendUint32(raw, h)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// feedsQuery 首页推荐流游标对应的查询
+const feedsQuery = "feed"
+
+// searchQuery 搜索游标对应的查询，关键词或筛选条件变化后旧游标失效
+func searchQuery(keyword string, filters []FilterOption) string {
+	data, _ := json.Marshal(struct {
+		Keyword string
+		Filters []FilterOption
+	}{keyword, filters})
+	return string(data)
+}
+
+// CheckFeedsCursor 在打开页面前校验首页推荐流的游标
+func CheckFeedsCursor(cursor string) error {
+	_, err := decodeCursor(cursor, feedsQuery)
+	return err
+}
+
+// CheckSearchCursor 在打开页面前校验搜索游标是否属于该关键词与筛选条件
+func CheckSearchCursor(cursor, keyword string, filters ...FilterOption) error {
+	_, err := decodeCursor(cursor, searchQuery(keyword, filters))
+	return err
+}
+
+// normalizePageLimit 将 limit 限制在 1~MaxPageLimit，0 表示默认值
+func normalizePageLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultPageLimit
+	case limit > MaxPageLimit:
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// collectFeeds 反复读取页面上的 Feed 并模拟人工滚动加载更多，直到凑够一页或不再有新内容。
+// read 返回页面状态中当前的全部 Feed。
+func collectFeeds(ctx context.Context, page *rod.Page, query string, opts PageOptions, read func() ([]Feed, error)) (*FeedPage, error) {
+	scroll := func(stagnant int) {
+		// 停滞时加大滚动幅度，尽量触发懒加载
+		humanScroll(page, "normal", stagnant > 0, 1+stagnant)
+	}
+	feeds, next, err := collectPage(ctx, query, opts, read, func(f Feed) string { return f.ID }, scroll)
+	if err != nil {
+		return nil, err
+	}
+	return &FeedPage{Feeds: feeds, NextCursor: next}, nil
+}
+
+// collectPage 滚动加载列表并按游标分页的通用实现，id 返回条目的唯一标识，
+// scroll 加载更多内容，stagnant 为连续没有新条目的次数。
+// 返回本页条目与下一页游标，游标为空表示没有更多。
+func collectPage[T any](ctx context.Context, query string, opts PageOptions, read func() ([]T, error), id func(T) string, scroll func(stagnant int)) ([]T, string, error) {
+	cursor, err := decodeCursor(opts.Cursor, query)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := normalizePageLimit(opts.Limit)
+	initialOnly := opts.Limit <= 0 && opts.Cursor == ""
+
+	var (
+		out      []T
+		taken    = map[string]bool{}
+		stagnant int
+		more     = true
+	)
+	for scrolls := 0; ; scrolls++ {
+		items, err := read()
+		if err != nil {
+			return nil, "", err
+		}
+		added := 0
+		for _, item := range items {
+			key := id(item)
+			if key == "" || taken[key] || cursor.set[hash32(key)] {
+				continue
+			}
+			taken[key] = true
+			out = append(out, item)
+			added++
+			if len(out) == limit && !initialOnly {
+				break
+			}
+		}
+		if initialOnly || len(out) >= limit {
+			break
+		}
+		if added == 0 {
+			stagnant++
+		} else {
+			stagnant = 0
+		}
+		if stagnant >= pageStagnantLimit || scrolls >= pageMaxScrolls {
+			more = false
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+
+		scroll(stagnant)
+		sleepRandom(readTimeRange.min, readTimeRange.max)
+	}
+	logrus.Infof("分页获取: %d 条 (limit=%d, 已跳过 %d 条已返回的内容, 还有更多: %v)", len(out), limit, len(cursor.seen), more)
+
+	if !more {
+		return out, "", nil
+	}
+	for _, item := range out {
+		cursor.add(hash32(id(item)))
+	}
+	return out, cursor.encode(), nil
+}
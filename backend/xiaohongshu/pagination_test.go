@@ -0,0 +1,90 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedCursor(t *testing.T) {
+	c, err := decodeCursor("", "feed")
+	require.NoError(t, err)
+	assert.Empty(t, c.seen)
+
+	c.add(hash32("a"))
+	c.add(hash32("b"))
+	c.add(hash32("a"))
+	token := c.encode()
+
+	got, err := decodeCursor(token, "feed")
+	require.NoError(t, err)
+	assert.Len(t, got.seen, 2)
+	assert.True(t, got.set[hash32("a")])
+	assert.False(t, got.set[hash32("c")])
+
+	// 游标不能用于其他查询
+	_, err = decodeCursor(token, "search")
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+
+	for _, bad := range []string{"!!", "AQ", "AgAAAAA"} {
+		_, err = decodeCursor(bad, "feed")
+		assert.True(t, errors.Is(err, ErrInvalidCursor), bad)
+	}
+}
+
+func TestFeedCursorKeepsLatest(t *testing.T) {
+	c, err := decodeCursor("", "feed")
+	require.NoError(t, err)
+	for i := 0; i < cursorMaxSeen+10; i++ {
+		c.add(hash32(fmt.Sprint(i)))
+	}
+
+	got, err := decodeCursor(c.encode(), "feed")
+	require.NoError(t, err)
+	assert.Len(t, got.seen, cursorMaxSeen)
+	assert.False(t, got.set[hash32("0")])
+	assert.True(t, got.set[hash32(fmt.Sprint(cursorMaxSeen+9))])
+}
+
+func TestFollowListCursor(t *testing.T) {
+	c, err := decodeCursor("", followListQuery(FollowListFollowers))
+	require.NoError(t, err)
+	c.add(hash32("u1"))
+	token := c.encode()
+
+	assert.NoError(t, CheckFollowListCursor(token, FollowListFollowers))
+	// 粉丝列表的游标不能用于关注列表
+	assert.True(t, errors.Is(CheckFollowListCursor(token, FollowListFollowing), ErrInvalidCursor))
+}
+
+func TestNormalizePageLimit(t *testing.T) {
+	assert.Equal(t, DefaultPageLimit, normalizePageLimit(0))
+	assert.Equal(t, 5, normalizePageLimit(5))
+	assert.Equal(t, MaxPageLimit, normalizePageLimit(MaxPageLimit+1))
+}
+
+func TestCollectPageEnd(t *testing.T) {
+	items := []Feed{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	read := func() ([]Feed, error) { return items, nil }
+	id := func(f Feed) string { return f.ID }
+	scrolls := 0
+	scroll := func(int) { scrolls++ }
+
+	// 只取首屏时无法判断是否到底，总会返回游标
+	got, next, err := collectPage(context.Background(), "feed", PageOptions{}, read, id, scroll)
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.NotEmpty(t, next)
+	assert.Zero(t, scrolls)
+
+	// 指定 limit 时滚动到没有新内容为止，不足一页即没有下一页
+	got, next, err = collectPage(context.Background(), "feed", PageOptions{Limit: DefaultPageLimit}, read, id, scroll)
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+	assert.Empty(t, next)
+	assert.Equal(t, pageStagnantLimit, scrolls)
+}
@@ -0,0 +1,15 @@
+package xiaohongshu
+
+import (
+	"github.com/go-rod/rod"
+	"github.com/xpzouying/xiaohongshu-mcp/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// phase 把操作中的一个阶段（打开页面、上传、提交等）记为 page 当前 context 下的子 span，
+// fn 收到的 page 带有该 span，阶段内再开启的 span 挂在它下面。
+func phase(page *rod.Page, name string, fn func(page *rod.Page) error, attrs ...attribute.KeyValue) (err error) {
+	ctx, span := tracing.Start(page.GetContext(), name, attrs...)
+	defer tracing.End(span, &err)
+	return fn(page.Context(ctx))
+}
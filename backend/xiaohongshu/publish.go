@@ -2,6 +2,7 @@ package xiaohongshu
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"math/rand"
 	"os"
@@ -27,17 +28,11 @@ type PublishAction struct {
 	page *rod.Page
 }
 
-const (
-	urlOfPublic          = `https://creator.xiaohongshu.com/publish/publish?source=official`
-	draftButtonSelector  = `#web > div > div > div > div > div.submit > div > button.d-button.d-button-large.--size-icon-large.--size-text-h6.d-button-with-content.--color-static.bold.--color-bg-fill.--color-text-paragraph.custom-button.cancelBtn > div`
-	submitButtonSelector = `div.submit div.d-button-content`
-)
-
 func NewPublishImageAction(page *rod.Page) (*PublishAction, error) {
 
 	pp := page.Timeout(300 * time.Second)
 
-	pp.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
+	pp.MustNavigate(creatorURL(pathOfPublic)).MustWaitIdle().MustWaitDOMStable()
 	time.Sleep(1 * time.Second)
 
 	if err := mustClickPublishTab(page, "上传图文"); err != nil {
@@ -59,7 +54,9 @@ func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent
 
 	page := p.page.Context(ctx)
 
-	if err := uploadImages(page, content.ImagePaths); err != nil {
+	if err := phase(page, "upload", func(page *rod.Page) error {
+		return uploadImages(page, content.ImagePaths)
+	}); err != nil {
 		return errors.Wrap(err, "小红书上传图片失败")
 	}
 
@@ -71,7 +68,9 @@ func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent
 
 	logrus.Infof("发布内容: title=%s, images=%v, tags=%v", content.Title, len(content.ImagePaths), tags)
 
-	if err := submitPublish(page, content.Title, content.Content, tags); err != nil {
+	if err := phase(page, "submit", func(page *rod.Page) error {
+		return submitPublish(page, content.Title, content.Content, tags)
+	}); err != nil {
 		return errors.Wrap(err, "小红书发布失败")
 	}
 
@@ -85,7 +84,9 @@ func (p *PublishAction) SaveDraft(ctx context.Context, content PublishImageConte
 
 	page := p.page.Context(ctx)
 
-	if err := uploadImages(page, content.ImagePaths); err != nil {
+	if err := phase(page, "upload", func(page *rod.Page) error {
+		return uploadImages(page, content.ImagePaths)
+	}); err != nil {
 		return errors.Wrap(err, "小红书上传图片失败")
 	}
 
@@ -97,7 +98,9 @@ func (p *PublishAction) SaveDraft(ctx context.Context, content PublishImageConte
 
 	logrus.Infof("保存草稿: title=%s, images=%v, tags=%v", content.Title, len(content.ImagePaths), tags)
 
-	if err := submitDraft(page, content.Title, content.Content, tags); err != nil {
+	if err := phase(page, "submit", func(page *rod.Page) error {
+		return submitDraft(page, content.Title, content.Content, tags)
+	}); err != nil {
 		return errors.Wrap(err, "小红书草稿保存失败")
 	}
 
@@ -112,7 +115,9 @@ func (p *PublishAction) PublishScheduled(ctx context.Context, content PublishIma
 
 	page := p.page.Context(ctx)
 
-	if err := uploadImages(page, content.ImagePaths); err != nil {
+	if err := phase(page, "upload", func(page *rod.Page) error {
+		return uploadImages(page, content.ImagePaths)
+	}); err != nil {
 		return errors.Wrap(err, "小红书上传图片失败")
 	}
 
@@ -124,7 +129,9 @@ func (p *PublishAction) PublishScheduled(ctx context.Context, content PublishIma
 
 	logrus.Infof("定时发布: title=%s, images=%v, tags=%v, when=%s", content.Title, len(content.ImagePaths), tags, when.Format("2006-01-02 15:04"))
 
-	if err := submitPublishScheduled(page, content.Title, content.Content, tags, when); err != nil {
+	if err := phase(page, "submit", func(page *rod.Page) error {
+		return submitPublishScheduled(page, content.Title, content.Content, tags, when)
+	}); err != nil {
 		return errors.Wrap(err, "小红书定时发布失败")
 	}
 
@@ -134,7 +141,7 @@ func (p *PublishAction) PublishScheduled(ctx context.Context, content PublishIma
 func removePopCover(page *rod.Page) {
 
 	// 先移除弹窗封面
-	has, elem, err := page.Has("div.d-popover")
+	has, elem, err := hasElement(page, "publish.popover")
 	if err != nil {
 		return
 	}
@@ -153,7 +160,7 @@ func clickEmptyPosition(page *rod.Page) {
 }
 
 func mustClickPublishTab(page *rod.Page, tabname string) error {
-	page.MustElement(`div.upload-content`).MustWaitVisible()
+	mustFindElement(page, "publish.upload_area").MustWaitVisible()
 
 	deadline := time.Now().Add(15 * time.Second)
 	for time.Now().Before(deadline) {
@@ -189,7 +196,7 @@ func mustClickPublishTab(page *rod.Page, tabname string) error {
 }
 
 func getTabElement(page *rod.Page, tabname string) (*rod.Element, bool, error) {
-	elems, err := page.Elements("div.creator-tab")
+	elems, err := findElements(page, "publish.tab")
 	if err != nil {
 		return nil, false, err
 	}
@@ -254,7 +261,10 @@ func uploadImages(page *rod.Page, imagesPaths []string) error {
 	}
 
 	// 等待上传输入框出现
-	uploadInput := pp.MustElement(".upload-input")
+	uploadInput, err := findElement(pp, "publish.upload_input")
+	if err != nil {
+		return errors.Wrap(err, "没有找到图片上传输入框")
+	}
 
 	// 上传多个文件
 	uploadInput.MustSetFiles(validPaths...)
@@ -273,7 +283,7 @@ func waitForUploadComplete(page *rod.Page, expectedCount int) error {
 
 	for time.Since(start) < maxWaitTime {
 		// 使用具体的pr类名检查已上传的图片
-		uploadedImages, err := page.Elements(".img-preview-area .pr")
+		uploadedImages, err := findElements(page, "publish.image_preview")
 
 		slog.Info("uploadedImages", "uploadedImages", uploadedImages)
 
@@ -294,9 +304,30 @@ func waitForUploadComplete(page *rod.Page, expectedCount int) error {
 	return errors.New("上传超时，请检查网络连接和图片大小")
 }
 
+// ErrSubmitUncertain 点击提交或发送后出错：点击可能已经生效，笔记或私信可能已经发出，不能直接重试
+var ErrSubmitUncertain = errors.New("submit may have taken effect")
+
+// markSubmitted 标记点击提交之后发生的错误
+func markSubmitted(err error) error {
+	return fmt.Errorf("%w: %w", ErrSubmitUncertain, err)
+}
+
+// clickSubmit 点击发布/暂存按钮。点击出错或 panic 时无法确定是否已提交，返回的错误带 ErrSubmitUncertain
+func clickSubmit(btn *rod.Element) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = markSubmitted(fmt.Errorf("panic: %v", r))
+		}
+	}()
+	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return markSubmitted(err)
+	}
+	return nil
+}
+
 func submitPublish(page *rod.Page, title, content string, tags []string) error {
 
-	titleElem := page.MustElement("div.d-input input")
+	titleElem := mustFindElement(page, "publish.title_input")
 	titleElem.MustInput(title)
 
 	time.Sleep(1 * time.Second)
@@ -312,8 +343,10 @@ func submitPublish(page *rod.Page, title, content string, tags []string) error {
 
 	time.Sleep(1 * time.Second)
 
-	submitButton := page.MustElement(submitButtonSelector)
-	submitButton.MustClick()
+	submitButton := mustFindElement(page, "publish.submit_button")
+	if err := clickSubmit(submitButton); err != nil {
+		return errors.Wrap(err, "点击发布按钮失败")
+	}
 
 	time.Sleep(3 * time.Second)
 
@@ -321,7 +354,7 @@ func submitPublish(page *rod.Page, title, content string, tags []string) error {
 }
 
 func submitPublishScheduled(page *rod.Page, title, content string, tags []string, when time.Time) error {
-	titleElem := page.MustElement("div.d-input input")
+	titleElem := mustFindElement(page, "publish.title_input")
 	titleElem.MustInput(title)
 
 	time.Sleep(1 * time.Second)
@@ -339,8 +372,10 @@ func submitPublishScheduled(page *rod.Page, title, content string, tags []string
 		return err
 	}
 
-	submitButton := page.MustElement(submitButtonSelector)
-	submitButton.MustClick()
+	submitButton := mustFindElement(page, "publish.submit_button")
+	if err := clickSubmit(submitButton); err != nil {
+		return errors.Wrap(err, "点击发布按钮失败")
+	}
 
 	time.Sleep(3 * time.Second)
 
@@ -349,7 +384,7 @@ func submitPublishScheduled(page *rod.Page, title, content string, tags []string
 
 func submitDraft(page *rod.Page, title, content string, tags []string) error {
 
-	titleElem := page.MustElement("div.d-input input")
+	titleElem := mustFindElement(page, "publish.title_input")
 	titleElem.MustInput(title)
 
 	time.Sleep(1 * time.Second)
@@ -365,8 +400,13 @@ func submitDraft(page *rod.Page, title, content string, tags []string) error {
 
 	time.Sleep(1 * time.Second)
 
-	draftButton := page.MustElement(draftButtonSelector)
-	draftButton.MustClick()
+	draftButton, err := findElement(page, "publish.draft_button")
+	if err != nil {
+		return errors.Wrap(err, "没有找到暂存离开按钮")
+	}
+	if err := clickSubmit(draftButton); err != nil {
+		return errors.Wrap(err, "点击暂存离开按钮失败")
+	}
 
 	time.Sleep(3 * time.Second)
 
@@ -379,7 +419,13 @@ func getContentElement(page *rod.Page) (*rod.Element, bool) {
 	var found bool
 
 	page.Race().
-		Element("div.ql-editor").MustHandle(func(e *rod.Element) {
+		ElementFunc(func(page *rod.Page) (*rod.Element, error) {
+			has, el, err := hasElement(page, "publish.content_editor")
+			if err == nil && !has {
+				err = &rod.ElementNotFoundError{}
+			}
+			return el, err
+		}).MustHandle(func(e *rod.Element) {
 		foundElement = e
 		found = true
 	}).
@@ -438,9 +484,9 @@ func inputTag(contentElem *rod.Element, tag string) {
 	time.Sleep(1 * time.Second)
 
 	page := contentElem.Page()
-	topicContainer, err := page.Element("#creator-editor-topic-container")
+	topicContainer, err := findElement(page, "publish.topic_container")
 	if err == nil && topicContainer != nil {
-		firstItem, err := topicContainer.Element(".item")
+		firstItem, err := findChild(topicContainer, "publish.topic_item")
 		if err == nil && firstItem != nil {
 			firstItem.MustClick()
 			slog.Info("成功点击标签联想选项", "tag", tag)
@@ -460,9 +506,12 @@ func inputTag(contentElem *rod.Element, tag string) {
 }
 
 func findTextboxByPlaceholder(page *rod.Page) (*rod.Element, error) {
-	elements := page.MustElements("p")
-	if elements == nil {
-		return nil, errors.New("no p elements found")
+	elements, err := findElements(page, "publish.content_placeholder")
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) == 0 {
+		return nil, errors.New("no placeholder elements found")
 	}
 
 	// 查找包含指定placeholder的元素
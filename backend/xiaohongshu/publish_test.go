@@ -2,6 +2,8 @@ package xiaohongshu
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
@@ -12,7 +14,7 @@ import (
 
 func TestPublish(t *testing.T) {
 
-	t.Skip("SKIP: 测试发布")
+	skipUnlessLive(t)
 
 	b, err := browser.New(browser.Config{Headless: false})
 	require.NoError(t, err)
@@ -31,3 +33,51 @@ func TestPublish(t *testing.T) {
 	})
 	assert.NoError(t, err)
 }
+
+func TestPublish_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+
+	image := filepath.Join(t.TempDir(), "cover.png")
+	require.NoError(t, os.WriteFile(image, []byte("\x89PNG\r\n\x1a\n"), 0o644))
+
+	action, err := NewPublishImageAction(page)
+	require.NoError(t, err)
+
+	err = action.Publish(context.Background(), PublishImageContent{
+		Title:      "春日穿搭",
+		Content:    "今天分享三套春日通勤穿搭",
+		ImagePaths: []string{image},
+	})
+	require.NoError(t, err)
+
+	notes := srv.publishedNotes()
+	require.Len(t, notes, 1)
+	assert.Equal(t, "image", notes[0]["type"], "应先切换到上传图文")
+	assert.Equal(t, "春日穿搭", notes[0]["title"])
+	assert.Equal(t, "今天分享三套春日通勤穿搭", notes[0]["desc"])
+	assert.Equal(t, []any{"cover.png"}, notes[0]["images"])
+}
+
+func TestPublishVideo_Fixture(t *testing.T) {
+	page, srv := newFixturePage(t)
+
+	video := filepath.Join(t.TempDir(), "vlog.mp4")
+	require.NoError(t, os.WriteFile(video, []byte("\x00\x00\x00\x18ftypmp42"), 0o644))
+
+	action, err := NewPublishVideoAction(page)
+	require.NoError(t, err)
+
+	err = action.PublishVideo(context.Background(), PublishVideoContent{
+		Title:     "通勤 vlog",
+		Content:   "早八通勤日常",
+		VideoPath: video,
+	})
+	require.NoError(t, err)
+
+	notes := srv.publishedNotes()
+	require.Len(t, notes, 1)
+	assert.Equal(t, "video", notes[0]["type"])
+	assert.Equal(t, "通勤 vlog", notes[0]["title"])
+	assert.Equal(t, "早八通勤日常", notes[0]["desc"])
+	assert.Equal(t, "vlog.mp4", notes[0]["video"])
+}
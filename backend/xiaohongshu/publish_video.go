@@ -24,7 +24,7 @@ type PublishVideoContent struct {
 func NewPublishVideoAction(page *rod.Page) (*PublishAction, error) {
 	pp := page.Timeout(300 * time.Second)
 
-	pp.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
+	pp.MustNavigate(creatorURL(pathOfPublic)).MustWaitIdle().MustWaitDOMStable()
 	time.Sleep(1 * time.Second)
 
 	if err := mustClickPublishTab(page, "上传视频"); err != nil {
@@ -44,11 +44,15 @@ func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoCo
 
 	page := p.page.Context(ctx)
 
-	if err := uploadVideo(page, content.VideoPath); err != nil {
+	if err := phase(page, "upload", func(page *rod.Page) error {
+		return uploadVideo(page, content.VideoPath)
+	}); err != nil {
 		return errors.Wrap(err, "小红书上传视频失败")
 	}
 
-	if err := submitPublishVideo(page, content.Title, content.Content, content.Tags); err != nil {
+	if err := phase(page, "submit", func(page *rod.Page) error {
+		return submitPublishVideo(page, content.Title, content.Content, content.Tags)
+	}); err != nil {
 		return errors.Wrap(err, "小红书发布失败")
 	}
 	return nil
@@ -62,11 +66,15 @@ func (p *PublishAction) SaveDraftVideo(ctx context.Context, content PublishVideo
 
 	page := p.page.Context(ctx)
 
-	if err := uploadVideo(page, content.VideoPath); err != nil {
+	if err := phase(page, "upload", func(page *rod.Page) error {
+		return uploadVideo(page, content.VideoPath)
+	}); err != nil {
 		return errors.Wrap(err, "小红书上传视频失败")
 	}
 
-	if err := submitDraftVideo(page, content.Title, content.Content, content.Tags); err != nil {
+	if err := phase(page, "submit", func(page *rod.Page) error {
+		return submitDraftVideo(page, content.Title, content.Content, content.Tags)
+	}); err != nil {
 		return errors.Wrap(err, "小红书草稿保存失败")
 	}
 	return nil
@@ -80,11 +88,15 @@ func (p *PublishAction) PublishVideoScheduled(ctx context.Context, content Publi
 
 	page := p.page.Context(ctx)
 
-	if err := uploadVideo(page, content.VideoPath); err != nil {
+	if err := phase(page, "upload", func(page *rod.Page) error {
+		return uploadVideo(page, content.VideoPath)
+	}); err != nil {
 		return errors.Wrap(err, "小红书上传视频失败")
 	}
 
-	if err := submitPublishVideoScheduled(page, content.Title, content.Content, content.Tags, when); err != nil {
+	if err := phase(page, "submit", func(page *rod.Page) error {
+		return submitPublishVideoScheduled(page, content.Title, content.Content, content.Tags, when)
+	}); err != nil {
 		return errors.Wrap(err, "小红书定时发布失败")
 	}
 	return nil
@@ -99,14 +111,9 @@ func uploadVideo(page *rod.Page, videoPath string) error {
 	}
 
 	// 寻找文件上传输入框（与图文一致的 class，或退回到 input[type=file]）
-	var fileInput *rod.Element
-	var err error
-	fileInput, err = pp.Element(".upload-input")
-	if err != nil || fileInput == nil {
-		fileInput, err = pp.Element("input[type='file']")
-		if err != nil || fileInput == nil {
-			return errors.New("未找到视频上传输入框")
-		}
+	fileInput, err := findElement(pp, "publish.upload_input")
+	if err != nil {
+		return errors.Wrap(err, "未找到视频上传输入框")
 	}
 
 	fileInput.MustSetFiles(videoPath)
@@ -125,12 +132,11 @@ func waitForPublishButtonClickable(page *rod.Page) (*rod.Element, error) {
 	maxWait := 10 * time.Minute
 	interval := 1 * time.Second
 	start := time.Now()
-	selector := "button.publishBtn"
 
 	slog.Info("开始等待发布按钮可点击(视频)")
 
 	for time.Since(start) < maxWait {
-		btn, err := page.Element(selector)
+		btn, err := findElement(page, "publish.publish_button")
 		if err == nil && btn != nil {
 			// 可见性
 			vis, verr := btn.Visible()
@@ -154,7 +160,7 @@ func waitForPublishButtonClickable(page *rod.Page) (*rod.Element, error) {
 // submitPublishVideo 填写标题、正文、标签并点击发布（等待按钮可点击后再提交）
 func submitPublishVideo(page *rod.Page, title, content string, tags []string) error {
 	// 标题
-	titleElem := page.MustElement("div.d-input input")
+	titleElem := mustFindElement(page, "publish.title_input")
 	titleElem.MustInput(title)
 	time.Sleep(1 * time.Second)
 
@@ -175,7 +181,7 @@ func submitPublishVideo(page *rod.Page, title, content string, tags []string) er
 	}
 
 	// 点击发布
-	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+	if err := clickSubmit(btn); err != nil {
 		return errors.Wrap(err, "点击发布按钮失败")
 	}
 
@@ -186,12 +192,12 @@ func submitPublishVideo(page *rod.Page, title, content string, tags []string) er
 // submitPublishVideoScheduled 填写标题、正文、标签，设置定时并发布
 func submitPublishVideoScheduled(page *rod.Page, title, content string, tags []string, when time.Time) error {
 	// 标题
-	titleElem := page.MustElement("div.d-input input")
+	titleElem := mustFindElement(page, "publish.title_input")
 	titleElem.MustInput(title)
 	time.Sleep(1 * time.Second)
 
 	// 正文
-	editor, err := page.Element(".ql-editor")
+	editor, err := findElement(page, "publish.content_editor")
 	if err != nil || editor == nil {
 		return errors.New("未找到正文输入框")
 	}
@@ -205,9 +211,9 @@ func submitPublishVideoScheduled(page *rod.Page, title, content string, tags []s
 		editor.MustInput("#" + tag)
 		time.Sleep(300 * time.Millisecond)
 
-		topicContainer, _ := page.Element("#creator-editor-topic-container")
+		topicContainer, _ := findElement(page, "publish.topic_container")
 		if topicContainer != nil {
-			if item, _ := topicContainer.Element(".item"); item != nil {
+			if item, _ := findChild(topicContainer, "publish.topic_item"); item != nil {
 				_ = item.Click(proto.InputMouseButtonLeft, 1)
 			}
 		}
@@ -225,18 +231,21 @@ func submitPublishVideoScheduled(page *rod.Page, title, content string, tags []s
 	if err != nil {
 		return err
 	}
-	return btn.Click(proto.InputMouseButtonLeft, 1)
+	if err := clickSubmit(btn); err != nil {
+		return errors.Wrap(err, "点击发布按钮失败")
+	}
+	return nil
 }
 
 // submitDraftVideo 填写标题、正文、标签并点击“暂时离开”（保存草稿）
 func submitDraftVideo(page *rod.Page, title, content string, tags []string) error {
 	// 标题
-	titleElem := page.MustElement("div.d-input input")
+	titleElem := mustFindElement(page, "publish.title_input")
 	titleElem.MustInput(title)
 	time.Sleep(1 * time.Second)
 
 	// 正文
-	editor, err := page.Element(".ql-editor")
+	editor, err := findElement(page, "publish.content_editor")
 	if err != nil || editor == nil {
 		return errors.New("未找到正文输入框")
 	}
@@ -250,9 +259,9 @@ func submitDraftVideo(page *rod.Page, title, content string, tags []string) erro
 		editor.MustInput("#" + tag)
 		time.Sleep(300 * time.Millisecond)
 
-		topicContainer, _ := page.Element("#creator-editor-topic-container")
+		topicContainer, _ := findElement(page, "publish.topic_container")
 		if topicContainer != nil {
-			if item, _ := topicContainer.Element(".item"); item != nil {
+			if item, _ := findChild(topicContainer, "publish.topic_item"); item != nil {
 				_ = item.Click(proto.InputMouseButtonLeft, 1)
 			}
 		}
@@ -262,8 +271,13 @@ func submitDraftVideo(page *rod.Page, title, content string, tags []string) erro
 	time.Sleep(1 * time.Second)
 
 	// 草稿按钮
-	draftBtn := page.MustElement(draftButtonSelector)
-	draftBtn.MustClick()
+	draftBtn, err := findElement(page, "publish.draft_button")
+	if err != nil {
+		return errors.Wrap(err, "未找到暂存离开按钮")
+	}
+	if err := clickSubmit(draftBtn); err != nil {
+		return errors.Wrap(err, "点击暂存离开按钮失败")
+	}
 	time.Sleep(3 * time.Second)
 	return nil
 }
@@ -8,6 +8,50 @@ import (
 	"github.com/pkg/errors"
 )
 
+// 创作者平台允许的定时发布窗口
+const (
+	ScheduleMinLead = time.Hour
+	ScheduleMaxLead = 14 * 24 * time.Hour
+)
+
+// scheduleTimeLayout 创作者平台时间输入框使用的格式（北京时间）
+const scheduleTimeLayout = "2006-01-02 15:04"
+
+// ScheduleLocation 定时发布使用的时区（Asia/Shanghai，无夏令时，直接用固定时区避免依赖 tzdata）
+var ScheduleLocation = time.FixedZone("Asia/Shanghai", 8*60*60)
+
+var (
+	// ErrInvalidScheduleTime 定时发布时间格式无法解析
+	ErrInvalidScheduleTime = errors.New("定时发布时间格式错误，请使用 RFC3339 或 \"2006-01-02 15:04\"（北京时间）")
+	// ErrScheduleOutOfRange 定时发布时间不在平台允许的窗口内
+	ErrScheduleOutOfRange = errors.New("定时发布时间超出允许范围")
+)
+
+// ParseScheduleTime 解析定时发布时间，支持 RFC3339 或 "2006-01-02 15:04"（按北京时间解析），结果精确到分钟。
+func ParseScheduleTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Truncate(time.Minute), nil
+	}
+	t, err := time.ParseInLocation(scheduleTimeLayout, s, ScheduleLocation)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(ErrInvalidScheduleTime, "publish_at=%q", s)
+	}
+	return t, nil
+}
+
+// ValidateScheduleTime 校验定时发布时间是否在 now 之后 1 小时到 14 天之间。
+func ValidateScheduleTime(when, now time.Time) error {
+	earliest := now.Add(ScheduleMinLead)
+	latest := now.Add(ScheduleMaxLead)
+	if when.Before(earliest) || when.After(latest) {
+		return errors.Wrapf(ErrScheduleOutOfRange, "%s 需在 %s 至 %s 之间",
+			when.In(ScheduleLocation).Format(scheduleTimeLayout),
+			earliest.In(ScheduleLocation).Format(scheduleTimeLayout),
+			latest.In(ScheduleLocation).Format(scheduleTimeLayout))
+	}
+	return nil
+}
+
 // applySchedule 选择“定时发布”并填入目标时间。
 func applySchedule(page *rod.Page, when time.Time) error {
 	radio, err := findScheduleRadio(page)
@@ -28,7 +72,7 @@ func applySchedule(page *rod.Page, when time.Time) error {
 	// 确保输入框获得焦点再填值
 	_ = inputEl.Click(proto.InputMouseButtonLeft, 1)
 
-	whenStr := when.Format("2006-01-02 15:04")
+	whenStr := when.In(ScheduleLocation).Format(scheduleTimeLayout)
 	_, err = inputEl.Eval(`(v) => {
 		const el = this;
 		el.value = '';
@@ -46,29 +90,27 @@ func applySchedule(page *rod.Page, when time.Time) error {
 }
 
 func findScheduleRadio(page *rod.Page) (*rod.Element, error) {
-	// 优先通过文本匹配“定时发布”
-	if el, err := page.ElementR("label.el-radio", "定时"); err == nil && el != nil {
-		return el, nil
-	}
-	// 退回使用较短的 selector
-	if el, err := page.Element("#el-id-3747-47 label.el-radio"); err == nil && el != nil {
-		return el, nil
+	// 按候选顺序通过文本匹配“定时发布”
+	for _, selector := range selCandidates("publish.schedule_radio") {
+		if el, err := page.ElementR(selector, "定时"); err == nil && el != nil {
+			return el, nil
+		}
 	}
 	return nil, errors.New("未找到定时发布单选框")
 }
 
 func findScheduleInput(page *rod.Page) (*rod.Element, error) {
-	els, err := page.Elements("div.el-date-editor--datetime input")
-	if err == nil {
+	// 按候选顺序取第一个可见的输入框
+	for _, selector := range selCandidates("publish.schedule_input") {
+		els, err := page.Elements(selector)
+		if err != nil {
+			continue
+		}
 		for _, el := range els {
 			if vis, _ := el.Visible(); vis {
 				return el, nil
 			}
 		}
 	}
-	// 退回使用较短的 selector
-	if el, err := page.Element("#el-id-3747-47 input"); err == nil && el != nil {
-		return el, nil
-	}
 	return nil, errors.New("未找到定时发布时间输入框")
 }
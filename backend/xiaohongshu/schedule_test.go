@@ -0,0 +1,39 @@
+package xiaohongshu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	want := time.Date(2025, 10, 20, 20, 30, 0, 0, ScheduleLocation)
+
+	got, err := ParseScheduleTime("2025-10-20 20:30")
+	require.NoError(t, err)
+	assert.True(t, want.Equal(got))
+
+	got, err = ParseScheduleTime("2025-10-20T12:30:45Z")
+	require.NoError(t, err)
+	assert.True(t, want.Equal(got), "RFC3339 should be truncated to minute, got %s", got)
+
+	_, err = ParseScheduleTime("明天晚上八点")
+	assert.Error(t, err)
+}
+
+func TestValidateScheduleTime(t *testing.T) {
+	now := time.Date(2025, 10, 16, 10, 0, 0, 0, ScheduleLocation)
+
+	assert.NoError(t, ValidateScheduleTime(now.Add(time.Hour), now))
+	assert.NoError(t, ValidateScheduleTime(now.Add(72*time.Hour), now))
+	assert.NoError(t, ValidateScheduleTime(now.Add(14*24*time.Hour), now))
+
+	err := ValidateScheduleTime(now.Add(30*time.Minute), now)
+	assert.True(t, errors.Is(err, ErrScheduleOutOfRange))
+
+	err = ValidateScheduleTime(now.Add(15*24*time.Hour), now)
+	assert.True(t, errors.Is(err, ErrScheduleOutOfRange))
+}
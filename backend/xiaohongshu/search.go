@@ -2,13 +2,11 @@ package xiaohongshu
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"time"
 
 	"github.com/go-rod/rod"
-	"github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
 type SearchResult struct {
@@ -165,9 +163,8 @@ func NewSearchAction(page *rod.Page) *SearchAction {
 	return &SearchAction{page: pp}
 }
 
-func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...FilterOption) ([]Feed, error) {
-	page := s.page.Context(ctx)
-
+// openSearch 打开搜索结果页并应用筛选条件，rec 只保留应用筛选后的搜索接口响应
+func (s *SearchAction) openSearch(page *rod.Page, rec *responseRecorder, keyword string, filters []FilterOption) error {
 	searchURL := makeSearchURL(keyword)
 	page.MustNavigate(searchURL)
 	page.MustWaitStable()
@@ -181,7 +178,7 @@ func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...Fi
 		for _, filter := range filters {
 			internalFilters, err := convertToInternalFilters(filter)
 			if err != nil {
-				return nil, fmt.Errorf("筛选选项转换失败: %w", err)
+				return fmt.Errorf("筛选选项转换失败: %w", err)
 			}
 			allInternalFilters = append(allInternalFilters, internalFilters...)
 		}
@@ -189,22 +186,22 @@ func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...Fi
 		// 验证所有内部筛选选项
 		for _, filter := range allInternalFilters {
 			if err := validateInternalFilterOption(filter); err != nil {
-				return nil, fmt.Errorf("筛选选项验证失败: %w", err)
+				return fmt.Errorf("筛选选项验证失败: %w", err)
 			}
 		}
 
 		// 悬停在筛选按钮上
-		filterButton := page.MustElement(`div.filter`)
+		filterButton := mustFindElement(page, "search.filter_button")
 		filterButton.MustHover()
 
 		// 等待筛选面板出现
-		page.MustWait(`() => document.querySelector('div.filter-panel') !== null`)
+		page.MustWait(`(candidates) => candidates.some((c) => document.querySelector(c) !== null)`, selCandidates("search.filter_panel"))
 
 		// 应用所有筛选条件
 		for _, filter := range allInternalFilters {
-			selector := fmt.Sprintf(`div.filter-panel div.filters:nth-child(%d) div.tags:nth-child(%d)`,
-				filter.FiltersIndex, filter.TagsIndex)
-			option := page.MustElement(selector)
+			option := mustFindElement(page, "search.filter_option", filter.FiltersIndex, filter.TagsIndex)
+			// 每次点击都会重新搜索，之前的结果不再有效
+			rec.reset()
 			option.MustClick()
 		}
 
@@ -214,29 +211,47 @@ func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...Fi
 		page.MustWait(`() => window.__INITIAL_STATE__ !== undefined`)
 	}
 
-	result := page.MustEval(`() => {
-		if (window.__INITIAL_STATE__ &&
-		    window.__INITIAL_STATE__.search &&
-		    window.__INITIAL_STATE__.search.feeds) {
-			const feeds = window.__INITIAL_STATE__.search.feeds;
-			const feedsData = feeds.value !== undefined ? feeds.value : feeds._value;
-			if (feedsData) {
-				return JSON.stringify(feedsData);
-			}
-		}
-		return "";
-	}`).String()
+	return nil
+}
+
+func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...FilterOption) ([]Feed, error) {
+	page := s.page.Context(ctx)
+	rec := recordResponses(page, searchNotesAPI)
+	defer rec.stop()
 
-	if result == "" {
-		return nil, errors.ErrNoFeeds
+	if err := s.openSearch(page, rec, keyword, filters); err != nil {
+		return nil, err
 	}
 
-	var feeds []Feed
-	if err := json.Unmarshal([]byte(result), &feeds); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal feeds: %w", err)
+	return readSearchFeeds(page, rec)
+}
+
+// SearchPage 搜索并滚动结果列表，返回 opts.Cursor 之后的最多 opts.Limit 条 Feed。
+// 翻页时需使用相同的关键词与筛选条件。
+func (s *SearchAction) SearchPage(ctx context.Context, keyword string, opts PageOptions, filters ...FilterOption) (*FeedPage, error) {
+	page := s.page.Context(ctx)
+
+	// 先校验游标，避免无效游标也要打开页面
+	query := searchQuery(keyword, filters)
+	if _, err := decodeCursor(opts.Cursor, query); err != nil {
+		return nil, err
 	}
 
-	return feeds, nil
+	rec := recordResponses(page, searchNotesAPI)
+	defer rec.stop()
+	if err := s.openSearch(page, rec, keyword, filters); err != nil {
+		return nil, err
+	}
+
+	return collectFeeds(ctx, page, query, opts, func() ([]Feed, error) {
+		return readSearchFeeds(page, rec)
+	})
+}
+
+// readSearchFeeds 合并页面状态与搜索接口返回的 Feed
+func readSearchFeeds(page *rod.Page, rec *responseRecorder) ([]Feed, error) {
+	captured, _ := readCaptured(rec, decodeSearchNotes)
+	return readMerged(func() ([]Feed, error) { return readStateFeeds(page, "search") }, captured.Items, feedID)
 }
 
 func makeSearchURL(keyword string) string {
@@ -247,5 +262,5 @@ func makeSearchURL(keyword string) string {
 
 	//https://www.xiaohongshu.com/search_result?keyword=%25E7%258E%258B%25E5%25AD%2590&source=web_search_result_notes
 	//https://www.xiaohongshu.com/search_result?keyword=%25E7%258E%258B%25E5%25AD%2590&source=web_explore_feed
-	return fmt.Sprintf("https://www.xiaohongshu.com/search_result?%s", values.Encode())
+	return siteURL(pathOfSearch + "?" + values.Encode())
 }
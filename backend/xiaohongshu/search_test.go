@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 )
 
 func TestSearch(t *testing.T) {
 
-	t.Skip("SKIP: 测试发布")
+	skipUnlessLive(t)
 
 	b, err := browser.New(browser.Config{Headless: false})
 	require.NoError(t, err)
@@ -38,7 +39,7 @@ func TestSearch(t *testing.T) {
 
 func TestSearchWithFilters(t *testing.T) {
 
-	//t.Skip("SKIP: 测试筛选功能")
+	skipUnlessLive(t)
 
 	b, err := browser.New(browser.Config{Headless: false})
 	require.NoError(t, err)
@@ -105,3 +106,25 @@ func TestFilterValidation(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, internalFilters, 5)
 }
+
+func TestSearch_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+	ctx := context.Background()
+
+	// 首页结果写入 __INITIAL_STATE__，非笔记条目（hot_query）被过滤
+	feeds, err := NewSearchAction(page).Search(ctx, "咖啡")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"65f000000000000000000a01", "65f000000000000000000a02", "65f000000000000000000a03",
+	}, feedIDs(feeds))
+	assert.Equal(t, "xt-a02", feeds[1].XsecToken)
+
+	// 第 2 页只渲染到列表中，靠接口响应补齐
+	result, err := NewSearchAction(page).SearchPage(ctx, "咖啡", PageOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"65f000000000000000000a01", "65f000000000000000000a02", "65f000000000000000000a03",
+		"65f000000000000000000a04", "65f000000000000000000a05",
+	}, feedIDs(result.Feeds))
+	assert.Empty(t, result.NextCursor)
+}
@@ -0,0 +1,301 @@
+package xiaohongshu
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultSelectorsJSON 内置的选择器表，也是覆盖文件的参考格式
+//
+//go:embed selectors.json
+var defaultSelectorsJSON []byte
+
+// SelectorDef 一个命名选择器。Candidates 按优先级排列，
+// findElement 等查找函数逐个尝试，多个候选同时匹配时取靠前的候选。
+type SelectorDef struct {
+	// Page 选择器所在的页面，健康检查按页面打开
+	Page       string   `json:"page"`
+	Candidates []string `json:"candidates"`
+	// Optional 只在交互后或特定状态下出现（弹窗、悬停菜单、上传后的编辑器等），健康检查时没有匹配不算失效
+	Optional    bool   `json:"optional,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// isTemplate 候选中带有 fmt 占位符，需要参数才能使用
+func (d SelectorDef) isTemplate() bool {
+	for _, c := range d.Candidates {
+		if strings.Contains(c, "%") {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholderCount 候选中的 fmt 占位符个数（不含 %%）
+func placeholderCount(candidate string) int {
+	return strings.Count(strings.ReplaceAll(candidate, "%%", ""), "%")
+}
+
+// SelectorRegistry 版本化的选择器表
+type SelectorRegistry struct {
+	Version   string                 `json:"version"`
+	Selectors map[string]SelectorDef `json:"selectors"`
+}
+
+// Names 按名称排序
+func (r *SelectorRegistry) Names() []string {
+	names := make([]string, 0, len(r.Selectors))
+	for name := range r.Selectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	selectorsMu sync.RWMutex
+	selectors   = DefaultSelectors()
+)
+
+// DefaultSelectors 内置的选择器表
+func DefaultSelectors() *SelectorRegistry {
+	r, err := parseSelectors(defaultSelectorsJSON)
+	if err != nil {
+		panic(fmt.Sprintf("built-in selectors.json: %v", err))
+	}
+	return r
+}
+
+func parseSelectors(data []byte) (*SelectorRegistry, error) {
+	var r SelectorRegistry
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	for name, def := range r.Selectors {
+		if len(def.Candidates) == 0 {
+			return nil, fmt.Errorf("selector %s: no candidates", name)
+		}
+		for _, c := range def.Candidates {
+			if strings.TrimSpace(c) == "" {
+				return nil, fmt.Errorf("selector %s: empty candidate", name)
+			}
+		}
+	}
+	return &r, nil
+}
+
+// LoadSelectors 读取覆盖文件并合并到内置选择器表：文件中出现的名称替换对应条目（未填写的 page 沿用内置值），
+// version 不为空时替换版本号。path 为空时返回内置选择器表。
+func LoadSelectors(path string) (*SelectorRegistry, error) {
+	r := DefaultSelectors()
+	if path == "" {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read selectors %s", path)
+	}
+	overrides, err := parseSelectors(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse selectors %s", path)
+	}
+	for name, def := range overrides.Selectors {
+		base, ok := r.Selectors[name]
+		if !ok {
+			return nil, fmt.Errorf("selectors %s: unknown selector %s", path, name)
+		}
+		if err := checkOverride(name, base, def); err != nil {
+			return nil, errors.Wrapf(err, "selectors %s", path)
+		}
+		if def.Page == "" {
+			def.Page = base.Page
+		}
+		r.Selectors[name] = def
+	}
+	if overrides.Version != "" {
+		r.Version = overrides.Version
+	}
+	return r, nil
+}
+
+// checkOverride 覆盖条目的占位符必须和内置条目一致：代码按内置条目传参，
+// 多出或缺少占位符都会生成错误的选择器
+func checkOverride(name string, base, def SelectorDef) error {
+	want := placeholderCount(base.Candidates[0])
+	for _, c := range def.Candidates {
+		if n := placeholderCount(c); n != want {
+			return fmt.Errorf("selector %s: candidate %q has %d placeholder(s), built-in entry takes %d", name, c, n, want)
+		}
+	}
+	return nil
+}
+
+// SetSelectors 替换当前使用的选择器表，之后的操作立即生效
+func SetSelectors(r *SelectorRegistry) {
+	selectorsMu.Lock()
+	defer selectorsMu.Unlock()
+	selectors = r
+}
+
+// CurrentSelectors 当前使用的选择器表，调用方不要修改
+func CurrentSelectors() *SelectorRegistry {
+	selectorsMu.RLock()
+	defer selectorsMu.RUnlock()
+	return selectors
+}
+
+// WatchSelectors 每隔 interval 检查覆盖文件的修改时间，变化后重新加载；
+// 加载失败时保留当前选择器表。ctx 结束时返回。
+func WatchSelectors(ctx context.Context, path string, interval time.Duration) {
+	var lastMod time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastMod = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(lastMod) {
+			continue
+		}
+		lastMod = info.ModTime()
+
+		r, err := LoadSelectors(path)
+		if err != nil {
+			logrus.Errorf("重新加载选择器失败，继续使用版本 %s: %v", CurrentSelectors().Version, err)
+			continue
+		}
+		SetSelectors(r)
+		logrus.Infof("已重新加载选择器: %s (版本 %s)", path, r.Version)
+	}
+}
+
+// selCandidates 按优先级返回命名选择器的候选，args 用于填充候选中的占位符。
+// 名称未注册属于代码错误，直接 panic。
+func selCandidates(name string, args ...any) []string {
+	def, ok := CurrentSelectors().Selectors[name]
+	if !ok {
+		panic(fmt.Sprintf("selector %q is not registered", name))
+	}
+	if len(args) == 0 {
+		return def.Candidates
+	}
+	out := make([]string, len(def.Candidates))
+	for i, c := range def.Candidates {
+		out[i] = fmt.Sprintf(c, args...)
+	}
+	return out
+}
+
+// elementFinder rod.Page 与 rod.Element 共有的查找方法
+type elementFinder interface {
+	Has(selector string) (bool, *rod.Element, error)
+	Elements(selector string) (rod.Elements, error)
+}
+
+// findElement 按候选顺序查找元素，等待到任一候选出现或页面超时；同时出现时取靠前的候选
+func findElement(page *rod.Page, name string, args ...any) (*rod.Element, error) {
+	race := page.Race()
+	for _, c := range selCandidates(name, args...) {
+		race = race.Element(c)
+	}
+	el, err := race.Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "selector %s", name)
+	}
+	return el, nil
+}
+
+// findElementR 同 findElement，但只取文本匹配 jsRegex 的元素
+func findElementR(page *rod.Page, name, jsRegex string, args ...any) (*rod.Element, error) {
+	race := page.Race()
+	for _, c := range selCandidates(name, args...) {
+		race = race.ElementR(c, jsRegex)
+	}
+	el, err := race.Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "selector %s", name)
+	}
+	return el, nil
+}
+
+// mustFindElement 同 findElement，找不到时 panic
+func mustFindElement(page *rod.Page, name string, args ...any) *rod.Element {
+	el, err := findElement(page, name, args...)
+	if err != nil {
+		panic(err)
+	}
+	return el
+}
+
+// findChild 按候选顺序查找 el 的子元素，不等待
+func findChild(el *rod.Element, name string, args ...any) (*rod.Element, error) {
+	has, child, err := hasElement(el, name, args...)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, errors.Wrapf(&rod.ElementNotFoundError{}, "selector %s", name)
+	}
+	return child, nil
+}
+
+// findChildR 同 findChild，但只取文本匹配 jsRegex 的子元素
+func findChildR(el *rod.Element, name, jsRegex string, args ...any) (*rod.Element, error) {
+	for _, c := range selCandidates(name, args...) {
+		has, child, err := el.HasR(c, jsRegex)
+		if err != nil {
+			return nil, errors.Wrapf(err, "selector %s", name)
+		}
+		if has {
+			return child, nil
+		}
+	}
+	return nil, errors.Wrapf(&rod.ElementNotFoundError{}, "selector %s", name)
+}
+
+// hasElement 按候选顺序检查元素是否存在，不等待
+func hasElement(f elementFinder, name string, args ...any) (bool, *rod.Element, error) {
+	for _, c := range selCandidates(name, args...) {
+		has, el, err := f.Has(c)
+		if err != nil {
+			return false, nil, errors.Wrapf(err, "selector %s", name)
+		}
+		if has {
+			return true, el, nil
+		}
+	}
+	return false, nil, nil
+}
+
+// findElements 返回第一个有匹配的候选的全部元素，不等待；都没有匹配时返回空列表
+func findElements(f elementFinder, name string, args ...any) (rod.Elements, error) {
+	for _, c := range selCandidates(name, args...) {
+		els, err := f.Elements(c)
+		if err != nil {
+			return nil, errors.Wrapf(err, "selector %s", name)
+		}
+		if len(els) > 0 {
+			return els, nil
+		}
+	}
+	return rod.Elements{}, nil
+}
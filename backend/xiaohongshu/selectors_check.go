@@ -0,0 +1,213 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+)
+
+// SelectorPages 健康检查可以打开的页面，按检查顺序排列
+var SelectorPages = []string{"explore", "note", "search", "profile", "notifications", "im", "publish", "note_manager", "analytics"}
+
+// selectorCheckKeyword 检查搜索页时使用的关键词
+const selectorCheckKeyword = "穿搭"
+
+// 选择器检查结果
+const (
+	SelectorOK       = "ok"        // 有候选匹配
+	SelectorMissing  = "missing"   // 所有候选都没有匹配，选择器可能已失效
+	SelectorNotShown = "not_shown" // 可选选择器没有匹配，通常需要交互后才出现
+	SelectorSkipped  = "skipped"   // 带占位符，需要具体的评论 ID 等参数才能检查
+)
+
+// SelectorStatus 一个命名选择器的检查结果
+type SelectorStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Matched 第一个匹配的候选
+	Matched  string `json:"matched,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// SelectorPageReport 一个页面的检查结果，页面打不开时 Error 非空
+type SelectorPageReport struct {
+	Page      string           `json:"page"`
+	URL       string           `json:"url,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	Selectors []SelectorStatus `json:"selectors"`
+}
+
+// SelectorReport 选择器健康检查报告
+type SelectorReport struct {
+	Version   string    `json:"version"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Broken 状态为 missing 的选择器
+	Broken []string             `json:"broken"`
+	Pages  []SelectorPageReport `json:"pages"`
+}
+
+// ParseSelectorPages 解析逗号分隔的页面列表，为空时返回全部页面
+func ParseSelectorPages(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return SelectorPages, nil
+	}
+	var pages []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		known := false
+		for _, name := range SelectorPages {
+			known = known || name == p
+		}
+		if !known {
+			return nil, fmt.Errorf("未知页面 %q，可选 %s", p, strings.Join(SelectorPages, "、"))
+		}
+		pages = append(pages, p)
+	}
+	return pages, nil
+}
+
+// SelectorCheckAction 依次打开页面，检查当前选择器表中的选择器是否还能匹配
+type SelectorCheckAction struct {
+	page *rod.Page
+
+	// sample 用于打开笔记详情页和用户主页的首页笔记
+	sample *Feed
+}
+
+func NewSelectorCheckAction(page *rod.Page) *SelectorCheckAction {
+	return &SelectorCheckAction{page: page}
+}
+
+// Check 检查 pages 中的页面，某个页面打不开时记录错误并继续
+func (a *SelectorCheckAction) Check(ctx context.Context, pages []string) *SelectorReport {
+	reg := CurrentSelectors()
+	byPage := map[string][]string{}
+	for _, name := range reg.Names() {
+		p := reg.Selectors[name].Page
+		byPage[p] = append(byPage[p], name)
+	}
+
+	report := &SelectorReport{Version: reg.Version, CheckedAt: time.Now(), Broken: []string{}}
+	for _, p := range pages {
+		pr := a.checkPage(ctx, reg, p, byPage[p])
+		for _, s := range pr.Selectors {
+			if s.Status == SelectorMissing {
+				report.Broken = append(report.Broken, s.Name)
+			}
+		}
+		report.Pages = append(report.Pages, pr)
+	}
+	logrus.Infof("选择器检查完成: 版本 %s，失效 %d 个 %v", report.Version, len(report.Broken), report.Broken)
+	return report
+}
+
+func (a *SelectorCheckAction) checkPage(ctx context.Context, reg *SelectorRegistry, name string, selectors []string) SelectorPageReport {
+	pr := SelectorPageReport{Page: name, Selectors: []SelectorStatus{}}
+	page := a.page.Context(ctx).Timeout(time.Minute)
+
+	url, err := a.pageURL(page, name)
+	if err != nil {
+		pr.Error = err.Error()
+		return pr
+	}
+	pr.URL = url
+
+	logrus.Infof("检查选择器: %s (%d 个) %s", name, len(selectors), url)
+	if err := rod.Try(func() {
+		page.MustNavigate(url).MustWaitIdle().MustWaitDOMStable()
+	}); err != nil {
+		pr.Error = fmt.Sprintf("打开页面失败: %v", err)
+		return pr
+	}
+	time.Sleep(1 * time.Second)
+
+	for _, s := range selectors {
+		status, err := matchSelector(page, s, reg.Selectors[s])
+		if err != nil {
+			pr.Error = fmt.Sprintf("检查 %s 失败: %v", s, err)
+			return pr
+		}
+		pr.Selectors = append(pr.Selectors, status)
+	}
+	return pr
+}
+
+// matchSelector 按顺序找到第一个匹配的候选；写错的候选视为不匹配
+func matchSelector(page *rod.Page, name string, def SelectorDef) (SelectorStatus, error) {
+	status := SelectorStatus{Name: name, Optional: def.Optional}
+	if def.isTemplate() {
+		status.Status = SelectorSkipped
+		return status, nil
+	}
+
+	res, err := page.Eval(`(candidates) => candidates.findIndex((c) => {
+		try {
+			return document.querySelector(c) !== null;
+		} catch (e) {
+			return false;
+		}
+	})`, def.Candidates)
+	if err != nil {
+		return status, err
+	}
+
+	switch i := res.Value.Int(); {
+	case i >= 0:
+		status.Status, status.Matched = SelectorOK, def.Candidates[i]
+	case def.Optional:
+		status.Status = SelectorNotShown
+	default:
+		status.Status = SelectorMissing
+	}
+	return status, nil
+}
+
+// pageURL 页面地址；笔记详情页和用户主页取首页推荐的第一篇笔记及其作者
+func (a *SelectorCheckAction) pageURL(page *rod.Page, name string) (string, error) {
+	switch name {
+	case "explore":
+		return siteURL(pathOfExplore), nil
+	case "search":
+		return makeSearchURL(selectorCheckKeyword), nil
+	case "notifications":
+		return siteURL(pathOfNotification), nil
+	case "im":
+		return siteURL(pathOfIM), nil
+	case "publish":
+		return creatorURL(pathOfPublic), nil
+	case "note_manager":
+		return creatorURL(pathOfNoteManager), nil
+	case "analytics":
+		return creatorURL(pathOfNoteAnalytics), nil
+	}
+
+	if a.sample == nil {
+		if err := rod.Try(func() {
+			page.MustNavigate(siteURL(pathOfExplore)).MustWaitDOMStable()
+		}); err != nil {
+			return "", fmt.Errorf("打开首页失败: %w", err)
+		}
+		feeds, err := readStateFeeds(page, "feed")
+		if err != nil {
+			return "", fmt.Errorf("读取首页笔记失败: %w", err)
+		}
+		for i := range feeds {
+			if feeds[i].ModelType == "note" && feeds[i].XsecToken != "" {
+				a.sample = &feeds[i]
+				break
+			}
+		}
+		if a.sample == nil {
+			return "", fmt.Errorf("首页没有可用于检查的笔记")
+		}
+	}
+
+	if name == "profile" {
+		return makeUserProfileURL(a.sample.NoteCard.User.UserID, a.sample.XsecToken), nil
+	}
+	return makeFeedDetailURL(a.sample.ID, a.sample.XsecToken), nil
+}
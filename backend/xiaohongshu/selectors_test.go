@@ -0,0 +1,161 @@
+package xiaohongshu
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 代码中引用的选择器名称都必须在内置选择器表中
+func TestSelectorNamesRegistered(t *testing.T) {
+	ref := regexp.MustCompile(`(?:selCandidates|findElements?|findElementR|findChildR?|hasElement|mustFindElement|scrollContainer|performClick)\([^"\n]*?"([^"]+)"`)
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	reg := DefaultSelectors()
+	used := map[string]bool{}
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(f)
+		require.NoError(t, err)
+		for _, m := range ref.FindAllStringSubmatch(string(data), -1) {
+			name := m[1]
+			used[name] = true
+			assert.Contains(t, reg.Selectors, name, "%s 引用了未注册的选择器", f)
+		}
+	}
+	assert.Greater(t, len(used), 50)
+
+	for _, name := range reg.Names() {
+		assert.NotEmpty(t, reg.Selectors[name].Page, name)
+		assert.Contains(t, SelectorPages, reg.Selectors[name].Page, name)
+	}
+}
+
+func TestSelCandidates(t *testing.T) {
+	assert.Equal(t, []string{".parent-comment", ".comment-item", ".comment"}, selCandidates("comment.item"))
+	assert.Equal(t, []string{"#comment-c1"}, selCandidates("comment.by_id", "c1"))
+	assert.Equal(t, []string{"div.filter-panel div.filters:nth-child(2) div.tags:nth-child(3)"},
+		selCandidates("search.filter_option", 2, 3))
+	assert.Panics(t, func() { selCandidates("no.such") })
+}
+
+// 多个候选同时匹配时取靠前的候选，而不是文档中靠前的元素
+func TestFindElement_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+	page.MustSetDocumentContent(`<div id="root">
+		<p class="fallback">fallback</p>
+		<p class="primary">primary</p>
+		<p class="primary">primary 2</p>
+	</div>`)
+
+	restore := CurrentSelectors()
+	t.Cleanup(func() { SetSelectors(restore) })
+	r := DefaultSelectors()
+	r.Selectors["comment.item"] = SelectorDef{Page: "note", Candidates: []string{".primary", ".fallback"}}
+	SetSelectors(r)
+
+	el, err := findElement(page, "comment.item")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", el.MustText())
+
+	el, err = findElementR(page, "comment.item", "fallback|2")
+	require.NoError(t, err)
+	assert.Equal(t, "primary 2", el.MustText())
+
+	root := page.MustElement("#root")
+	el, err = findChild(root, "comment.item")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", el.MustText())
+
+	els, err := findElements(page, "comment.item")
+	require.NoError(t, err)
+	assert.Len(t, els, 2)
+
+	_, err = findChild(root, "comment.by_id", "none")
+	assert.Error(t, err)
+	has, _, err := hasElement(root, "comment.by_id", "none")
+	require.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestLoadSelectors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "selectors.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"version": "2099-01-01",
+		"selectors": {"comment.submit": {"candidates": ["button.send", "div.bottom button.submit"]}}
+	}`), 0o644))
+
+	r, err := LoadSelectors(path)
+	require.NoError(t, err)
+	assert.Equal(t, "2099-01-01", r.Version)
+	assert.Equal(t, SelectorDef{Page: "note", Candidates: []string{"button.send", "div.bottom button.submit"}}, r.Selectors["comment.submit"])
+	assert.Equal(t, DefaultSelectors().Selectors["comment.input"], r.Selectors["comment.input"], "未覆盖的条目保持内置值")
+
+	restore := CurrentSelectors()
+	SetSelectors(r)
+	assert.Equal(t, []string{"button.send", "div.bottom button.submit"}, selCandidates("comment.submit"))
+	SetSelectors(restore)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"selectors": {"comment.by_id": {"candidates": ["#comment-%s", "[data-id=\"%s\"]"]}}}`), 0o644))
+	_, err = LoadSelectors(path)
+	assert.NoError(t, err, "占位符个数与内置条目一致的覆盖可以加载")
+
+	for _, body := range []string{
+		`{"selectors": {"no.such": {"candidates": ["div"]}}}`,
+		`{"selectors": {"comment.submit": {"candidates": []}}}`,
+		`{"selectors": {"comment.submit": {"candidates": [" "]}}}`,
+		`{"selectors": {"comment.submit": {"candidates": ["button.submit-%s"]}}}`,
+		`{"selectors": {"comment.by_id": {"candidates": ["#comment"]}}}`,
+		`{"selectors": {"comment.by_id": {"candidates": ["#comment-%s", "[data-id=\"%s\"] #comment-%s"]}}}`,
+		`{"selectors": {"search.filter_option": {"candidates": ["div.filters:nth-child(%d)"]}}}`,
+		`not json`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+		_, err := LoadSelectors(path)
+		assert.Error(t, err, body)
+	}
+}
+
+func TestParseSelectorPages(t *testing.T) {
+	pages, err := ParseSelectorPages("")
+	require.NoError(t, err)
+	assert.Equal(t, SelectorPages, pages)
+
+	pages, err = ParseSelectorPages("note, publish")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"note", "publish"}, pages)
+
+	_, err = ParseSelectorPages("note,home")
+	assert.Error(t, err)
+}
+
+func TestSelectorCheck_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+
+	report := NewSelectorCheckAction(page).Check(context.Background(), []string{"explore", "note"})
+	require.Len(t, report.Pages, 2)
+	assert.Equal(t, DefaultSelectors().Version, report.Version)
+
+	statuses := map[string]SelectorStatus{}
+	for _, p := range report.Pages {
+		assert.Empty(t, p.Error, p.Page)
+		for _, s := range p.Selectors {
+			statuses[s.Name] = s
+		}
+	}
+	assert.Equal(t, SelectorOK, statuses["login.logged_in"].Status)
+	assert.Equal(t, SelectorNotShown, statuses["login.qrcode"].Status)
+	assert.Equal(t, SelectorSkipped, statuses["comment.by_id"].Status)
+	assert.Equal(t, SelectorOK, statuses["comments.total"].Status)
+	assert.Equal(t, SelectorMissing, statuses["note.scroller"].Status)
+	assert.Contains(t, report.Broken, "note.scroller")
+}
@@ -0,0 +1,42 @@
+// 替身页面的公共脚本：渲染卡片，滚动时按页请求接口（模拟网页版的懒加载）
+
+function renderCards(container, items, className, text) {
+	for (const item of items) {
+		const el = document.createElement("section");
+		el.className = className;
+		el.style.height = "420px";
+		el.textContent = text(item);
+		container.appendChild(el);
+	}
+}
+
+// loadOnScroll 每次滚动请求下一页，从第 first 页到第 last 页；onData 接收响应中的 data
+function loadOnScroll(api, first, last, onData) {
+	let next = first;
+	let loading = false;
+	window.addEventListener("scroll", async () => {
+		if (loading || next > last) {
+			return;
+		}
+		loading = true;
+		const resp = await fetch(api + "?page=" + next++);
+		onData((await resp.json()).data);
+		loading = false;
+	});
+}
+
+// toStateFeed 把接口中的下划线字段转换为 __INITIAL_STATE__ 中的驼峰字段
+function toStateFeed(item) {
+	const card = item.note_card || {};
+	return {
+		id: item.id,
+		xsecToken: item.xsec_token,
+		modelType: item.model_type,
+		noteCard: {
+			type: card.type,
+			displayTitle: card.display_title,
+			user: { userId: card.user.user_id, nickname: card.user.nickname },
+			interactInfo: { likedCount: card.interact_info.liked_count },
+		},
+	};
+}
@@ -0,0 +1,56 @@
+package xiaohongshu
+
+import "sync"
+
+// 主站与创作者中心的地址，测试时通过 SetBaseURLs 指向本地的 fixture 服务
+var (
+	baseURLMu      sync.RWMutex
+	siteBaseURL    = "https://www.xiaohongshu.com"
+	creatorBaseURL = "https://creator.xiaohongshu.com"
+)
+
+// 页面路径
+const (
+	pathOfExplore          = "/explore"
+	pathOfSearch           = "/search_result"
+	pathOfIM               = "/im"
+	pathOfNotification     = "/notification"
+	pathOfPublic           = "/publish/publish?source=official"
+	pathOfNoteManager      = "/new/note-manager"
+	pathOfNoteAnalytics    = "/statistics/data-analysis"
+	pathOfAccountAnalytics = "/statistics/account"
+)
+
+// SetBaseURLs 替换主站与创作者中心的地址（不带结尾的 /），空字符串表示保持不变。
+// 返回的函数恢复原来的地址。
+func SetBaseURLs(site, creator string) (restore func()) {
+	baseURLMu.Lock()
+	defer baseURLMu.Unlock()
+
+	oldSite, oldCreator := siteBaseURL, creatorBaseURL
+	if site != "" {
+		siteBaseURL = site
+	}
+	if creator != "" {
+		creatorBaseURL = creator
+	}
+	return func() {
+		baseURLMu.Lock()
+		defer baseURLMu.Unlock()
+		siteBaseURL, creatorBaseURL = oldSite, oldCreator
+	}
+}
+
+// siteURL 主站页面地址
+func siteURL(path string) string {
+	baseURLMu.RLock()
+	defer baseURLMu.RUnlock()
+	return siteBaseURL + path
+}
+
+// creatorURL 创作者中心页面地址
+func creatorURL(path string) string {
+	baseURLMu.RLock()
+	defer baseURLMu.RUnlock()
+	return creatorBaseURL + path
+}
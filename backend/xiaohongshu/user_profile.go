@@ -18,15 +18,24 @@ func NewUserProfileAction(page *rod.Page) *UserProfileAction {
 	return &UserProfileAction{page: pp}
 }
 
-// UserProfile 获取用户基本信息及帖子
-func (u *UserProfileAction) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
+// UserProfile 获取用户基本信息及帖子，opts 为空时只返回首屏笔记
+func (u *UserProfileAction) UserProfile(ctx context.Context, userID, xsecToken string, opts ProfileNotesOptions) (*UserProfileResponse, error) {
 	page := u.page.Context(ctx)
+	rec := recordResponses(page, userPostedAPI)
+	defer rec.stop()
 
 	searchURL := makeUserProfileURL(userID, xsecToken)
 	page.MustNavigate(searchURL)
 	page.MustWaitStable()
 
-	return u.extractUserProfileData(page)
+	response, err := u.extractUserProfileData(page)
+	if err != nil {
+		return nil, err
+	}
+	if response.Feeds, err = collectProfileNotes(ctx, page, rec, response.Feeds, opts); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 // extractUserProfileData 从页面中提取用户资料数据的通用方法
@@ -50,25 +59,6 @@ func (u *UserProfileAction) extractUserProfileData(page *rod.Page) (*UserProfile
 		return nil, fmt.Errorf("user.userPageData.value not found in __INITIAL_STATE__")
 	}
 
-	// 2. 获取用户帖子：window.__INITIAL_STATE__.user.notes.value
-	notesResult := page.MustEval(`() => {
-		if (window.__INITIAL_STATE__ &&
-		    window.__INITIAL_STATE__.user &&
-		    window.__INITIAL_STATE__.user.notes) {
-			const notes = window.__INITIAL_STATE__.user.notes;
-			// 优先使用 value（getter），如果不存在则使用 _value（内部字段）
-			const data = notes.value !== undefined ? notes.value : notes._value;
-			if (data) {
-				return JSON.stringify(data);
-			}
-		}
-		return "";
-	}`).String()
-
-	if notesResult == "" {
-		return nil, fmt.Errorf("user.notes.value not found in __INITIAL_STATE__")
-	}
-
 	// 解析用户信息
 	var userPageData struct {
 		Interactions []UserInteractions `json:"interactions"`
@@ -78,30 +68,23 @@ func (u *UserProfileAction) extractUserProfileData(page *rod.Page) (*UserProfile
 		return nil, fmt.Errorf("failed to unmarshal userPageData: %w", err)
 	}
 
-	// 解析帖子数据（帖子为双重数组）
-	var notesFeeds [][]Feed
-	if err := json.Unmarshal([]byte(notesResult), &notesFeeds); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal notes: %w", err)
+	feeds, err := readProfileNotes(page)
+	if err != nil {
+		return nil, err
 	}
 
 	// 组装响应
 	response := &UserProfileResponse{
 		UserBasicInfo: userPageData.BasicInfo,
 		Interactions:  userPageData.Interactions,
-	}
-
-	// 添加用户帖子（展平双重数组）
-	for _, feeds := range notesFeeds {
-		if len(feeds) != 0 {
-			response.Feeds = append(response.Feeds, feeds...)
-		}
+		Feeds:         feeds,
 	}
 
 	return response, nil
 }
 
 func makeUserProfileURL(userID, xsecToken string) string {
-	return fmt.Sprintf("https://www.xiaohongshu.com/user/profile/%s?xsec_token=%s&xsec_source=pc_note", userID, xsecToken)
+	return siteURL(fmt.Sprintf("/user/profile/%s?xsec_token=%s&xsec_source=pc_note", userID, xsecToken))
 }
 
 func (u *UserProfileAction) GetMyProfileViaSidebar(ctx context.Context) (*UserProfileResponse, error) {
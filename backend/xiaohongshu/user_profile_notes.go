@@ -0,0 +1,211 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxProfileNotes 单次最多抓取的主页笔记数
+	MaxProfileNotes = 500
+
+	profileStagnantLimit   = 5 // 连续多少次滚动没有新笔记视为已到底
+	profileLargeScrollFrom = 2 // 停滞多少次后加大滚动幅度
+	profileOlderLimit      = 3 // 连续遇到多少篇早于 Since 的笔记后停止，置顶笔记可能较旧，不能遇到一篇就停
+)
+
+// ProfileNotesOptions 用户主页笔记抓取参数。两者都为空时只返回首屏笔记，不滚动。
+type ProfileNotesOptions struct {
+	// MaxNotes 最多返回的笔记数，0 表示 MaxProfileNotes
+	MaxNotes int
+	// Since 只返回该时间之后发布的笔记，零值表示不限
+	Since time.Time
+}
+
+func (o ProfileNotesOptions) scroll() bool {
+	return o.MaxNotes > 0 || !o.Since.IsZero()
+}
+
+func (o ProfileNotesOptions) limit() int {
+	if o.MaxNotes <= 0 || o.MaxNotes > MaxProfileNotes {
+		return MaxProfileNotes
+	}
+	return o.MaxNotes
+}
+
+// NoteIDTime 从笔记 ID 中解析发布时间。笔记 ID 为 24 位十六进制，前 8 位是秒级时间戳。
+func NoteIDTime(id string) (time.Time, bool) {
+	if len(id) != 24 {
+		return time.Time{}, false
+	}
+	raw, err := hex.DecodeString(id[:8])
+	if err != nil {
+		return time.Time{}, false
+	}
+	sec := int64(raw[0])<<24 | int64(raw[1])<<16 | int64(raw[2])<<8 | int64(raw[3])
+	return time.Unix(sec, 0), true
+}
+
+// profileNoteCollector 按页面顺序合并多次读取到的笔记，去重并应用 MaxNotes 与 Since
+type profileNoteCollector struct {
+	opts        ProfileNotesOptions
+	notes       []Feed
+	seen        map[string]bool
+	olderStreak int
+	reachedOld  bool
+}
+
+func newProfileNoteCollector(opts ProfileNotesOptions) *profileNoteCollector {
+	return &profileNoteCollector{opts: opts, seen: map[string]bool{}}
+}
+
+// add 合并一次读取的结果，返回新出现的笔记数（包括因早于 Since 被跳过的）
+func (c *profileNoteCollector) add(feeds []Feed) int {
+	added := 0
+	for _, feed := range feeds {
+		if feed.ID == "" || c.seen[feed.ID] {
+			continue
+		}
+		c.seen[feed.ID] = true
+		added++
+
+		if !c.opts.Since.IsZero() {
+			if t, ok := NoteIDTime(feed.ID); ok && t.Before(c.opts.Since) {
+				c.olderStreak++
+				if c.olderStreak >= profileOlderLimit {
+					c.reachedOld = true
+				}
+				continue
+			}
+			c.olderStreak = 0
+		}
+		if len(c.notes) < c.opts.limit() {
+			c.notes = append(c.notes, feed)
+		}
+	}
+	return added
+}
+
+// done 已凑够 MaxNotes，或已经翻到 Since 之前的笔记
+func (c *profileNoteCollector) done() bool {
+	return len(c.notes) >= c.opts.limit() || c.reachedOld
+}
+
+// collectProfileNotes 滚动用户主页的笔记列表，直到满足 opts 或不再加载新笔记。
+// rec 记录滚动时 userPostedAPI 的响应，与页面状态合并读取。
+func collectProfileNotes(ctx context.Context, page *rod.Page, rec *responseRecorder, first []Feed, opts ProfileNotesOptions) ([]Feed, error) {
+	c := newProfileNoteCollector(opts)
+	c.add(first)
+	if !opts.scroll() {
+		return c.notes, nil
+	}
+
+	var (
+		stagnant   int
+		lastTop    int
+		maxScrolls = c.opts.limit()
+		scrolls    int
+	)
+	for ; !c.done() && scrolls < maxScrolls; scrolls++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		largeMode := stagnant >= profileLargeScrollFrom
+		_, delta, top := humanScroll(page, "normal", largeMode, 1+stagnant)
+		sleepRandom(readTimeRange.min, readTimeRange.max)
+
+		feeds, err := readProfileFeeds(page, rec)
+		if err != nil {
+			return nil, err
+		}
+		if added := c.add(feeds); added > 0 {
+			logrus.Debugf("主页笔记增加 %d 篇，当前 %d 篇", added, len(c.notes))
+			stagnant = 0
+		} else if delta < minScrollDelta || top == lastTop {
+			// 没有新笔记且页面已滚不动
+			stagnant++
+			if profileHasMore(page, rec) == "false" {
+				break
+			}
+		} else {
+			stagnant++
+		}
+		lastTop = top
+
+		if stagnant >= profileStagnantLimit {
+			break
+		}
+	}
+	logrus.Infof("主页笔记抓取完成: %d 篇 (max=%d, since=%v, 滚动 %d 次, 已到 since: %v)",
+		len(c.notes), c.opts.limit(), c.opts.Since, scrolls, c.reachedOld)
+	return c.notes, nil
+}
+
+// readProfileFeeds 合并页面状态与主页笔记接口返回的笔记
+func readProfileFeeds(page *rod.Page, rec *responseRecorder) ([]Feed, error) {
+	captured, _ := readCaptured(rec, decodeUserPosted)
+	return readMerged(func() ([]Feed, error) { return readProfileNotes(page) }, captured.Items, feedID)
+}
+
+// profileHasMore 优先使用最近一次接口响应的 has_more，没有响应时读取页面状态
+func profileHasMore(page *rod.Page, rec *responseRecorder) string {
+	if captured, ok := readCaptured(rec, decodeUserPosted); ok {
+		return strconv.FormatBool(captured.HasMore)
+	}
+	return readProfileHasMore(page)
+}
+
+// readProfileNotes 读取 window.__INITIAL_STATE__.user.notes 并按顺序展平（原始数据为双重数组）
+func readProfileNotes(page *rod.Page) ([]Feed, error) {
+	result := page.MustEval(`() => {
+		if (window.__INITIAL_STATE__ &&
+		    window.__INITIAL_STATE__.user &&
+		    window.__INITIAL_STATE__.user.notes) {
+			const notes = window.__INITIAL_STATE__.user.notes;
+			// 优先使用 value（getter），如果不存在则使用 _value（内部字段）
+			const data = notes.value !== undefined ? notes.value : notes._value;
+			if (data) {
+				return JSON.stringify(data);
+			}
+		}
+		return "";
+	}`).String()
+
+	if result == "" {
+		return nil, fmt.Errorf("user.notes.value not found in __INITIAL_STATE__")
+	}
+
+	var notesFeeds [][]Feed
+	if err := json.Unmarshal([]byte(result), &notesFeeds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal notes: %w", err)
+	}
+
+	var feeds []Feed
+	for _, group := range notesFeeds {
+		feeds = append(feeds, group...)
+	}
+	return feeds, nil
+}
+
+// readProfileHasMore 读取笔记列表的 hasMore 标记，返回 "true"/"false"，取不到时为空
+func readProfileHasMore(page *rod.Page) string {
+	return page.MustEval(`() => {
+		const user = window.__INITIAL_STATE__ && window.__INITIAL_STATE__.user;
+		if (!user || !user.noteQueries) {
+			return "";
+		}
+		const queries = user.noteQueries.value !== undefined ? user.noteQueries.value : user.noteQueries._value;
+		if (!queries || !queries[0] || typeof queries[0].hasMore !== "boolean") {
+			return "";
+		}
+		return String(queries[0].hasMore);
+	}`).String()
+}
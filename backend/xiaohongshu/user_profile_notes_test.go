@@ -0,0 +1,63 @@
+package xiaohongshu
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// noteAt 生成发布时间为 t 的笔记
+func noteAt(t time.Time, n int) Feed {
+	return Feed{ID: fmt.Sprintf("%08x%016x", t.Unix(), n)}
+}
+
+func TestNoteIDTime(t *testing.T) {
+	got, ok := NoteIDTime("64f1a2b3c4d5e6f7a8b9c0d1")
+	assert.True(t, ok)
+	assert.Equal(t, int64(0x64f1a2b3), got.Unix())
+
+	for _, bad := range []string{"", "64f1a2b3", "zzzzzzzzc4d5e6f7a8b9c0d1"} {
+		_, ok := NoteIDTime(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestProfileNoteCollectorMaxNotes(t *testing.T) {
+	now := time.Now()
+	c := newProfileNoteCollector(ProfileNotesOptions{MaxNotes: 3})
+
+	first := []Feed{noteAt(now, 1), noteAt(now, 2)}
+	assert.Equal(t, 2, c.add(first))
+	assert.False(t, c.done())
+
+	// 滚动后页面状态包含之前的笔记，只合并新增的
+	assert.Equal(t, 2, c.add(append(first, noteAt(now, 3), noteAt(now, 4))))
+	assert.True(t, c.done())
+	assert.Equal(t, []Feed{noteAt(now, 1), noteAt(now, 2), noteAt(now, 3)}, c.notes)
+}
+
+func TestProfileNoteCollectorSince(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-24 * time.Hour)
+	old := now.Add(-48 * time.Hour)
+	c := newProfileNoteCollector(ProfileNotesOptions{Since: since})
+
+	// 置顶的旧笔记被跳过，但不会提前结束
+	c.add([]Feed{noteAt(old, 1), noteAt(now, 2), noteAt(now, 3)})
+	assert.False(t, c.done())
+
+	c.add([]Feed{noteAt(old, 4), noteAt(old, 5)})
+	assert.False(t, c.done())
+	c.add([]Feed{noteAt(old, 6)})
+	assert.True(t, c.done())
+	assert.Equal(t, []Feed{noteAt(now, 2), noteAt(now, 3)}, c.notes)
+}
+
+func TestProfileNotesOptionsLimit(t *testing.T) {
+	assert.False(t, ProfileNotesOptions{}.scroll())
+	assert.Equal(t, MaxProfileNotes, ProfileNotesOptions{}.limit())
+	assert.Equal(t, 10, ProfileNotesOptions{MaxNotes: 10}.limit())
+	assert.Equal(t, MaxProfileNotes, ProfileNotesOptions{MaxNotes: MaxProfileNotes + 1}.limit())
+}
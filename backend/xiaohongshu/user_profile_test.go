@@ -0,0 +1,26 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserProfile_Fixture(t *testing.T) {
+	page, _ := newFixturePage(t)
+
+	// 首屏两篇来自 __INITIAL_STATE__，滚动加载的 d03、d04 来自 user_posted 接口
+	profile, err := NewUserProfileAction(page).UserProfile(context.Background(), "5f00000000000000000000bb", "xt-bb", ProfileNotesOptions{MaxNotes: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, "测试博主", profile.UserBasicInfo.Nickname)
+	assert.Equal(t, "123456789", profile.UserBasicInfo.RedId)
+	require.Len(t, profile.Interactions, 3)
+	assert.Equal(t, "3456", profile.Interactions[1].Count)
+	assert.Equal(t, []string{
+		"65f000000000000000000d01", "65f000000000000000000d02",
+		"65f000000000000000000d03", "65f000000000000000000d04",
+	}, feedIDs(profile.Feeds))
+}